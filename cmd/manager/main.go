@@ -9,6 +9,8 @@ import (
 
 	"github.com/PolarGeospatialCenter/ceph-operator/pkg/apis"
 	"github.com/PolarGeospatialCenter/ceph-operator/pkg/controller"
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/controller/common"
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/webhook"
 	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
 	"github.com/operator-framework/operator-sdk/pkg/leader"
 	"github.com/operator-framework/operator-sdk/pkg/ready"
@@ -39,12 +41,22 @@ func main() {
 
 	printVersion()
 
-	namespace, err := k8sutil.GetWatchNamespace()
+	watchNamespace, err := k8sutil.GetWatchNamespace()
 	if err != nil {
 		log.Error(err, "failed to get watch namespace")
 		os.Exit(1)
 	}
 
+	// WATCH_NAMESPACE is a single namespace, a comma separated list of namespaces or empty to watch every
+	// namespace.  The cache can only be limited to a single namespace, lists are filtered by the controllers.
+	namespaces := common.ParseWatchNamespaces(watchNamespace)
+	namespace := ""
+	if len(namespaces) == 1 {
+		namespace = namespaces[0]
+	}
+	common.SetWatchNamespaces(namespaces)
+	log.Info("Watching namespaces", "Namespaces", namespaces)
+
 	// Get a config to talk to the apiserver
 	cfg, err := config.GetConfig()
 	if err != nil {
@@ -84,6 +96,18 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Setup admission webhooks, they can only be served from inside the cluster
+	operatorNamespace, err := k8sutil.GetOperatorNamespace()
+	if err == k8sutil.ErrNoNamespace {
+		log.Info("Not running in a cluster, skipping admission webhooks")
+	} else if err != nil {
+		log.Error(err, "")
+		os.Exit(1)
+	} else if err := webhook.AddToManager(mgr, operatorNamespace); err != nil {
+		log.Error(err, "")
+		os.Exit(1)
+	}
+
 	log.Info("Starting the Cmd.")
 
 	// Start the Cmd
@@ -0,0 +1,503 @@
+// crdgen renders the CustomResourceDefinitions in deploy/crds from the go types in
+// the api versions in pkg/apis/ceph.  Run it from the root of the repository after changing the api:
+//
+//	go run ./hack/crdgen
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	cephv1alpha1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1alpha1"
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// PrinterColumn is an additionalPrinterColumn of a CRD
+type PrinterColumn struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Format      string `json:"format,omitempty"`
+	Description string `json:"description"`
+	JSONPath    string `json:"JSONPath"`
+	Priority    int    `json:"priority"`
+}
+
+// Version is a served version of a custom resource
+type Version struct {
+	Object  interface{}
+	Storage bool
+	Columns []PrinterColumn
+}
+
+// Name returns the api version the object belongs to
+func (v Version) Name() string {
+	return path.Base(reflect.TypeOf(v.Object).PkgPath())
+}
+
+// CRD describes a custom resource served by the operator
+type CRD struct {
+	Kind     string
+	Plural   string
+	Versions []Version
+}
+
+var ageColumn = PrinterColumn{
+	Name:        "Age",
+	Type:        "date",
+	Description: "The time since the resource was created",
+	JSONPath:    ".metadata.creationTimestamp",
+}
+
+var (
+	cephClusterColumns = []PrinterColumn{
+		{Name: "State", Type: "string", Description: "The state of the cluster", JSONPath: ".status.state"},
+		{Name: "Health", Type: "string", Description: "The health reported by ceph", JSONPath: ".status.health.status"},
+		{Name: "Blocked", Type: "string", Description: "Why a health gate is holding the next transition", JSONPath: ".status.transitionBlocked.reason", Priority: 1},
+		{Name: "OsdsUp", Type: "integer", Description: "The number of osds that are up", JSONPath: ".status.health.osds.up", Priority: 1},
+		{Name: "OsdsIn", Type: "integer", Description: "The number of osds that are in", JSONPath: ".status.health.osds.in", Priority: 1},
+		{Name: "Quorum", Type: "string", Description: "The monitors in quorum", JSONPath: ".status.health.quorumMembers", Priority: 1},
+		{Name: "Fsid", Type: "string", Description: "The fsid of the cluster", JSONPath: ".spec.fsid", Priority: 1},
+		{Name: "MonCluster", Type: "string", Description: "The name of the mon cluster", JSONPath: ".status.monClusterName", Priority: 1},
+		ageColumn,
+	}
+	cephMonClusterColumns = []PrinterColumn{
+		{Name: "ClusterName", Type: "string", Description: "The name of the cluster", JSONPath: ".spec.clusterName", Priority: 1},
+		{Name: "State", Type: "string", Description: "The quorum state of the mon cluster", JSONPath: ".status.monClusterState"},
+		{Name: "Quorum", Type: "integer", Description: "The number of monitors in quorum", JSONPath: ".status.inQuorum"},
+		{Name: "Count", Type: "integer", Description: "The desired number of monitors", JSONPath: ".spec.count"},
+		{Name: "Epoch", Type: "integer", Description: "The epoch monitors are started in", JSONPath: ".status.monStartEpoch", Priority: 1},
+		ageColumn,
+	}
+	cephOsdColumns = []PrinterColumn{
+		{Name: "ClusterName", Type: "string", Description: "The name of the cluster", JSONPath: ".spec.clusterName", Priority: 3},
+		{Name: "Id", Type: "integer", Description: "The ID of the osd", JSONPath: ".spec.id"},
+		{Name: "State", Type: "string", Description: "The state of the osd", JSONPath: ".status.state"},
+		{Name: "OsdFsid", Type: "string", Description: "The fsid of the prepared osd", JSONPath: ".status.osdFsid", Priority: 1},
+		ageColumn,
+	}
+	cephDaemonClusterColumns = []PrinterColumn{
+		{Name: "ClusterName", Type: "string", Description: "The name of the cluster", JSONPath: ".spec.clusterName", Priority: 1},
+		{Name: "Type", Type: "string", Description: "The type of the daemons", JSONPath: ".spec.daemonType"},
+		{Name: "Replicas", Type: "integer", Description: "The desired number of daemons", JSONPath: ".spec.replicas"},
+		{Name: "State", Type: "string", Description: "The state of the daemon cluster", JSONPath: ".status.state"},
+		ageColumn,
+	}
+	cephDaemonColumns = []PrinterColumn{
+		{Name: "ClusterName", Type: "string", Description: "The name of the cluster", JSONPath: ".spec.clusterName", Priority: 1},
+		{Name: "Type", Type: "string", Description: "The type of the daemon", JSONPath: ".spec.daemonType"},
+		{Name: "Id", Type: "string", Description: "The ID of the daemon", JSONPath: ".spec.id"},
+		{Name: "State", Type: "string", Description: "The state of the daemon", JSONPath: ".status.state"},
+		ageColumn,
+	}
+)
+
+func cephMonColumns(initialMemberPath string) []PrinterColumn {
+	return []PrinterColumn{
+		{Name: "ClusterName", Type: "string", Description: "The name of the cluster", JSONPath: ".spec.clusterName", Priority: 1},
+		{Name: "Id", Type: "string", Description: "The ID of the monitor", JSONPath: ".spec.id"},
+		{Name: "State", Type: "string", Description: "The state of the monitor", JSONPath: ".status.monState"},
+		{Name: "PodIP", Type: "string", Description: "The IP of the monitor pod", JSONPath: ".status.podIP", Priority: 1},
+		{Name: "Initial", Type: "boolean", Description: "Whether the monitor is an initial member of the quorum", JSONPath: initialMemberPath, Priority: 1},
+		ageColumn,
+	}
+}
+
+// CRDs are the custom resources rendered into deploy/crds, each lists the storage version first
+var CRDs = []CRD{
+	{
+		Kind:   "CephCluster",
+		Plural: "cephclusters",
+		Versions: []Version{
+			{Object: cephv1beta1.CephCluster{}, Storage: true, Columns: cephClusterColumns},
+			{Object: cephv1alpha1.CephCluster{}, Columns: cephClusterColumns},
+		},
+	},
+	{
+		Kind:   "CephMonCluster",
+		Plural: "cephmonclusters",
+		Versions: []Version{
+			{Object: cephv1beta1.CephMonCluster{}, Storage: true, Columns: cephMonClusterColumns},
+			{Object: cephv1alpha1.CephMonCluster{}, Columns: cephMonClusterColumns},
+		},
+	},
+	{
+		Kind:   "CephMon",
+		Plural: "cephmons",
+		Versions: []Version{
+			{Object: cephv1beta1.CephMon{}, Storage: true, Columns: cephMonColumns(".status.initialMember")},
+			{Object: cephv1alpha1.CephMon{}, Columns: cephMonColumns(".status.initalMember")},
+		},
+	},
+	{
+		Kind:   "CephOsd",
+		Plural: "cephosds",
+		Versions: []Version{
+			{Object: cephv1beta1.CephOsd{}, Storage: true, Columns: cephOsdColumns},
+			{Object: cephv1alpha1.CephOsd{}, Columns: cephOsdColumns},
+		},
+	},
+	{
+		Kind:   "CephDaemonCluster",
+		Plural: "cephdaemonclusters",
+		Versions: []Version{
+			{Object: cephv1beta1.CephDaemonCluster{}, Storage: true, Columns: cephDaemonClusterColumns},
+			{Object: cephv1alpha1.CephDaemonCluster{}, Columns: cephDaemonClusterColumns},
+		},
+	},
+	{
+		Kind:   "CephDaemon",
+		Plural: "cephdaemons",
+		Versions: []Version{
+			{Object: cephv1beta1.CephDaemon{}, Storage: true, Columns: cephDaemonColumns},
+			{Object: cephv1alpha1.CephDaemon{}, Columns: cephDaemonColumns},
+		},
+	},
+}
+
+// Status is written along with the rest of the object, so state enums include the empty value of a
+// resource that hasn't been reconciled yet.
+var (
+	cephClusterStates = []string{
+		"",
+		string(cephv1beta1.CephClusterIdle),
+		string(cephv1beta1.CephClusterStartMons),
+		string(cephv1beta1.CephClusterStartDaemons),
+		string(cephv1beta1.CephClusterStartOsds),
+		string(cephv1beta1.CephClusterRunning),
+		string(cephv1beta1.CephClusterShutdown),
+		string(cephv1beta1.CephClusterStopDaemons),
+		string(cephv1beta1.CephClusterStopOsds),
+		string(cephv1beta1.CephClusterStopMons),
+	}
+	monClusterStates = []string{
+		"",
+		string(cephv1beta1.MonClusterIdle),
+		string(cephv1beta1.MonClusterLaunching),
+		string(cephv1beta1.MonClusterEstablishingQuorum),
+		string(cephv1beta1.MonClusterInQuorum),
+		string(cephv1beta1.MonClusterLostQuorum),
+		string(cephv1beta1.MonClusterRecovering),
+	}
+	monStates = []string{
+		"",
+		string(cephv1beta1.MonLaunchPod),
+		string(cephv1beta1.MonWaitForPodRun),
+		string(cephv1beta1.MonWaitForPodReady),
+		string(cephv1beta1.MonInQuorum),
+		string(cephv1beta1.MonError),
+		string(cephv1beta1.MonCleanup),
+		string(cephv1beta1.MonIdle),
+	}
+	cephOsdStates = []string{
+		"",
+		string(cephv1beta1.CephOsdStateIdle),
+		string(cephv1beta1.CephOsdStatePreparing),
+		string(cephv1beta1.CephOsdStatePrepareFailed),
+		string(cephv1beta1.CephOsdStatePrepared),
+	}
+	cephDaemonClusterStates = []string{
+		"",
+		string(cephv1beta1.CephDaemonClusterStateIdle),
+		string(cephv1beta1.CephDaemonClusterStateRunning),
+		string(cephv1beta1.CephDaemonClusterStateScaling),
+		string(cephv1beta1.CephDaemonClusterStateError),
+	}
+	cephDaemonStates = []string{
+		"",
+		string(cephv1beta1.CephDaemonStateIdle),
+		string(cephv1beta1.CephDaemonStateLaunching),
+		string(cephv1beta1.CephDaemonStateWaitForRun),
+		string(cephv1beta1.CephDaemonStateWaitForReady),
+		string(cephv1beta1.CephDaemonStateReady),
+		string(cephv1beta1.CephDaemonStateError),
+		string(cephv1beta1.CephDaemonStateCleanup),
+	}
+	cephDaemonTypes = []string{
+		string(cephv1beta1.CephDaemonTypeMgr),
+		string(cephv1beta1.CephDaemonTypeMds),
+		string(cephv1beta1.CephDaemonTypeRgw),
+		string(cephv1beta1.CephDaemonTypeOsd),
+		string(cephv1beta1.CephDaemonTypeMon),
+	}
+	healthGates = []string{
+		string(cephv1beta1.HealthGateMgrAvailable),
+		string(cephv1beta1.HealthGatePgsActive),
+		string(cephv1beta1.HealthGateNoDegradedObjects),
+	}
+	antiAffinityTypes = []string{
+		"",
+		string(cephv1beta1.AntiAffinityRequired),
+		string(cephv1beta1.AntiAffinityPreferred),
+	}
+	reclaimPolicies = []string{
+		"",
+		string(cephv1beta1.ReclaimPolicyRetain),
+		string(cephv1beta1.ReclaimPolicyDelete),
+	}
+	networkProviders = []string{
+		string(cephv1beta1.NetworkProviderPod),
+		string(cephv1beta1.NetworkProviderHost),
+		string(cephv1beta1.NetworkProviderMultus),
+	}
+)
+
+// Enums lists the allowed values of string types
+var Enums = map[reflect.Type][]string{
+	reflect.TypeOf(cephv1beta1.CephClusterState("")):        cephClusterStates,
+	reflect.TypeOf(cephv1alpha1.CephClusterState("")):       cephClusterStates,
+	reflect.TypeOf(cephv1beta1.MonClusterState("")):         monClusterStates,
+	reflect.TypeOf(cephv1alpha1.MonClusterState("")):        monClusterStates,
+	reflect.TypeOf(cephv1beta1.MonState("")):                monStates,
+	reflect.TypeOf(cephv1alpha1.MonState("")):               monStates,
+	reflect.TypeOf(cephv1beta1.CephOsdState("")):            cephOsdStates,
+	reflect.TypeOf(cephv1alpha1.CephOsdState("")):           cephOsdStates,
+	reflect.TypeOf(cephv1beta1.CephDaemonClusterState("")):  cephDaemonClusterStates,
+	reflect.TypeOf(cephv1alpha1.CephDaemonClusterState("")): cephDaemonClusterStates,
+	reflect.TypeOf(cephv1beta1.CephDaemonState("")):         cephDaemonStates,
+	reflect.TypeOf(cephv1alpha1.CephDaemonState("")):        cephDaemonStates,
+	reflect.TypeOf(cephv1beta1.CephDaemonType("")):          cephDaemonTypes,
+	reflect.TypeOf(cephv1alpha1.CephDaemonType("")):         cephDaemonTypes,
+	reflect.TypeOf(cephv1beta1.AntiAffinityType("")):        antiAffinityTypes,
+	reflect.TypeOf(cephv1alpha1.AntiAffinityType("")):       antiAffinityTypes,
+	reflect.TypeOf(cephv1beta1.NetworkProvider("")):         networkProviders,
+	reflect.TypeOf(cephv1alpha1.NetworkProvider("")):        networkProviders,
+	reflect.TypeOf(cephv1beta1.ReclaimPolicy("")):           reclaimPolicies,
+	reflect.TypeOf(cephv1beta1.HealthGate("")):              healthGates,
+}
+
+// Schema is an openAPIV3Schema, kept as a map so fields that aren't set are left out
+type Schema map[string]interface{}
+
+var (
+	timeType     = reflect.TypeOf(metav1.Time{})
+	durationType = reflect.TypeOf(metav1.Duration{})
+	ipType       = reflect.TypeOf(net.IP{})
+	quantityType = reflect.TypeOf(resource.Quantity{})
+	intStrType   = reflect.TypeOf(intstr.IntOrString{})
+)
+
+// schemaFor returns the schema of values of type t, as they are encoded to json.  Legacy schemas leave out the
+// nullable and x-kubernetes-int-or-string extensions, which apiservers before 1.15 reject.
+func schemaFor(t reflect.Type, legacy bool) Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		if legacy {
+			// Unset times are encoded as null, which a typed schema rejects
+			return Schema{"format": "date-time"}
+		}
+		return Schema{"type": "string", "format": "date-time", "nullable": true}
+	case durationType, ipType:
+		return Schema{"type": "string"}
+	case quantityType, intStrType:
+		if legacy {
+			return Schema{"anyOf": []Schema{{"type": "integer"}, {"type": "string"}}}
+		}
+		return Schema{"x-kubernetes-int-or-string": true}
+	}
+
+	if values, ok := Enums[t]; ok {
+		return Schema{"type": "string", "enum": values}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if legacy {
+			// Nil slices and maps are encoded as null, so they aren't typed
+			return Schema{"items": schemaFor(t.Elem(), legacy)}
+		}
+		return Schema{"type": "array", "items": schemaFor(t.Elem(), legacy), "nullable": true}
+	case reflect.Map:
+		if legacy {
+			return Schema{"additionalProperties": schemaFor(t.Elem(), legacy)}
+		}
+		return Schema{"type": "object", "additionalProperties": schemaFor(t.Elem(), legacy), "nullable": true}
+	case reflect.Struct:
+		properties := map[string]interface{}{}
+		addProperties(t, properties, legacy)
+		return Schema{"type": "object", "properties": properties}
+	}
+	panic(fmt.Sprintf("unable to generate a schema for %s", t))
+}
+
+// addProperties adds the json fields of struct t to properties, flattening inlined structs
+func addProperties(t reflect.Type, properties map[string]interface{}, legacy bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		tag := strings.Split(field.Tag.Get("json"), ",")
+		name := tag[0]
+		if name == "-" {
+			continue
+		}
+
+		inline := field.Anonymous && name == ""
+		for _, opt := range tag[1:] {
+			if opt == "inline" {
+				inline = true
+			}
+		}
+		if inline {
+			addProperties(field.Type, properties, legacy)
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = schemaFor(field.Type, legacy)
+	}
+}
+
+// objectSchema returns the openAPIV3Schema of a custom resource
+func objectSchema(obj interface{}, legacy bool) Schema {
+	t := reflect.TypeOf(obj)
+	spec, _ := t.FieldByName("Spec")
+	status, _ := t.FieldByName("Status")
+
+	return Schema{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"apiVersion": Schema{"type": "string"},
+			"kind":       Schema{"type": "string"},
+			"metadata":   Schema{"type": "object"},
+			"spec":       schemaFor(spec.Type, legacy),
+			"status":     schemaFor(status.Type, legacy),
+		},
+	}
+}
+
+// Render returns the CustomResourceDefinition yaml for crd, for apiservers of at least Kubernetes 1.15
+func (crd CRD) Render() ([]byte, error) {
+	versions := []interface{}{}
+	for _, v := range crd.Versions {
+		versions = append(versions, map[string]interface{}{
+			"name":                     v.Name(),
+			"served":                   true,
+			"storage":                  v.Storage,
+			"additionalPrinterColumns": v.Columns,
+			"schema": map[string]interface{}{
+				"openAPIV3Schema": objectSchema(v.Object, false),
+			},
+		})
+	}
+
+	spec := crd.spec()
+	spec["versions"] = versions
+	spec["preserveUnknownFields"] = false
+	// The operator fills in the namespace of the webhook service and its CA when it starts
+	spec["conversion"] = map[string]interface{}{
+		"strategy": "Webhook",
+		"webhookClientConfig": map[string]interface{}{
+			"service": map[string]interface{}{
+				"namespace": "default",
+				"name":      "ceph-operator-webhook",
+				"path":      "/convert",
+			},
+		},
+	}
+	return crd.marshal(spec)
+}
+
+// RenderLegacy returns the CustomResourceDefinition yaml for crd, for apiservers from Kubernetes 1.12 to 1.14.
+// Those can't convert between versions or hold a schema per version, so only the storage version is served.
+func (crd CRD) RenderLegacy() ([]byte, error) {
+	storage := crd.Versions[0]
+
+	spec := crd.spec()
+	spec["versions"] = []interface{}{
+		map[string]interface{}{
+			"name":    storage.Name(),
+			"served":  true,
+			"storage": true,
+		},
+	}
+	spec["additionalPrinterColumns"] = storage.Columns
+	spec["validation"] = map[string]interface{}{
+		"openAPIV3Schema": objectSchema(storage.Object, true),
+	}
+	return crd.marshal(spec)
+}
+
+func (crd CRD) spec() map[string]interface{} {
+	return map[string]interface{}{
+		"group": cephv1beta1.SchemeGroupVersion.Group,
+		"names": map[string]interface{}{
+			"kind":     crd.Kind,
+			"listKind": crd.Kind + "List",
+			"plural":   crd.Plural,
+			"singular": strings.ToLower(crd.Kind),
+		},
+		"scope": "Namespaced",
+	}
+}
+
+func (crd CRD) marshal(spec map[string]interface{}) ([]byte, error) {
+	definition := map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1beta1",
+		"kind":       "CustomResourceDefinition",
+		"metadata": map[string]interface{}{
+			"name": fmt.Sprintf("%s.%s", crd.Plural, cephv1beta1.SchemeGroupVersion.Group),
+		},
+		"spec": spec,
+	}
+	return yaml.Marshal(definition)
+}
+
+// Filename returns the name of the file the crd is written to
+func (crd CRD) Filename() string {
+	return fmt.Sprintf("ceph_%s_%s_crd.yaml", crd.Versions[0].Name(), strings.ToLower(crd.Kind))
+}
+
+// LegacyDir is the directory under the crd directory the crds for apiservers before Kubernetes 1.15 are written to
+const LegacyDir = "k8s-1.12"
+
+func main() {
+	dir := flag.String("dir", "deploy/crds", "directory the crds are written to")
+	flag.Parse()
+
+	for _, crd := range CRDs {
+		for path, render := range map[string]func() ([]byte, error){
+			filepath.Join(*dir, crd.Filename()):            crd.Render,
+			filepath.Join(*dir, LegacyDir, crd.Filename()): crd.RenderLegacy,
+		} {
+			data, err := render()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "unable to render %s: %v\n", crd.Kind, err)
+				os.Exit(1)
+			}
+
+			err = ioutil.WriteFile(path, data, 0644)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "unable to write %s: %v\n", crd.Kind, err)
+				os.Exit(1)
+			}
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCRDsInSync(t *testing.T) {
+	for _, crd := range CRDs {
+		for path, render := range map[string]func() ([]byte, error){
+			crd.Filename():                           crd.Render,
+			filepath.Join(LegacyDir, crd.Filename()): crd.RenderLegacy,
+		} {
+			expected, err := render()
+			if err != nil {
+				t.Errorf("unable to render %s: %v", crd.Kind, err)
+				continue
+			}
+
+			actual, err := ioutil.ReadFile(filepath.Join("..", "..", "deploy", "crds", path))
+			if err != nil {
+				t.Errorf("unable to read %s: %v", path, err)
+				continue
+			}
+
+			if string(actual) != string(expected) {
+				t.Errorf("%s is out of date with the go types, run 'go run ./hack/crdgen'", path)
+			}
+		}
+	}
+}
+
+func TestLegacyCRDsHaveNoExtensions(t *testing.T) {
+	for _, crd := range CRDs {
+		data, err := crd.RenderLegacy()
+		if err != nil {
+			t.Fatalf("unable to render %s: %v", crd.Kind, err)
+		}
+
+		for _, field := range []string{"nullable", "x-kubernetes-int-or-string", "preserveUnknownFields", "conversion"} {
+			if strings.Contains(string(data), field+":") {
+				t.Errorf("legacy %s crd contains %s, which apiservers before 1.15 reject", crd.Kind, field)
+			}
+		}
+	}
+}
@@ -24,6 +24,7 @@ type CephDaemonClusterSpec struct {
 	DaemonType            CephDaemonType `json:"daemonType"`
 	Disabled              bool           `json:"disabled"`
 	Replicas              int            `json:"replicas"`
+	Network               NetworkSpec    `json:"network"`
 }
 
 // CephDaemonClusterStatus defines the observed state of CephDaemonCluster
@@ -55,54 +56,3 @@ type CephDaemonClusterList struct {
 func init() {
 	SchemeBuilder.Register(&CephDaemonCluster{}, &CephDaemonClusterList{})
 }
-
-func NewCephDaemonCluster(t CephDaemonType) *CephDaemonCluster {
-	return &CephDaemonCluster{Spec: CephDaemonClusterSpec{
-		DaemonType: t,
-	}}
-}
-
-func (d *CephDaemonCluster) GetState() CephDaemonClusterState {
-	return d.Status.State
-}
-
-func (d *CephDaemonCluster) SetState(s CephDaemonClusterState) {
-	d.Status.State = s
-}
-
-func (c *CephDaemonCluster) GetDaemonType() CephDaemonType {
-	return c.Spec.DaemonType
-}
-
-func (c *CephDaemonCluster) SetCephClusterName(name string) {
-	c.Spec.ClusterName = name
-}
-
-func (c *CephDaemonCluster) GetCephClusterName() string {
-	return c.Spec.ClusterName
-}
-
-func (c *CephDaemonCluster) SetImage(image ImageSpec) {
-	c.Spec.Image = image
-}
-
-func (c *CephDaemonCluster) GetImage() ImageSpec {
-	return c.Spec.Image
-}
-
-func (c *CephDaemonCluster) SetCephConfConfigMapName(name string) {
-	c.Spec.CephConfConfigMapName = name
-}
-
-func (c *CephDaemonCluster) GetCephConfConfigMapName() string {
-	return c.Spec.CephConfConfigMapName
-}
-
-func (c *CephDaemonClusterList) AllInState(state CephDaemonClusterState) bool {
-	for _, e := range c.Items {
-		if e.GetState() != state {
-			return false
-		}
-	}
-	return true
-}
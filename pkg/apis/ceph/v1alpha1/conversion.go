@@ -0,0 +1,541 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ConversionDataAnnotation holds the v1beta1 spec and status of an object served as v1alpha1, so fields
+// that can't be represented in v1alpha1 survive a round trip through this version.
+const ConversionDataAnnotation = "ceph.k8s.pgc.umn.edu/v1beta1-conversion-data"
+
+type conversionData struct {
+	Spec   interface{} `json:"spec"`
+	Status interface{} `json:"status"`
+}
+
+func saveConversionData(meta *metav1.ObjectMeta, spec, status interface{}) error {
+	data, err := json.Marshal(conversionData{Spec: spec, Status: status})
+	if err != nil {
+		return err
+	}
+
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+	meta.Annotations[ConversionDataAnnotation] = string(data)
+	return nil
+}
+
+// restoreConversionData removes the conversion data annotation from meta, decoding it into spec and status
+func restoreConversionData(meta *metav1.ObjectMeta, spec, status interface{}) error {
+	data, ok := meta.Annotations[ConversionDataAnnotation]
+	if !ok {
+		return nil
+	}
+
+	delete(meta.Annotations, ConversionDataAnnotation)
+	if len(meta.Annotations) == 0 {
+		meta.Annotations = nil
+	}
+
+	return json.Unmarshal([]byte(data), &conversionData{Spec: spec, Status: status})
+}
+
+func wrongHub(hub runtime.Object) error {
+	return fmt.Errorf("unable to convert to %T", hub)
+}
+
+func convertPvSelectorTo(selector string) (*metav1.LabelSelector, error) {
+	if selector == "" {
+		return nil, nil
+	}
+	return metav1.ParseToLabelSelector(selector)
+}
+
+func convertPvSelectorFrom(selector *metav1.LabelSelector) (string, error) {
+	if selector == nil {
+		return "", nil
+	}
+
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return "", err
+	}
+	return s.String(), nil
+}
+
+func convertIPTo(ip net.IP) string {
+	if ip == nil {
+		return ""
+	}
+	return ip.String()
+}
+
+func convertIPFrom(ip string) net.IP {
+	if ip == "" {
+		return nil
+	}
+	return net.ParseIP(ip)
+}
+
+func convertImageTo(in ImageSpec, out *v1beta1.ImageSpec) {
+	out.Registry = in.Registry
+	out.Tag = in.Tag
+}
+
+func convertImageFrom(in v1beta1.ImageSpec, out *ImageSpec) {
+	out.Registry = in.Registry
+	out.Tag = in.Tag
+}
+
+func convertPlacementTo(in PlacementSpec, out *v1beta1.PlacementSpec) {
+	out.AntiAffinity = v1beta1.AntiAffinityType(in.AntiAffinity)
+	out.TopologyKey = in.TopologyKey
+	out.NodeSelector = in.NodeSelector
+	out.Tolerations = in.Tolerations
+	out.AllowUnsafe = in.AllowUnsafe
+}
+
+func convertPlacementFrom(in v1beta1.PlacementSpec, out *PlacementSpec) {
+	out.AntiAffinity = AntiAffinityType(in.AntiAffinity)
+	out.TopologyKey = in.TopologyKey
+	out.NodeSelector = in.NodeSelector
+	out.Tolerations = in.Tolerations
+	out.AllowUnsafe = in.AllowUnsafe
+}
+
+func convertMsgr2To(in Msgr2Spec, out *v1beta1.Msgr2Spec) {
+	out.Require = in.Require
+	out.SecureMode = in.SecureMode
+}
+
+func convertMsgr2From(in v1beta1.Msgr2Spec, out *Msgr2Spec) {
+	out.Require = in.Require
+	out.SecureMode = in.SecureMode
+}
+
+func convertNetworkTo(in NetworkSpec, out *v1beta1.NetworkSpec) {
+	out.Provider = v1beta1.NetworkProvider(in.Provider)
+	out.PublicNetwork = in.PublicNetwork
+	out.ClusterNetwork = in.ClusterNetwork
+	out.PublicNetworkAttachment = in.PublicNetworkAttachment
+	out.ClusterNetworkAttachment = in.ClusterNetworkAttachment
+}
+
+func convertNetworkFrom(in v1beta1.NetworkSpec, out *NetworkSpec) {
+	out.Provider = NetworkProvider(in.Provider)
+	out.PublicNetwork = in.PublicNetwork
+	out.ClusterNetwork = in.ClusterNetwork
+	out.PublicNetworkAttachment = in.PublicNetworkAttachment
+	out.ClusterNetworkAttachment = in.ClusterNetworkAttachment
+}
+
+func convertMonBackupTo(in *MonBackupSpec, out **v1beta1.MonBackupSpec) {
+	if in == nil {
+		*out = nil
+		return
+	}
+	if *out == nil {
+		*out = &v1beta1.MonBackupSpec{}
+	}
+
+	backup := *out
+	backup.Interval = in.Interval
+	backup.Retain = in.Retain
+	backup.Destination.PersistentVolumeClaim = in.Destination.PersistentVolumeClaim
+	backup.Destination.S3 = (*v1beta1.S3BackupDestination)(in.Destination.S3)
+}
+
+func convertMonBackupFrom(in *v1beta1.MonBackupSpec, out **MonBackupSpec) {
+	if in == nil {
+		*out = nil
+		return
+	}
+
+	*out = &MonBackupSpec{
+		Interval: in.Interval,
+		Retain:   in.Retain,
+		Destination: MonBackupDestination{
+			PersistentVolumeClaim: in.Destination.PersistentVolumeClaim,
+			S3:                    (*S3BackupDestination)(in.Destination.S3),
+		},
+	}
+}
+
+func convertMonBackupRecordsTo(in []MonBackupRecord) []v1beta1.MonBackupRecord {
+	if in == nil {
+		return nil
+	}
+
+	out := make([]v1beta1.MonBackupRecord, 0, len(in))
+	for _, record := range in {
+		out = append(out, v1beta1.MonBackupRecord(record))
+	}
+	return out
+}
+
+func convertMonBackupRecordsFrom(in []v1beta1.MonBackupRecord) []MonBackupRecord {
+	if in == nil {
+		return nil
+	}
+
+	out := make([]MonBackupRecord, 0, len(in))
+	for _, record := range in {
+		out = append(out, MonBackupRecord(record))
+	}
+	return out
+}
+
+// ConvertTo converts this CephCluster to the hub version
+func (src *CephCluster) ConvertTo(hub runtime.Object) error {
+	dst, ok := hub.(*v1beta1.CephCluster)
+	if !ok {
+		return wrongHub(hub)
+	}
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	err := restoreConversionData(&dst.ObjectMeta, &dst.Spec, &dst.Status)
+	if err != nil {
+		return err
+	}
+
+	dst.Spec.Disabled = src.Spec.Disabled
+	dst.Spec.Config = src.Spec.Config
+	dst.Spec.Fsid = src.Spec.Fsid
+	dst.Spec.MonServiceName = src.Spec.MonServiceName
+	dst.Spec.ClusterDomain = src.Spec.ClusterDomain
+	convertImageTo(src.Spec.MonImage, &dst.Spec.MonImage)
+	convertImageTo(src.Spec.OsdImage, &dst.Spec.OsdImage)
+	convertImageTo(src.Spec.MgrImage, &dst.Spec.MgrImage)
+	convertImageTo(src.Spec.MdsImage, &dst.Spec.MdsImage)
+	convertPlacementTo(src.Spec.MonPlacement, &dst.Spec.MonPlacement)
+	convertMsgr2To(src.Spec.Msgr2, &dst.Spec.Msgr2)
+	convertNetworkTo(src.Spec.Network, &dst.Spec.Network)
+	dst.Spec.ClientConfigNamespaces = src.Spec.ClientConfigNamespaces
+
+	dst.Status.MonClusterName = src.Status.MonClusterName
+	dst.Status.State = v1beta1.CephClusterState(src.Status.State)
+	dst.Status.ClientConfigNamespaces = src.Status.ClientConfigNamespaces
+	return nil
+}
+
+// ConvertFrom converts the hub version to this CephCluster
+func (dst *CephCluster) ConvertFrom(hub runtime.Object) error {
+	src, ok := hub.(*v1beta1.CephCluster)
+	if !ok {
+		return wrongHub(hub)
+	}
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+
+	dst.Spec.Disabled = src.Spec.Disabled
+	dst.Spec.Config = src.Spec.Config
+	dst.Spec.Fsid = src.Spec.Fsid
+	dst.Spec.MonServiceName = src.Spec.MonServiceName
+	dst.Spec.ClusterDomain = src.Spec.ClusterDomain
+	convertImageFrom(src.Spec.MonImage, &dst.Spec.MonImage)
+	convertImageFrom(src.Spec.OsdImage, &dst.Spec.OsdImage)
+	convertImageFrom(src.Spec.MgrImage, &dst.Spec.MgrImage)
+	convertImageFrom(src.Spec.MdsImage, &dst.Spec.MdsImage)
+	convertPlacementFrom(src.Spec.MonPlacement, &dst.Spec.MonPlacement)
+	convertMsgr2From(src.Spec.Msgr2, &dst.Spec.Msgr2)
+	convertNetworkFrom(src.Spec.Network, &dst.Spec.Network)
+	dst.Spec.ClientConfigNamespaces = src.Spec.ClientConfigNamespaces
+
+	dst.Status.MonClusterName = src.Status.MonClusterName
+	dst.Status.State = CephClusterState(src.Status.State)
+	dst.Status.ClientConfigNamespaces = src.Status.ClientConfigNamespaces
+
+	return saveConversionData(&dst.ObjectMeta, src.Spec, src.Status)
+}
+
+// ConvertTo converts this CephMonCluster to the hub version
+func (src *CephMonCluster) ConvertTo(hub runtime.Object) error {
+	dst, ok := hub.(*v1beta1.CephMonCluster)
+	if !ok {
+		return wrongHub(hub)
+	}
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	err := restoreConversionData(&dst.ObjectMeta, &dst.Spec, &dst.Status)
+	if err != nil {
+		return err
+	}
+
+	dst.Spec.PvSelector, err = convertPvSelectorTo(src.Spec.PvSelectorString)
+	if err != nil {
+		return err
+	}
+
+	dst.Spec.ClusterName = src.Spec.ClusterName
+	convertImageTo(src.Spec.Image, &dst.Spec.Image)
+	dst.Spec.CephConfConfigMapName = src.Spec.CephConfConfigMapName
+	convertPlacementTo(src.Spec.Placement, &dst.Spec.Placement)
+	dst.Spec.Count = src.Spec.Count
+	dst.Spec.FailureTimeout = src.Spec.FailureTimeout
+	dst.Spec.Recovery = (*v1beta1.MonRecoverySpec)(src.Spec.Recovery)
+	convertMonBackupTo(src.Spec.Backup, &dst.Spec.Backup)
+	convertMsgr2To(src.Spec.Msgr2, &dst.Spec.Msgr2)
+	convertNetworkTo(src.Spec.Network, &dst.Spec.Network)
+
+	dst.Status.StartEpoch = src.Status.StartEpoch
+	dst.Status.State = v1beta1.MonClusterState(src.Status.State)
+	dst.Status.InQuorum = src.Status.InQuorum
+	dst.Status.LastRecovery = (*v1beta1.MonRecoveryStatus)(src.Status.LastRecovery)
+	dst.Status.LastBackupTime = src.Status.LastBackupTime
+	dst.Status.ActiveBackup = (*v1beta1.MonActiveBackup)(src.Status.ActiveBackup)
+	dst.Status.Backups = convertMonBackupRecordsTo(src.Status.Backups)
+	return nil
+}
+
+// ConvertFrom converts the hub version to this CephMonCluster
+func (dst *CephMonCluster) ConvertFrom(hub runtime.Object) error {
+	src, ok := hub.(*v1beta1.CephMonCluster)
+	if !ok {
+		return wrongHub(hub)
+	}
+
+	var err error
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Spec.PvSelectorString, err = convertPvSelectorFrom(src.Spec.PvSelector)
+	if err != nil {
+		return err
+	}
+
+	dst.Spec.ClusterName = src.Spec.ClusterName
+	convertImageFrom(src.Spec.Image, &dst.Spec.Image)
+	dst.Spec.CephConfConfigMapName = src.Spec.CephConfConfigMapName
+	convertPlacementFrom(src.Spec.Placement, &dst.Spec.Placement)
+	dst.Spec.Count = src.Spec.Count
+	dst.Spec.FailureTimeout = src.Spec.FailureTimeout
+	dst.Spec.Recovery = (*MonRecoverySpec)(src.Spec.Recovery)
+	convertMonBackupFrom(src.Spec.Backup, &dst.Spec.Backup)
+	convertMsgr2From(src.Spec.Msgr2, &dst.Spec.Msgr2)
+	convertNetworkFrom(src.Spec.Network, &dst.Spec.Network)
+
+	dst.Status.StartEpoch = src.Status.StartEpoch
+	dst.Status.State = MonClusterState(src.Status.State)
+	dst.Status.InQuorum = src.Status.InQuorum
+	dst.Status.LastRecovery = (*MonRecoveryStatus)(src.Status.LastRecovery)
+	dst.Status.LastBackupTime = src.Status.LastBackupTime
+	dst.Status.ActiveBackup = (*MonActiveBackup)(src.Status.ActiveBackup)
+	dst.Status.Backups = convertMonBackupRecordsFrom(src.Status.Backups)
+
+	return saveConversionData(&dst.ObjectMeta, src.Spec, src.Status)
+}
+
+// ConvertTo converts this CephMon to the hub version
+func (src *CephMon) ConvertTo(hub runtime.Object) error {
+	dst, ok := hub.(*v1beta1.CephMon)
+	if !ok {
+		return wrongHub(hub)
+	}
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	err := restoreConversionData(&dst.ObjectMeta, &dst.Spec, &dst.Status)
+	if err != nil {
+		return err
+	}
+
+	dst.Spec.PvSelector, err = convertPvSelectorTo(src.Spec.PvSelectorString)
+	if err != nil {
+		return err
+	}
+
+	dst.Spec.ClusterName = src.Spec.ClusterName
+	dst.Spec.ID = src.Spec.ID
+	dst.Spec.Disabled = src.Spec.Disabled
+	dst.Spec.Port = src.Spec.Port
+	dst.Spec.V2Port = src.Spec.V2Port
+
+	dst.Status.StartEpoch = src.Status.StartEpoch
+	dst.Status.State = v1beta1.MonState(src.Status.State)
+	dst.Status.PodIP = convertIPTo(src.Status.PodIP)
+	dst.Status.ServiceIP = convertIPTo(src.Status.ServiceIP)
+	dst.Status.InitialMember = src.Status.InitalMember
+	dst.Status.OutOfQuorumSince = src.Status.OutOfQuorumSince
+	return nil
+}
+
+// ConvertFrom converts the hub version to this CephMon
+func (dst *CephMon) ConvertFrom(hub runtime.Object) error {
+	src, ok := hub.(*v1beta1.CephMon)
+	if !ok {
+		return wrongHub(hub)
+	}
+
+	var err error
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Spec.PvSelectorString, err = convertPvSelectorFrom(src.Spec.PvSelector)
+	if err != nil {
+		return err
+	}
+
+	dst.Spec.ClusterName = src.Spec.ClusterName
+	dst.Spec.ID = src.Spec.ID
+	dst.Spec.Disabled = src.Spec.Disabled
+	dst.Spec.Port = src.Spec.Port
+	dst.Spec.V2Port = src.Spec.V2Port
+
+	dst.Status.StartEpoch = src.Status.StartEpoch
+	dst.Status.State = MonState(src.Status.State)
+	dst.Status.PodIP = convertIPFrom(src.Status.PodIP)
+	dst.Status.ServiceIP = convertIPFrom(src.Status.ServiceIP)
+	dst.Status.InitalMember = src.Status.InitialMember
+	dst.Status.OutOfQuorumSince = src.Status.OutOfQuorumSince
+
+	return saveConversionData(&dst.ObjectMeta, src.Spec, src.Status)
+}
+
+// ConvertTo converts this CephOsd to the hub version
+func (src *CephOsd) ConvertTo(hub runtime.Object) error {
+	dst, ok := hub.(*v1beta1.CephOsd)
+	if !ok {
+		return wrongHub(hub)
+	}
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	err := restoreConversionData(&dst.ObjectMeta, &dst.Spec, &dst.Status)
+	if err != nil {
+		return err
+	}
+
+	dst.Spec.PvSelector, err = convertPvSelectorTo(src.Spec.PvSelectorString)
+	if err != nil {
+		return err
+	}
+
+	dst.Spec.ID = src.Spec.ID
+	dst.Spec.ClusterName = src.Spec.ClusterName
+	dst.Spec.Disabled = src.Spec.Disabled
+
+	dst.Status.State = v1beta1.CephOsdState(src.Status.State)
+	dst.Status.OsdFsid = src.Status.OsdFsid
+	dst.Status.OsdID = src.Status.OsdID
+	return nil
+}
+
+// ConvertFrom converts the hub version to this CephOsd
+func (dst *CephOsd) ConvertFrom(hub runtime.Object) error {
+	src, ok := hub.(*v1beta1.CephOsd)
+	if !ok {
+		return wrongHub(hub)
+	}
+
+	var err error
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Spec.PvSelectorString, err = convertPvSelectorFrom(src.Spec.PvSelector)
+	if err != nil {
+		return err
+	}
+
+	dst.Spec.ID = src.Spec.ID
+	dst.Spec.ClusterName = src.Spec.ClusterName
+	dst.Spec.Disabled = src.Spec.Disabled
+
+	dst.Status.State = CephOsdState(src.Status.State)
+	dst.Status.OsdFsid = src.Status.OsdFsid
+	dst.Status.OsdID = src.Status.OsdID
+
+	return saveConversionData(&dst.ObjectMeta, src.Spec, src.Status)
+}
+
+// ConvertTo converts this CephDaemonCluster to the hub version
+func (src *CephDaemonCluster) ConvertTo(hub runtime.Object) error {
+	dst, ok := hub.(*v1beta1.CephDaemonCluster)
+	if !ok {
+		return wrongHub(hub)
+	}
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	err := restoreConversionData(&dst.ObjectMeta, &dst.Spec, &dst.Status)
+	if err != nil {
+		return err
+	}
+
+	dst.Spec.ClusterName = src.Spec.ClusterName
+	convertImageTo(src.Spec.Image, &dst.Spec.Image)
+	dst.Spec.CephConfConfigMapName = src.Spec.CephConfConfigMapName
+	dst.Spec.DaemonType = v1beta1.CephDaemonType(src.Spec.DaemonType)
+	dst.Spec.Disabled = src.Spec.Disabled
+	dst.Spec.Replicas = src.Spec.Replicas
+	convertNetworkTo(src.Spec.Network, &dst.Spec.Network)
+
+	dst.Status.State = v1beta1.CephDaemonClusterState(src.Status.State)
+	return nil
+}
+
+// ConvertFrom converts the hub version to this CephDaemonCluster
+func (dst *CephDaemonCluster) ConvertFrom(hub runtime.Object) error {
+	src, ok := hub.(*v1beta1.CephDaemonCluster)
+	if !ok {
+		return wrongHub(hub)
+	}
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+
+	dst.Spec.ClusterName = src.Spec.ClusterName
+	convertImageFrom(src.Spec.Image, &dst.Spec.Image)
+	dst.Spec.CephConfConfigMapName = src.Spec.CephConfConfigMapName
+	dst.Spec.DaemonType = CephDaemonType(src.Spec.DaemonType)
+	dst.Spec.Disabled = src.Spec.Disabled
+	dst.Spec.Replicas = src.Spec.Replicas
+	convertNetworkFrom(src.Spec.Network, &dst.Spec.Network)
+
+	dst.Status.State = CephDaemonClusterState(src.Status.State)
+
+	return saveConversionData(&dst.ObjectMeta, src.Spec, src.Status)
+}
+
+// ConvertTo converts this CephDaemon to the hub version
+func (src *CephDaemon) ConvertTo(hub runtime.Object) error {
+	dst, ok := hub.(*v1beta1.CephDaemon)
+	if !ok {
+		return wrongHub(hub)
+	}
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	err := restoreConversionData(&dst.ObjectMeta, &dst.Spec, &dst.Status)
+	if err != nil {
+		return err
+	}
+
+	dst.Spec.ClusterName = src.Spec.ClusterName
+	dst.Spec.ID = src.Spec.ID
+	convertImageTo(src.Spec.Image, &dst.Spec.Image)
+	dst.Spec.CephConfConfigMapName = src.Spec.CephConfConfigMapName
+	dst.Spec.DaemonType = v1beta1.CephDaemonType(src.Spec.DaemonType)
+	dst.Spec.Disabled = src.Spec.Disabled
+	convertNetworkTo(src.Spec.Network, &dst.Spec.Network)
+
+	dst.Status.State = v1beta1.CephDaemonState(src.Status.State)
+	return nil
+}
+
+// ConvertFrom converts the hub version to this CephDaemon
+func (dst *CephDaemon) ConvertFrom(hub runtime.Object) error {
+	src, ok := hub.(*v1beta1.CephDaemon)
+	if !ok {
+		return wrongHub(hub)
+	}
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+
+	dst.Spec.ClusterName = src.Spec.ClusterName
+	dst.Spec.ID = src.Spec.ID
+	convertImageFrom(src.Spec.Image, &dst.Spec.Image)
+	dst.Spec.CephConfConfigMapName = src.Spec.CephConfConfigMapName
+	dst.Spec.DaemonType = CephDaemonType(src.Spec.DaemonType)
+	dst.Spec.Disabled = src.Spec.Disabled
+	convertNetworkFrom(src.Spec.Network, &dst.Spec.Network)
+
+	dst.Status.State = CephDaemonState(src.Status.State)
+
+	return saveConversionData(&dst.ObjectMeta, src.Spec, src.Status)
+}
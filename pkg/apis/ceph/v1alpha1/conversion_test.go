@@ -0,0 +1,285 @@
+package v1alpha1
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	"github.com/go-test/deep"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type convertible interface {
+	runtime.Object
+	ConvertTo(runtime.Object) error
+	ConvertFrom(runtime.Object) error
+}
+
+var (
+	testTime    = metav1.NewTime(time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC))
+	testMeta    = metav1.ObjectMeta{Name: "test", Namespace: "ceph", Labels: map[string]string{"app": "ceph"}}
+	testNetwork = NetworkSpec{
+		Provider:                NetworkProviderMultus,
+		PublicNetwork:           "192.168.10.0/24",
+		PublicNetworkAttachment: "ceph/public",
+	}
+	testPlacement = PlacementSpec{
+		AntiAffinity: AntiAffinityPreferred,
+		TopologyKey:  "zone",
+		NodeSelector: map[string]string{"ceph": "true"},
+		Tolerations:  []corev1.Toleration{{Key: "ceph", Operator: corev1.TolerationOpExists}},
+	}
+)
+
+func spokes() map[string]func() convertible {
+	return map[string]func() convertible{
+		"cephcluster": func() convertible {
+			return &CephCluster{
+				ObjectMeta: *testMeta.DeepCopy(),
+				Spec: CephClusterSpec{
+					Config:                 map[string]map[string]string{"global": {"osd_pool_default_size": "3"}},
+					Fsid:                   "3f6d3ee2-9cbb-4e3b-9a3c-0b9cd0a8b5e1",
+					MonServiceName:         "test-mon",
+					ClusterDomain:          "cluster.local",
+					MonImage:               ImageSpec{Registry: "ceph/daemon", Tag: "latest-mimic"},
+					OsdImage:               ImageSpec{Registry: "ceph/daemon", Tag: "latest-mimic"},
+					MgrImage:               ImageSpec{Registry: "ceph/daemon", Tag: "latest-mimic"},
+					MdsImage:               ImageSpec{Registry: "ceph/daemon", Tag: "latest-mimic"},
+					MonPlacement:           testPlacement,
+					Msgr2:                  Msgr2Spec{Require: true, SecureMode: true},
+					Network:                testNetwork,
+					ClientConfigNamespaces: []string{"apps"},
+				},
+				Status: CephClusterStatus{
+					MonClusterName:         "test-mon",
+					State:                  CephClusterRunning,
+					ClientConfigNamespaces: []string{"apps"},
+				},
+			}
+		},
+		"cephmoncluster": func() convertible {
+			return &CephMonCluster{
+				ObjectMeta: *testMeta.DeepCopy(),
+				Spec: CephMonClusterSpec{
+					ClusterName:           "test",
+					Image:                 ImageSpec{Registry: "ceph/daemon", Tag: "latest-mimic"},
+					CephConfConfigMapName: "ceph-test-conf",
+					Placement:             testPlacement,
+					Count:                 3,
+					PvSelectorString:      "ceph.k8s.pgc.umn.edu/mon,zone in (a,b)",
+					FailureTimeout:        &metav1.Duration{Duration: 5 * time.Minute},
+					Recovery:              &MonRecoverySpec{Nonce: "1", SurvivorID: "a", Backup: "backup-1"},
+					Backup: &MonBackupSpec{
+						Retain: 3,
+						Destination: MonBackupDestination{
+							S3: &S3BackupDestination{Endpoint: "http://minio", Bucket: "ceph", Region: "us-west-2", CredentialsSecretName: "s3"},
+						},
+					},
+					Msgr2:   Msgr2Spec{Require: true},
+					Network: testNetwork,
+				},
+				Status: CephMonClusterStatus{
+					StartEpoch:     2,
+					State:          MonClusterInQuorum,
+					InQuorum:       3,
+					LastRecovery:   &MonRecoveryStatus{Nonce: "1", SurvivorID: "a", RemovedMonIDs: []string{"b"}, CompletionTime: testTime},
+					LastBackupTime: &testTime,
+					ActiveBackup:   &MonActiveBackup{Name: "backup-2", MonID: "b", NodeName: "node-b"},
+					Backups:        []MonBackupRecord{{Name: "backup-1", MonID: "a", CompletionTime: testTime, Location: "s3://ceph/backup-1"}},
+				},
+			}
+		},
+		"cephmon": func() convertible {
+			return &CephMon{
+				ObjectMeta: *testMeta.DeepCopy(),
+				Spec: CephMonSpec{
+					ClusterName:      "test",
+					ID:               "a",
+					PvSelectorString: "ceph.k8s.pgc.umn.edu/mon=a",
+					Port:             6790,
+					V2Port:           3301,
+				},
+				Status: CephMonStatus{
+					StartEpoch:       2,
+					State:            MonInQuorum,
+					PodIP:            net.ParseIP("10.244.0.5"),
+					ServiceIP:        net.ParseIP("10.96.0.20"),
+					InitalMember:     true,
+					OutOfQuorumSince: &testTime,
+				},
+			}
+		},
+		"cephosd": func() convertible {
+			return &CephOsd{
+				ObjectMeta: *testMeta.DeepCopy(),
+				Spec:       CephOsdSpec{ID: 3, ClusterName: "test", PvSelectorString: "ceph.k8s.pgc.umn.edu/osd=3"},
+				Status:     CephOsdStatus{State: CephOsdStatePrepared, OsdFsid: "8c2a6a4e-5b1f-4c39-9d7c-1f0f3e0c2b11", OsdID: 3},
+			}
+		},
+		"cephdaemoncluster": func() convertible {
+			return &CephDaemonCluster{
+				ObjectMeta: *testMeta.DeepCopy(),
+				Spec: CephDaemonClusterSpec{
+					ClusterName:           "test",
+					Image:                 ImageSpec{Registry: "ceph/daemon", Tag: "latest-mimic"},
+					CephConfConfigMapName: "ceph-test-conf",
+					DaemonType:            CephDaemonTypeMds,
+					Replicas:              2,
+					Network:               testNetwork,
+				},
+				Status: CephDaemonClusterStatus{State: CephDaemonClusterStateRunning},
+			}
+		},
+		"cephdaemon": func() convertible {
+			return &CephDaemon{
+				ObjectMeta: *testMeta.DeepCopy(),
+				Spec: CephDaemonSpec{
+					ClusterName:           "test",
+					ID:                    "b",
+					Image:                 ImageSpec{Registry: "ceph/daemon", Tag: "latest-mimic"},
+					CephConfConfigMapName: "ceph-test-conf",
+					DaemonType:            CephDaemonTypeMgr,
+					Disabled:              true,
+					Network:               testNetwork,
+				},
+				Status: CephDaemonStatus{State: CephDaemonStateReady},
+			}
+		},
+	}
+}
+
+func hubs() map[string]func() runtime.Object {
+	return map[string]func() runtime.Object{
+		"cephcluster":       func() runtime.Object { return &v1beta1.CephCluster{} },
+		"cephmoncluster":    func() runtime.Object { return &v1beta1.CephMonCluster{} },
+		"cephmon":           func() runtime.Object { return &v1beta1.CephMon{} },
+		"cephosd":           func() runtime.Object { return &v1beta1.CephOsd{} },
+		"cephdaemoncluster": func() runtime.Object { return &v1beta1.CephDaemonCluster{} },
+		"cephdaemon":        func() runtime.Object { return &v1beta1.CephDaemon{} },
+	}
+}
+
+func emptySpokes() map[string]func() convertible {
+	return map[string]func() convertible{
+		"cephcluster":       func() convertible { return &CephCluster{} },
+		"cephmoncluster":    func() convertible { return &CephMonCluster{} },
+		"cephmon":           func() convertible { return &CephMon{} },
+		"cephosd":           func() convertible { return &CephOsd{} },
+		"cephdaemoncluster": func() convertible { return &CephDaemonCluster{} },
+		"cephdaemon":        func() convertible { return &CephDaemon{} },
+	}
+}
+
+func stripConversionData(obj runtime.Object) {
+	meta := obj.(metav1.Object)
+	annotations := meta.GetAnnotations()
+	delete(annotations, ConversionDataAnnotation)
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	meta.SetAnnotations(annotations)
+}
+
+func TestConvertSpokeRoundTrip(t *testing.T) {
+	for name, newSpoke := range spokes() {
+		t.Run(name, func(t *testing.T) {
+			spoke := newSpoke()
+			hub := hubs()[name]()
+			if err := spoke.ConvertTo(hub); err != nil {
+				t.Fatalf("unable to convert to hub: %v", err)
+			}
+
+			if _, ok := hub.(metav1.Object).GetAnnotations()[ConversionDataAnnotation]; ok {
+				t.Errorf("conversion data stored on the hub")
+			}
+
+			out := emptySpokes()[name]()
+			if err := out.ConvertFrom(hub); err != nil {
+				t.Fatalf("unable to convert from hub: %v", err)
+			}
+
+			stripConversionData(out)
+			if diff := deep.Equal(spoke, out); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestConvertHubRoundTrip(t *testing.T) {
+	for name, newSpoke := range spokes() {
+		t.Run(name, func(t *testing.T) {
+			hub := hubs()[name]()
+			if err := newSpoke().ConvertTo(hub); err != nil {
+				t.Fatalf("unable to convert to hub: %v", err)
+			}
+
+			spoke := emptySpokes()[name]()
+			if err := spoke.ConvertFrom(hub); err != nil {
+				t.Fatalf("unable to convert from hub: %v", err)
+			}
+
+			out := hubs()[name]()
+			if err := spoke.ConvertTo(out); err != nil {
+				t.Fatalf("unable to convert to hub: %v", err)
+			}
+
+			if diff := deep.Equal(hub, out); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestConvertCleanedUpFields(t *testing.T) {
+	mon := spokes()["cephmon"]().(*CephMon)
+	hub := &v1beta1.CephMon{}
+	if err := mon.ConvertTo(hub); err != nil {
+		t.Fatalf("unable to convert to hub: %v", err)
+	}
+
+	if !hub.Status.InitialMember || hub.Status.PodIP != "10.244.0.5" || hub.Status.ServiceIP != "10.96.0.20" {
+		t.Errorf("unexpected status: %v", hub.Status)
+	}
+
+	expected := &metav1.LabelSelector{MatchLabels: map[string]string{"ceph.k8s.pgc.umn.edu/mon": "a"}, MatchExpressions: []metav1.LabelSelectorRequirement{}}
+	if diff := deep.Equal(hub.Spec.PvSelector, expected); diff != nil {
+		t.Error(diff)
+	}
+
+	empty := &CephOsd{}
+	osd := &v1beta1.CephOsd{}
+	if err := empty.ConvertTo(osd); err != nil {
+		t.Fatalf("unable to convert to hub: %v", err)
+	}
+	if osd.Spec.PvSelector != nil {
+		t.Errorf("expected an empty selector string to convert to a nil selector, got %v", osd.Spec.PvSelector)
+	}
+}
+
+func TestConvertSpokeChangesWin(t *testing.T) {
+	hub := &v1beta1.CephMonCluster{}
+	if err := spokes()["cephmoncluster"]().ConvertTo(hub); err != nil {
+		t.Fatalf("unable to convert to hub: %v", err)
+	}
+
+	spoke := &CephMonCluster{}
+	if err := spoke.ConvertFrom(hub); err != nil {
+		t.Fatalf("unable to convert from hub: %v", err)
+	}
+
+	spoke.Spec.Count = 5
+	spoke.Spec.Backup = nil
+
+	out := &v1beta1.CephMonCluster{}
+	if err := spoke.ConvertTo(out); err != nil {
+		t.Fatalf("unable to convert to hub: %v", err)
+	}
+
+	if out.Spec.Count != 5 || out.Spec.Backup != nil {
+		t.Errorf("changes made through v1alpha1 were lost: %v", out.Spec)
+	}
+}
@@ -0,0 +1,46 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MonBackupSpec schedules periodic backups of a monitor store
+type MonBackupSpec struct {
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// Retain is the number of backups kept at the destination
+	Retain      int                  `json:"retain,omitempty"`
+	Destination MonBackupDestination `json:"destination"`
+}
+
+// MonBackupDestination is where monitor store backups are written, exactly one destination should be set
+type MonBackupDestination struct {
+	PersistentVolumeClaim *corev1.PersistentVolumeClaimVolumeSource `json:"persistentVolumeClaim,omitempty"`
+	S3                    *S3BackupDestination                      `json:"s3,omitempty"`
+}
+
+// S3BackupDestination is an S3 compatible bucket.  Path style addressing is used so MinIO endpoints work.
+type S3BackupDestination struct {
+	Endpoint string `json:"endpoint"`
+	Bucket   string `json:"bucket"`
+	Prefix   string `json:"prefix,omitempty"`
+	// Region is used to sign requests, us-east-1 if unset
+	Region string `json:"region,omitempty"`
+	// CredentialsSecretName is a secret holding the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY keys
+	CredentialsSecretName string `json:"credentialsSecretName"`
+}
+
+// MonBackupRecord is a completed monitor store backup
+type MonBackupRecord struct {
+	Name           string      `json:"name"`
+	MonID          string      `json:"monId"`
+	CompletionTime metav1.Time `json:"completionTime"`
+	Location       string      `json:"location"`
+}
+
+// MonActiveBackup is a backup in progress.  The source monitor is stopped until its store has been copied.
+type MonActiveBackup struct {
+	Name     string `json:"name"`
+	MonID    string `json:"monId"`
+	NodeName string `json:"nodeName"`
+}
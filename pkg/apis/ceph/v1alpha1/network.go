@@ -0,0 +1,21 @@
+package v1alpha1
+
+type NetworkProvider string
+
+const (
+	NetworkProviderPod    NetworkProvider = ""
+	NetworkProviderHost   NetworkProvider = "host"
+	NetworkProviderMultus NetworkProvider = "multus"
+)
+
+// NetworkSpec describes the networks ceph daemons communicate on
+type NetworkSpec struct {
+	Provider NetworkProvider `json:"provider,omitempty"`
+	// PublicNetwork and ClusterNetwork are CIDRs written to ceph.conf
+	PublicNetwork  string `json:"publicNetwork,omitempty"`
+	ClusterNetwork string `json:"clusterNetwork,omitempty"`
+	// PublicNetworkAttachment and ClusterNetworkAttachment name multus NetworkAttachmentDefinitions
+	// as name or namespace/name
+	PublicNetworkAttachment  string `json:"publicNetworkAttachment,omitempty"`
+	ClusterNetworkAttachment string `json:"clusterNetworkAttachment,omitempty"`
+}
@@ -0,0 +1,23 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+type AntiAffinityType string
+
+const (
+	AntiAffinityRequired  AntiAffinityType = "Required"
+	AntiAffinityPreferred AntiAffinityType = "Preferred"
+)
+
+// PlacementSpec describes how the pods of a daemon type are spread across the kubernetes cluster
+type PlacementSpec struct {
+	AntiAffinity AntiAffinityType    `json:"antiAffinity,omitempty"`
+	TopologyKey  string              `json:"topologyKey,omitempty"`
+	NodeSelector map[string]string   `json:"nodeSelector,omitempty"`
+	Tolerations  []corev1.Toleration `json:"tolerations,omitempty"`
+	// AllowUnsafe allows quorum to be declared while a single failure domain holds enough daemons to break it,
+	// it only applies to required anti-affinity
+	AllowUnsafe bool `json:"allowUnsafe,omitempty"`
+}
@@ -23,6 +23,8 @@ package v1alpha1
 import (
 	net "net"
 
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -32,7 +34,7 @@ func (in *CephCluster) DeepCopyInto(out *CephCluster) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -111,6 +113,14 @@ func (in *CephClusterSpec) DeepCopyInto(out *CephClusterSpec) {
 	out.OsdImage = in.OsdImage
 	out.MgrImage = in.MgrImage
 	out.MdsImage = in.MdsImage
+	in.MonPlacement.DeepCopyInto(&out.MonPlacement)
+	out.Msgr2 = in.Msgr2
+	out.Network = in.Network
+	if in.ClientConfigNamespaces != nil {
+		in, out := &in.ClientConfigNamespaces, &out.ClientConfigNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -127,6 +137,11 @@ func (in *CephClusterSpec) DeepCopy() *CephClusterSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CephClusterStatus) DeepCopyInto(out *CephClusterStatus) {
 	*out = *in
+	if in.ClientConfigNamespaces != nil {
+		in, out := &in.ClientConfigNamespaces, &out.ClientConfigNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -233,6 +248,7 @@ func (in *CephDaemonClusterList) DeepCopyObject() runtime.Object {
 func (in *CephDaemonClusterSpec) DeepCopyInto(out *CephDaemonClusterSpec) {
 	*out = *in
 	out.Image = in.Image
+	out.Network = in.Network
 	return
 }
 
@@ -299,6 +315,7 @@ func (in *CephDaemonList) DeepCopyObject() runtime.Object {
 func (in *CephDaemonSpec) DeepCopyInto(out *CephDaemonSpec) {
 	*out = *in
 	out.Image = in.Image
+	out.Network = in.Network
 	return
 }
 
@@ -361,8 +378,8 @@ func (in *CephMonCluster) DeepCopyInto(out *CephMonCluster) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -421,6 +438,24 @@ func (in *CephMonClusterList) DeepCopyObject() runtime.Object {
 func (in *CephMonClusterSpec) DeepCopyInto(out *CephMonClusterSpec) {
 	*out = *in
 	out.Image = in.Image
+	in.Placement.DeepCopyInto(&out.Placement)
+	if in.FailureTimeout != nil {
+		in, out := &in.FailureTimeout, &out.FailureTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Recovery != nil {
+		in, out := &in.Recovery, &out.Recovery
+		*out = new(MonRecoverySpec)
+		**out = **in
+	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(MonBackupSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	out.Msgr2 = in.Msgr2
+	out.Network = in.Network
 	return
 }
 
@@ -437,6 +472,27 @@ func (in *CephMonClusterSpec) DeepCopy() *CephMonClusterSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CephMonClusterStatus) DeepCopyInto(out *CephMonClusterStatus) {
 	*out = *in
+	if in.LastRecovery != nil {
+		in, out := &in.LastRecovery, &out.LastRecovery
+		*out = new(MonRecoveryStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastBackupTime != nil {
+		in, out := &in.LastBackupTime, &out.LastBackupTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ActiveBackup != nil {
+		in, out := &in.ActiveBackup, &out.ActiveBackup
+		*out = new(MonActiveBackup)
+		**out = **in
+	}
+	if in.Backups != nil {
+		in, out := &in.Backups, &out.Backups
+		*out = make([]MonBackupRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -507,6 +563,15 @@ func (in *CephMonStatus) DeepCopyInto(out *CephMonStatus) {
 		*out = make(net.IP, len(*in))
 		copy(*out, *in)
 	}
+	if in.ServiceIP != nil {
+		in, out := &in.ServiceIP, &out.ServiceIP
+		*out = make(net.IP, len(*in))
+		copy(*out, *in)
+	}
+	if in.OutOfQuorumSince != nil {
+		in, out := &in.OutOfQuorumSince, &out.OutOfQuorumSince
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
@@ -630,67 +695,198 @@ func (in *ImageSpec) DeepCopy() *ImageSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in JsonMonMap) DeepCopyInto(out *JsonMonMap) {
-	{
-		in := &in
-		*out = make(JsonMonMap, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
-		return
+func (in *MonActiveBackup) DeepCopyInto(out *MonActiveBackup) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonActiveBackup.
+func (in *MonActiveBackup) DeepCopy() *MonActiveBackup {
+	if in == nil {
+		return nil
 	}
+	out := new(MonActiveBackup)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JsonMonMap.
-func (in JsonMonMap) DeepCopy() JsonMonMap {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonBackupDestination) DeepCopyInto(out *MonBackupDestination) {
+	*out = *in
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = new(corev1.PersistentVolumeClaimVolumeSource)
+		**out = **in
+	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3BackupDestination)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonBackupDestination.
+func (in *MonBackupDestination) DeepCopy() *MonBackupDestination {
 	if in == nil {
 		return nil
 	}
-	out := new(JsonMonMap)
+	out := new(MonBackupDestination)
 	in.DeepCopyInto(out)
-	return *out
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in MonMap) DeepCopyInto(out *MonMap) {
-	{
-		in := &in
-		*out = make(MonMap, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
-		return
+func (in *MonBackupRecord) DeepCopyInto(out *MonBackupRecord) {
+	*out = *in
+	in.CompletionTime.DeepCopyInto(&out.CompletionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonBackupRecord.
+func (in *MonBackupRecord) DeepCopy() *MonBackupRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(MonBackupRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonBackupSpec) DeepCopyInto(out *MonBackupSpec) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
 	}
+	in.Destination.DeepCopyInto(&out.Destination)
+	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonMap.
-func (in MonMap) DeepCopy() MonMap {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonBackupSpec.
+func (in *MonBackupSpec) DeepCopy() *MonBackupSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(MonMap)
+	out := new(MonBackupSpec)
 	in.DeepCopyInto(out)
-	return *out
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MonMapEntry) DeepCopyInto(out *MonMapEntry) {
+func (in *MonRecoverySpec) DeepCopyInto(out *MonRecoverySpec) {
 	*out = *in
-	if in.IP != nil {
-		in, out := &in.IP, &out.IP
-		*out = make(net.IP, len(*in))
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonRecoverySpec.
+func (in *MonRecoverySpec) DeepCopy() *MonRecoverySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonRecoverySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonRecoveryStatus) DeepCopyInto(out *MonRecoveryStatus) {
+	*out = *in
+	if in.RemovedMonIDs != nil {
+		in, out := &in.RemovedMonIDs, &out.RemovedMonIDs
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	out.NamespacedName = in.NamespacedName
+	in.CompletionTime.DeepCopyInto(&out.CompletionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonRecoveryStatus.
+func (in *MonRecoveryStatus) DeepCopy() *MonRecoveryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MonRecoveryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Msgr2Spec) DeepCopyInto(out *Msgr2Spec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Msgr2Spec.
+func (in *Msgr2Spec) DeepCopy() *Msgr2Spec {
+	if in == nil {
+		return nil
+	}
+	out := new(Msgr2Spec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkSpec) DeepCopyInto(out *NetworkSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkSpec.
+func (in *NetworkSpec) DeepCopy() *NetworkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementSpec) DeepCopyInto(out *PlacementSpec) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementSpec.
+func (in *PlacementSpec) DeepCopy() *PlacementSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3BackupDestination) DeepCopyInto(out *S3BackupDestination) {
+	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonMapEntry.
-func (in *MonMapEntry) DeepCopy() *MonMapEntry {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3BackupDestination.
+func (in *S3BackupDestination) DeepCopy() *S3BackupDestination {
 	if in == nil {
 		return nil
 	}
-	out := new(MonMapEntry)
+	out := new(S3BackupDestination)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -0,0 +1,463 @@
+package v1beta1
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	ini "gopkg.in/ini.v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	ClusterNameLabel    = "ceph.k8s.pgc.umn.edu/cluster"
+	DaemonTypeLabel     = "ceph.k8s.pgc.umn.edu/daemonType"
+	MonitorServiceLabel = "ceph.k8s.pgc.umn.edu/monitorService"
+	KeyringEntityLabel  = "ceph.k8s.pgc.umn.edu/keyringEntity"
+	MonIDLabel          = "ceph.k8s.pgc.umn.edu/monId"
+
+	// CephClusterFinalizer holds a deleted CephCluster until it has been shut down
+	CephClusterFinalizer = "ceph.k8s.pgc.umn.edu/teardown"
+	// ForceTeardownAnnotation removes the finalizer of a deleted CephCluster without waiting for it to shut
+	// down while set to true
+	ForceTeardownAnnotation = "ceph.k8s.pgc.umn.edu/force-teardown"
+)
+
+type CephClusterState string
+
+const (
+	CephClusterIdle         CephClusterState = "Idle"
+	CephClusterStartMons    CephClusterState = "Start Mons"
+	CephClusterStartDaemons CephClusterState = "Start Daemons"
+	CephClusterStartOsds    CephClusterState = "Start Osds"
+	CephClusterRunning      CephClusterState = "Running"
+	CephClusterShutdown     CephClusterState = "Starting Shutdown"
+	CephClusterStopDaemons  CephClusterState = "Stop Daemons"
+	CephClusterStopOsds     CephClusterState = "Stop Osds"
+	CephClusterStopMons     CephClusterState = "Stop Mons"
+)
+
+type DaemonEnabledStateMap map[CephDaemonType][]CephClusterState
+
+var DaemonEnabledStates DaemonEnabledStateMap = DaemonEnabledStateMap{
+	CephDaemonTypeMgr: []CephClusterState{
+		CephClusterRunning, CephClusterStartDaemons, CephClusterStartOsds, CephClusterShutdown,
+	},
+	CephDaemonTypeMds: []CephClusterState{
+		CephClusterRunning, CephClusterStartDaemons, CephClusterStartOsds, CephClusterShutdown,
+	},
+	CephDaemonTypeRgw: []CephClusterState{
+		CephClusterRunning, CephClusterStartDaemons, CephClusterStartOsds, CephClusterShutdown,
+	},
+	CephDaemonTypeOsd: []CephClusterState{
+		CephClusterRunning, CephClusterStartOsds, CephClusterStopDaemons, CephClusterShutdown,
+	},
+	CephDaemonTypeMon: []CephClusterState{
+		CephClusterRunning, CephClusterStartMons, CephClusterStartDaemons, CephClusterStartOsds, CephClusterStopDaemons, CephClusterStopOsds, CephClusterShutdown,
+	},
+}
+
+// CephClusterSpec defines the desired state of CephCluster
+type CephClusterSpec struct {
+	Disabled       bool                         `json:"disabled"`
+	Config         map[string]map[string]string `json:"config"`
+	Fsid           string                       `json:"fsid"`
+	MonServiceName string                       `json:"monServiceName"`
+	ClusterDomain  string                       `json:"clusterDomain"`
+	MonImage       ImageSpec                    `json:"monImage"`
+	OsdImage       ImageSpec                    `json:"osdImage"`
+	MgrImage       ImageSpec                    `json:"mgrImage"`
+	MdsImage       ImageSpec                    `json:"mdsImage"`
+	MonPlacement   PlacementSpec                `json:"monPlacement"`
+	Msgr2          Msgr2Spec                    `json:"msgr2"`
+	Network        NetworkSpec                  `json:"network"`
+	// ClientConfigNamespaces are namespaces that receive a copy of the ceph.conf ConfigMap for clients
+	ClientConfigNamespaces []string `json:"clientConfigNamespaces,omitempty"`
+	// ReclaimPolicy applies to the PVCs of monitors and osds that don't set their own, keyring secrets are only
+	// deleted when it's set to Delete
+	ReclaimPolicy ReclaimPolicy `json:"reclaimPolicy,omitempty"`
+	// Import adopts an existing ceph cluster with the fsid from the spec
+	Import *ImportSpec `json:"import,omitempty"`
+	// External consumes a ceph cluster run outside of Kubernetes, no daemons or monitor service are created
+	External bool `json:"external,omitempty"`
+	// ExternalCluster describes how clients reach an external cluster
+	ExternalCluster *ExternalClusterSpec `json:"externalCluster,omitempty"`
+	// PodOverrides are merged over the generated pods of each daemon type
+	PodOverrides PodOverridesSpec `json:"podOverrides,omitempty"`
+	// OsdFailureDomainKey is the node label defining the failure domains osd disruption budgets are created for
+	OsdFailureDomainKey string `json:"osdFailureDomainKey,omitempty"`
+	// DisableDisruptionBudgets removes the PodDisruptionBudgets protecting the cluster's daemons from evictions
+	DisableDisruptionBudgets bool `json:"disableDisruptionBudgets,omitempty"`
+	// HealthCheckInterval is the time between polls of ceph health, a minute if unset
+	HealthCheckInterval *metav1.Duration `json:"healthCheckInterval,omitempty"`
+	// HealthGates make state transitions wait for ceph to report the cluster healthy enough to proceed
+	HealthGates HealthGatesSpec `json:"healthGates,omitempty"`
+}
+
+// ExternalClusterSpec describes a ceph cluster consumed by clients in Kubernetes
+type ExternalClusterSpec struct {
+	// MonHosts are the addresses of the cluster's monitors
+	MonHosts []string `json:"monHosts"`
+	// ClientKeyringSecretName is a secret holding the client keyring under the keyring key
+	ClientKeyringSecretName string `json:"clientKeyringSecretName"`
+	// ClientName is the ceph user the keyring belongs to, client.admin if unset
+	ClientName string `json:"clientName,omitempty"`
+}
+
+// GetClientName returns the ceph user of the client keyring
+func (e *ExternalClusterSpec) GetClientName() string {
+	if e.ClientName == "" {
+		return "client.admin"
+	}
+	return e.ClientName
+}
+
+// ImportSpec describes a ceph cluster deployed outside of the operator.  Monitors created by the operator join
+// the existing quorum, so daemons can be migrated into the cluster gradually.
+type ImportSpec struct {
+	// MonHosts are the addresses of the existing monitors, they are added to mon_host.  Remove monitors from
+	// the list as they are decommissioned.
+	MonHosts []string `json:"monHosts,omitempty"`
+	// MonKeyringSecretName is a secret holding the existing mon. keyring under the keyring key
+	MonKeyringSecretName string `json:"monKeyringSecretName"`
+	// AdminKeyringSecretName is a secret holding the existing client.admin keyring under the keyring key
+	AdminKeyringSecretName string `json:"adminKeyringSecretName"`
+}
+
+// GetKeyringSecretName returns the secret holding the existing keyring for the entity, or an empty string
+// if the keyring isn't imported
+func (i *ImportSpec) GetKeyringSecretName(entity string) string {
+	switch strings.Trim(entity, ".") {
+	case "mon":
+		return i.MonKeyringSecretName
+	case "client.admin":
+		return i.AdminKeyringSecretName
+	}
+	return ""
+}
+
+// GetExternalCluster returns the external cluster spec, it's empty if unset
+func (c *CephCluster) GetExternalCluster() *ExternalClusterSpec {
+	if c.Spec.ExternalCluster == nil {
+		return &ExternalClusterSpec{}
+	}
+	return c.Spec.ExternalCluster
+}
+
+// GetExternalKeyringSecretName returns the name of the operator's copy of the external client keyring
+func (c *CephCluster) GetExternalKeyringSecretName() string {
+	return fmt.Sprintf("ceph-%s-%s-keyring", c.GetName(), c.GetExternalCluster().GetClientName())
+}
+
+// HasExternalQuorum returns true while monitors outside the operator are part of the quorum
+func (c *CephCluster) HasExternalQuorum() bool {
+	return c.Spec.Import != nil && len(c.Spec.Import.MonHosts) > 0
+}
+
+// Msgr2Spec configures the msgr2 wire protocol
+type Msgr2Spec struct {
+	// Require disables the legacy msgr1 protocol
+	Require bool `json:"require,omitempty"`
+	// SecureMode requires encrypted msgr2 connections
+	SecureMode bool `json:"secureMode,omitempty"`
+}
+
+var msgr2SecureModeOptions = []string{
+	"ms_cluster_mode", "ms_service_mode", "ms_client_mode",
+	"ms_mon_cluster_mode", "ms_mon_service_mode", "ms_mon_client_mode",
+}
+
+type ImageSpec struct {
+	Registry string `json:"registry"`
+	Tag      string `json:"tag"`
+}
+
+func (i ImageSpec) String() string {
+	return fmt.Sprintf("%s:%s", i.Registry, i.Tag)
+}
+
+// CephClusterStatus defines the observed state of CephCluster
+type CephClusterStatus struct {
+	MonClusterName string           `json:"monClusterName"`
+	State          CephClusterState `json:"state"`
+	// ClientConfigNamespaces are the namespaces the client ceph.conf has been published to
+	ClientConfigNamespaces []string `json:"clientConfigNamespaces,omitempty"`
+	// MaintenanceNodes are the nodes with noout set and their osds stopped for maintenance
+	MaintenanceNodes []string `json:"maintenanceNodes,omitempty"`
+	// MaintenanceError is why nodes couldn't be put into or taken out of maintenance, maintenance is retried on
+	// the next reconcile
+	MaintenanceError string `json:"maintenanceError,omitempty"`
+	// Health is the health of the cluster as last reported by ceph
+	Health *CephClusterHealth `json:"health,omitempty"`
+	// TransitionBlocked is set while a health gate holds the cluster in its current state
+	TransitionBlocked *CephClusterTransitionBlock `json:"transitionBlocked,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephCluster is the Schema for the cephclusters API
+// +k8s:openapi-gen=true
+type CephCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CephClusterSpec   `json:"spec,omitempty"`
+	Status CephClusterStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephClusterList contains a list of CephCluster
+type CephClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CephCluster `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CephCluster{}, &CephClusterList{})
+}
+
+// GetCephConfigMap returns a configmap containing a vaild ceph.conf file.  Monitors are listed from the
+// in quorum members of monMap, falling back to the monitor service until a monitor is in quorum.
+func (c *CephCluster) GetCephConfigMap(monMap MonMap) (*corev1.ConfigMap, error) {
+	// Inject monitor service name
+	// FSID, Mon_Host, Public Network, Private Network, Osd
+
+	cephConfIni := ini.Empty()
+
+	global, err := cephConfIni.NewSection("global")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = global.NewKey("fsid", c.Spec.Fsid)
+	if err != nil {
+		return nil, err
+	}
+
+	inQuorum := monMap.InState(MonInQuorum)
+	if c.Spec.External {
+		_, err = global.NewKey("mon_host", strings.Join(c.GetExternalCluster().MonHosts, ","))
+		if err != nil {
+			return nil, err
+		}
+	} else if c.HasExternalQuorum() {
+		inQuorum = inQuorum.withMsgr2(c.Spec.Msgr2)
+		monHosts := append([]string{}, c.Spec.Import.MonHosts...)
+		for _, id := range inQuorum.GetIDs() {
+			monHosts = append(monHosts, inQuorum[id].Addrs.String())
+		}
+
+		_, err = global.NewKey("mon_host", strings.Join(monHosts, ","))
+		if err != nil {
+			return nil, err
+		}
+	} else if inQuorum.Empty() {
+		// Address vectors only accept IPs, ceph resolves a plain host name to both ports itself
+		_, err = global.NewKey("mon_host", c.Spec.MonServiceName)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		inQuorum = inQuorum.withMsgr2(c.Spec.Msgr2)
+		ids := inQuorum.GetIDs()
+		monHosts := make([]string, 0, len(ids))
+		for _, id := range ids {
+			monHosts = append(monHosts, inQuorum[id].Addrs.String())
+		}
+
+		_, err = global.NewKey("mon_initial_members", strings.Join(ids, ","))
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = global.NewKey("mon_host", strings.Join(monHosts, ","))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.Spec.Network.PublicNetwork != "" {
+		_, err = global.NewKey("public_network", c.Spec.Network.PublicNetwork)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.Spec.Network.ClusterNetwork != "" {
+		_, err = global.NewKey("cluster_network", c.Spec.Network.ClusterNetwork)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.Spec.Msgr2.Require {
+		_, err = global.NewKey("ms_bind_msgr1", "false")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.Spec.Msgr2.SecureMode {
+		for _, option := range msgr2SecureModeOptions {
+			_, err = global.NewKey(option, "secure")
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for sectionName, sectionMap := range c.Spec.Config {
+		section, err := cephConfIni.NewSection(sectionName)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range sectionMap {
+			_, err = section.NewKey(k, v)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	cephConf := bytes.NewBufferString("")
+	cephConfIni.WriteTo(cephConf)
+
+	cm := &corev1.ConfigMap{}
+	cm.Name = c.GetCephConfigMapName()
+	cm.Data = map[string]string{fmt.Sprintf("%s.conf", c.GetName()): cephConf.String()}
+
+	return cm, nil
+}
+
+// GetDaemonEnabled returns true if the cluster's state allows daemons of the type to run, daemons never run
+// for an external cluster
+func (c *CephCluster) GetDaemonEnabled(d CephDaemonType) bool {
+	if c.Spec.External {
+		return false
+	}
+
+	enabledStates, ok := DaemonEnabledStates[d]
+	if !ok {
+		return false
+	}
+
+	for _, state := range enabledStates {
+		if c.Status.State == state {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsDeleting returns true once the cluster has been deleted and is waiting on teardown
+func (c *CephCluster) IsDeleting() bool {
+	return c.GetDeletionTimestamp() != nil
+}
+
+// ForceTeardown returns true if the finalizer may be removed before the cluster is idle
+func (c *CephCluster) ForceTeardown() bool {
+	return c.GetAnnotations()[ForceTeardownAnnotation] == "true"
+}
+
+func (d *CephCluster) GetState() CephClusterState {
+	return d.Status.State
+}
+
+func (d *CephCluster) SetState(s CephClusterState) {
+	d.Status.State = s
+}
+
+func (c *CephCluster) GetCephConfigMapName() string {
+	return fmt.Sprintf("ceph-%s-conf", c.GetName())
+}
+
+func (c *CephCluster) GetMonImage() string {
+	return c.Spec.MonImage.String()
+}
+
+func (c *CephCluster) GetOsdImage() string {
+	return c.Spec.OsdImage.String()
+}
+
+func (c *CephCluster) GetMgrImage() string {
+	return c.Spec.MgrImage.String()
+}
+
+func (c *CephCluster) GetMdsImage() string {
+	return c.Spec.MdsImage.String()
+}
+
+func getMonitorServicePorts() []corev1.ServicePort {
+	return []corev1.ServicePort{
+		corev1.ServicePort{
+			Name:       "ceph-mon-msgr2",
+			Port:       DefaultMonV2Port,
+			TargetPort: intstr.FromInt(DefaultMonV2Port),
+		},
+		corev1.ServicePort{
+			Name:       "ceph-mon",
+			Port:       DefaultMonV1Port,
+			TargetPort: intstr.FromInt(DefaultMonV1Port),
+		},
+	}
+}
+
+// GetMonitorServiceSelector selects the cluster's monitor pods
+func (c *CephCluster) GetMonitorServiceSelector() map[string]string {
+	return map[string]string{
+		ClusterNameLabel:    c.GetName(),
+		MonitorServiceLabel: c.GetName(),
+	}
+}
+
+func (c *CephCluster) GetMonitorService() *corev1.Service {
+	svc := &corev1.Service{}
+
+	svc.Name = c.Spec.MonServiceName
+	svc.SetLabels(map[string]string{ClusterNameLabel: c.GetName()})
+
+	svc.Spec = corev1.ServiceSpec{
+		Ports:     getMonitorServicePorts(),
+		Selector:  c.GetMonitorServiceSelector(),
+		ClusterIP: "None",
+	}
+
+	return svc
+}
+
+func (c *CephCluster) GetMonitorDiscoveryService() *corev1.Service {
+	svc := &corev1.Service{}
+
+	svc.Name = fmt.Sprintf("%s-discovery", c.Spec.MonServiceName)
+	svc.SetLabels(map[string]string{ClusterNameLabel: c.GetName()})
+
+	svc.Spec = corev1.ServiceSpec{
+		Ports:                    getMonitorServicePorts(),
+		Selector:                 c.GetMonitorServiceSelector(),
+		ClusterIP:                "None",
+		PublishNotReadyAddresses: true,
+	}
+
+	return svc
+}
+
+func (c *CephCluster) GetAPIVersion() string {
+	return c.APIVersion
+}
+
+func (c *CephCluster) SetAPIVersion(version string) {
+	c.APIVersion = version
+}
+
+func (c *CephCluster) GetKind() string {
+	return c.Kind
+}
+
+func (c *CephCluster) SetKind(kind string) {
+	c.Kind = kind
+}
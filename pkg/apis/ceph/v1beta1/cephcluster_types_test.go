@@ -0,0 +1,218 @@
+package v1beta1
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-test/deep"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestGetCephConfigMap(t *testing.T) {
+	testCases := []struct {
+		Name              string
+		Cluster           CephCluster
+		MonMap            MonMap
+		ExpectedConfigMap *corev1.ConfigMap
+	}{
+		{
+			Name: "basic-config-map",
+			Cluster: CephCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: CephClusterSpec{
+					Fsid:           "FCA3CCCA-8258-4A72-8C10-39CF2B0585EE",
+					MonServiceName: "monitor",
+				},
+			},
+			ExpectedConfigMap: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "ceph-test-conf",
+				},
+				Data: map[string]string{
+					"test.conf": "[global]\n" +
+						"fsid     = FCA3CCCA-8258-4A72-8C10-39CF2B0585EE\n" +
+						"mon_host = monitor\n\n"}},
+		},
+		{
+			Name: "in-quorum-monitors",
+			Cluster: CephCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: CephClusterSpec{
+					Fsid:           "FCA3CCCA-8258-4A72-8C10-39CF2B0585EE",
+					MonServiceName: "monitor",
+				},
+			},
+			MonMap: MonMap{
+				"b": MonMapEntry{State: MonInQuorum, Addrs: NewMonAddrVec("10.0.0.2", 3300, 6789, false)},
+				"a": MonMapEntry{State: MonInQuorum, Addrs: NewMonAddrVec("10.0.0.1", 3300, 6789, false)},
+				"c": MonMapEntry{State: MonWaitForPodRun, Addrs: NewMonAddrVec("10.0.0.3", 3300, 6789, false)},
+			},
+			ExpectedConfigMap: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "ceph-test-conf",
+				},
+				Data: map[string]string{
+					"test.conf": "[global]\n" +
+						"fsid                = FCA3CCCA-8258-4A72-8C10-39CF2B0585EE\n" +
+						"mon_initial_members = a,b\n" +
+						"mon_host            = [v2:10.0.0.1:3300,v1:10.0.0.1:6789],[v2:10.0.0.2:3300,v1:10.0.0.2:6789]\n\n"}},
+		},
+		{
+			Name: "imported-monitors",
+			Cluster: CephCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: CephClusterSpec{
+					Fsid:           "FCA3CCCA-8258-4A72-8C10-39CF2B0585EE",
+					MonServiceName: "monitor",
+					Import:         &ImportSpec{MonHosts: []string{"192.168.1.10", "192.168.1.11"}},
+				},
+			},
+			MonMap: MonMap{
+				"a": MonMapEntry{State: MonInQuorum, Addrs: NewMonAddrVec("10.0.0.1", 3300, 6789, false)},
+				"b": MonMapEntry{State: MonWaitForPodRun, Addrs: NewMonAddrVec("10.0.0.2", 3300, 6789, false)},
+			},
+			ExpectedConfigMap: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "ceph-test-conf",
+				},
+				Data: map[string]string{
+					"test.conf": "[global]\n" +
+						"fsid     = FCA3CCCA-8258-4A72-8C10-39CF2B0585EE\n" +
+						"mon_host = 192.168.1.10,192.168.1.11,[v2:10.0.0.1:3300,v1:10.0.0.1:6789]\n\n"}},
+		},
+		{
+			Name: "external-cluster",
+			Cluster: CephCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: CephClusterSpec{
+					Fsid:            "FCA3CCCA-8258-4A72-8C10-39CF2B0585EE",
+					MonServiceName:  "monitor",
+					External:        true,
+					ExternalCluster: &ExternalClusterSpec{MonHosts: []string{"192.168.1.10", "192.168.1.11"}},
+				},
+			},
+			MonMap: MonMap{
+				"a": MonMapEntry{State: MonInQuorum, Addrs: NewMonAddrVec("10.0.0.1", 3300, 6789, false)},
+			},
+			ExpectedConfigMap: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "ceph-test-conf",
+				},
+				Data: map[string]string{
+					"test.conf": "[global]\n" +
+						"fsid     = FCA3CCCA-8258-4A72-8C10-39CF2B0585EE\n" +
+						"mon_host = 192.168.1.10,192.168.1.11\n\n"}},
+		},
+		{
+			Name: "require-msgr2-secure",
+			Cluster: CephCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: CephClusterSpec{
+					Fsid:           "FCA3CCCA-8258-4A72-8C10-39CF2B0585EE",
+					MonServiceName: "monitor",
+					Msgr2:          Msgr2Spec{Require: true, SecureMode: true},
+				},
+			},
+			ExpectedConfigMap: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "ceph-test-conf",
+				},
+				Data: map[string]string{
+					"test.conf": "[global]\n" +
+						"fsid                = FCA3CCCA-8258-4A72-8C10-39CF2B0585EE\n" +
+						"mon_host            = monitor\n" +
+						"ms_bind_msgr1       = false\n" +
+						"ms_cluster_mode     = secure\n" +
+						"ms_service_mode     = secure\n" +
+						"ms_client_mode      = secure\n" +
+						"ms_mon_cluster_mode = secure\n" +
+						"ms_mon_service_mode = secure\n" +
+						"ms_mon_client_mode  = secure\n\n"}},
+		},
+		{
+			Name: "override-config-parameters",
+			Cluster: CephCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: CephClusterSpec{
+					Fsid:           "FCA3CCCA-8258-4A72-8C10-39CF2B0585EE",
+					MonServiceName: "monitor",
+					Config: map[string]map[string]string{
+						"global": map[string]string{
+							"mon_host": "fooservice",
+							"keyring":  "/keyrings/client.admin/keyring",
+						},
+						"mon": map[string]string{
+							"fookey": "barval",
+						},
+					},
+				},
+			},
+			ExpectedConfigMap: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "ceph-test-conf",
+				},
+				Data: map[string]string{
+					"test.conf": "[global]\n" +
+						"fsid     = FCA3CCCA-8258-4A72-8C10-39CF2B0585EE\n" +
+						"mon_host = fooservice\n" +
+						"keyring  = /keyrings/client.admin/keyring\n" +
+						"\n" +
+						"[mon]\n" +
+						"fookey = barval\n" +
+						"\n"}},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(st *testing.T) {
+			cm, err := c.Cluster.GetCephConfigMap(c.MonMap)
+			if err != nil {
+				st.Fatalf("Error getting config map: %v", err)
+			}
+			if diff := deep.Equal(cm, c.ExpectedConfigMap); len(diff) > 0 {
+				st.Error("Resulting config map not equal to expected.")
+				for _, l := range diff {
+					st.Error(l)
+				}
+			}
+		})
+	}
+
+}
+
+func TestMonitorServiceSelectsOwnCluster(t *testing.T) {
+	mon := NewCephMon("a", nil)
+	pod := mon.GetPod(&CephMonCluster{}, "admin")
+
+	for _, c := range []struct {
+		Cluster  string
+		Selected bool
+	}{
+		{Cluster: "a", Selected: true},
+		{Cluster: "b", Selected: false},
+	} {
+		cluster := &CephCluster{ObjectMeta: metav1.ObjectMeta{Name: c.Cluster}}
+		selected := labels.SelectorFromSet(cluster.GetMonitorService().Spec.Selector).Matches(labels.Set(pod.GetLabels()))
+		if selected != c.Selected {
+			t.Errorf("expected monitor service of cluster %s to select monitor pod of cluster a: %t", c.Cluster, c.Selected)
+		}
+	}
+
+	if expected := fmt.Sprintf("ceph-a-mon-%s", mon.Spec.ID); mon.GetPodName() != expected {
+		t.Errorf("expected pod name %s, got %s", expected, mon.GetPodName())
+	}
+}
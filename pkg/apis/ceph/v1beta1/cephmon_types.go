@@ -0,0 +1,578 @@
+package v1beta1
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/rand"
+)
+
+const MonQuorumPodCondition corev1.PodConditionType = corev1.PodReady
+
+// CephMonSpec defines the desired state of CephMon
+type CephMonSpec struct {
+	ClusterName string `json:"clusterName"`
+	ID          string `json:"id"`
+	// PvSelector selects the persistent volume the monitor store is kept on
+	PvSelector *metav1.LabelSelector `json:"pvSelector,omitempty"`
+	Disabled   bool                  `json:"disabled"`
+	Port       int                   `json:"port"`
+	V2Port     int                   `json:"v2Port"`
+	// ReclaimPolicy overrides the cluster's reclaim policy for the monitor's PVC
+	ReclaimPolicy ReclaimPolicy `json:"reclaimPolicy,omitempty"`
+}
+
+const (
+	DefaultMonV1Port = 6789
+	DefaultMonV2Port = 3300
+)
+
+// MonState describes the state of the monitor
+type MonState string
+
+const (
+	MonLaunchPod       MonState = "Launch Pod"
+	MonWaitForPodRun   MonState = "Wait for Pod Run"
+	MonWaitForPodReady MonState = "Wait for Pod Ready"
+	MonInQuorum        MonState = "In Quorum"
+	MonError           MonState = "Error"
+	MonCleanup         MonState = "Cleanup"
+	MonIdle            MonState = "Idle"
+)
+
+// CephMonStatus defines the observed state of CephMon
+type CephMonStatus struct {
+	StartEpoch int      `json:"startEpoch"`
+	State      MonState `json:"monState"`
+	PodIP      string   `json:"podIP,omitempty"`
+	// ServiceIP is the stable address of the monitor's service, when monitors are reached through services
+	ServiceIP     string `json:"serviceIP,omitempty"`
+	InitialMember bool   `json:"initialMember"`
+	// OutOfQuorumSince is set while the monitor is out of quorum and the mon cluster is in quorum
+	OutOfQuorumSince *metav1.Time `json:"outOfQuorumSince,omitempty"`
+	// LegacyPodName is set while a pod launched under the name used before pod names included the cluster
+	// name is running, it's cleared once the pod is cleaned up
+	LegacyPodName string `json:"legacyPodName,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephMon is the Schema for the cephmons API
+// +k8s:openapi-gen=true
+type CephMon struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CephMonSpec   `json:"spec,omitempty"`
+	Status CephMonStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephMonList contains a list of CephMon
+type CephMonList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CephMon `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CephMon{}, &CephMonList{})
+}
+
+// NewCephMon returns a monitor with a generated id for the cluster
+func NewCephMon(clusterName string, pvSelector *metav1.LabelSelector) *CephMon {
+	m := &CephMon{}
+	// Always have non-numeric start char
+	c := 'a' + rand.Intn(26)
+	m.Spec.ID = string(rune(c)) + rand.String(5)
+	m.Spec.ClusterName = clusterName
+	m.Spec.PvSelector = pvSelector.DeepCopy()
+	m.Name = fmt.Sprintf("%s-mon-%s", clusterName, m.Spec.ID)
+
+	return m
+}
+
+func (m *CephMon) GetDisabled() bool {
+	return m.Spec.Disabled
+}
+
+func (m *CephMon) GetVolumeClaimTemplate() (*corev1.PersistentVolumeClaim, error) {
+	pvc := &corev1.PersistentVolumeClaim{}
+	filesystemMode := corev1.PersistentVolumeFilesystem
+
+	pvc.APIVersion = "v1"
+	pvc.Kind = "PersistentVolumeCliam"
+
+	pvc.Name = m.GetName()
+
+	pvc.Spec.Selector = m.GetPvLabelSelector()
+	pvc.Spec.VolumeMode = &filesystemMode
+	pvc.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{
+		corev1.ReadWriteOnce,
+	}
+
+	storageClass := "local-storage"
+	pvc.Spec.StorageClassName = &storageClass
+
+	qty := resource.NewQuantity(100000, resource.DecimalSI)
+	pvc.Spec.Resources = corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceStorage: *qty,
+		},
+	}
+
+	return pvc, nil
+}
+
+// GetPodName returns the name of the monitor's pod, it's prefixed with the cluster name so monitors of
+// clusters sharing a namespace don't collide.  An adopted pod with the legacy name keeps its name.
+func (m *CephMon) GetPodName() string {
+	if m.Status.LegacyPodName != "" {
+		return m.Status.LegacyPodName
+	}
+	return m.daemonName()
+}
+
+func (m *CephMon) daemonName() string {
+	return fmt.Sprintf("ceph-%s-mon-%s", m.Spec.ClusterName, m.Spec.ID)
+}
+
+// GetLegacyPodName returns the name the monitor's pod had before pod names included the cluster name
+func (m *CephMon) GetLegacyPodName() string {
+	return fmt.Sprintf("ceph-%s", m.GetName())
+}
+
+func (m *CephMon) GetPod(monCluster *CephMonCluster, clientAdminKeyringName string) *corev1.Pod {
+	pod := &corev1.Pod{}
+
+	pod.APIVersion = "v1"
+	pod.Kind = "Pod"
+
+	pod.Name = m.daemonName()
+
+	pod.SetLabels(map[string]string{
+		MonitorServiceLabel: m.Spec.ClusterName,
+		ClusterNameLabel:    m.Spec.ClusterName,
+		DaemonTypeLabel:     CephDaemonTypeMon.String(),
+		MonIDLabel:          m.Spec.ID,
+	})
+
+	container := corev1.Container{}
+	container.Name = "ceph-mon"
+	container.Image = monCluster.GetImage().String()
+	container.Env = []corev1.EnvVar{
+		corev1.EnvVar{
+			Name:  "CMD",
+			Value: "start_mon",
+		},
+		corev1.EnvVar{
+			Name:  "MON_ID",
+			Value: m.Spec.ID,
+		},
+		corev1.EnvVar{
+			Name:  "CLUSTER",
+			Value: m.Spec.ClusterName,
+		},
+		corev1.EnvVar{
+			Name:  "MON_CLUSTER_START_EPOCH",
+			Value: strconv.Itoa(monCluster.Status.StartEpoch),
+		},
+	}
+	container.Env = append(container.Env, m.getMonIPEnv(monCluster.Spec.Network)...)
+
+	container.VolumeMounts = []corev1.VolumeMount{
+		corev1.VolumeMount{
+			Name:      "ceph-conf",
+			MountPath: "/etc/ceph",
+		},
+		corev1.VolumeMount{
+			Name:      "ceph-mon-data",
+			MountPath: "/mon",
+		},
+		corev1.VolumeMount{
+			Name:      "moncluster-configmap",
+			MountPath: "/config/moncluster",
+		},
+		corev1.VolumeMount{
+			Name:      "client-admin-keyring",
+			MountPath: "/keyrings/client.admin",
+		},
+	}
+
+	container.Ports = []corev1.ContainerPort{
+		corev1.ContainerPort{
+			Name:          "ceph-mon-msgr2",
+			ContainerPort: int32(m.GetV2Port()),
+		},
+		corev1.ContainerPort{
+			Name:          "ceph-mon",
+			ContainerPort: int32(m.GetPort()),
+		},
+	}
+
+	container.ImagePullPolicy = corev1.PullAlways
+
+	handler := corev1.Handler{
+		Exec: &corev1.ExecAction{
+			Command: []string{
+				"/ceph/bin/mon_health.sh",
+			},
+		},
+	}
+
+	container.ReadinessProbe = &corev1.Probe{
+		Handler:             handler,
+		InitialDelaySeconds: 15,
+		PeriodSeconds:       15,
+		FailureThreshold:    5,
+		TimeoutSeconds:      10,
+	}
+
+	container.LivenessProbe = &corev1.Probe{
+		Handler:             handler,
+		InitialDelaySeconds: 30,
+		PeriodSeconds:       30,
+		TimeoutSeconds:      25,
+		FailureThreshold:    5,
+	}
+
+	pod.Spec.Containers = []corev1.Container{container}
+	pod.Spec.ServiceAccountName = GetServiceAccountName(m.Spec.ClusterName, CephDaemonTypeMon)
+
+	pod.Spec.Volumes = []corev1.Volume{
+		corev1.Volume{
+			Name: "ceph-mon-data",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: m.GetName(),
+				},
+			},
+		},
+		corev1.Volume{
+			Name: "ceph-conf",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: monCluster.GetCephConfConfigMapName(),
+					},
+				},
+			},
+		},
+		corev1.Volume{
+			Name: "moncluster-configmap",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: monCluster.GetConfigMapName(),
+					},
+				},
+			},
+		},
+		corev1.Volume{
+			Name: "client-admin-keyring",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: clientAdminKeyringName,
+				},
+			},
+		},
+	}
+
+	monCluster.Spec.Network.ApplyToPodTemplate(&pod.ObjectMeta, &pod.Spec, false)
+
+	monCluster.GetPlacement().ApplyToPod(pod, map[string]string{
+		ClusterNameLabel: m.Spec.ClusterName,
+		DaemonTypeLabel:  CephDaemonTypeMon.String(),
+	})
+
+	return pod
+}
+
+// getMonIPEnv returns the address the monitor advertises.  A monitor with a service advertises
+// the service address and binds to its pod address.
+func (m *CephMon) getMonIPEnv(network NetworkSpec) []corev1.EnvVar {
+	if m.Status.ServiceIP == "" {
+		return network.GetMonIPEnv()
+	}
+
+	return []corev1.EnvVar{
+		corev1.EnvVar{
+			Name:  "MON_IP",
+			Value: m.Status.ServiceIP,
+		},
+		corev1.EnvVar{
+			Name: "MON_BIND_IP",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: "status.podIP",
+				},
+			},
+		},
+	}
+}
+
+func (m *CephMon) GetServiceName() string {
+	return m.daemonName()
+}
+
+// GetService returns a service giving the monitor an address that doesn't change when its pod is rescheduled
+func (m *CephMon) GetService() *corev1.Service {
+	svc := &corev1.Service{}
+
+	svc.APIVersion = "v1"
+	svc.Kind = "Service"
+
+	svc.Name = m.GetServiceName()
+	svc.SetLabels(map[string]string{
+		ClusterNameLabel: m.Spec.ClusterName,
+		DaemonTypeLabel:  CephDaemonTypeMon.String(),
+		MonIDLabel:       m.Spec.ID,
+	})
+
+	svc.Spec = corev1.ServiceSpec{
+		Ports: []corev1.ServicePort{
+			corev1.ServicePort{
+				Name:       "ceph-mon-msgr2",
+				Port:       int32(m.GetV2Port()),
+				TargetPort: intstr.FromInt(m.GetV2Port()),
+			},
+			corev1.ServicePort{
+				Name:       "ceph-mon",
+				Port:       int32(m.GetPort()),
+				TargetPort: intstr.FromInt(m.GetPort()),
+			},
+		},
+		Selector: map[string]string{
+			ClusterNameLabel: m.Spec.ClusterName,
+			DaemonTypeLabel:  CephDaemonTypeMon.String(),
+			MonIDLabel:       m.Spec.ID,
+		},
+		// Monitors must reach each other before they are ready to form quorum
+		PublishNotReadyAddresses: true,
+	}
+
+	return svc
+}
+
+// GetAddress returns the address the monitor is known by in the monmap
+func (m *CephMon) GetAddress() net.IP {
+	if m.Status.ServiceIP != "" {
+		return net.ParseIP(m.Status.ServiceIP)
+	}
+	return net.ParseIP(m.Status.PodIP)
+}
+
+func (m *CephMon) GetRecoveryJobName() string {
+	return fmt.Sprintf("%s-recover", m.daemonName())
+}
+
+// GetRecoveryJob returns a job that rebuilds this monitor's store so it can form a quorum alone.
+// The recover_mon command restores the named backup if one is given, extracts the monmap from
+// the store, removes the listed monitors from it, adds this monitor if it is missing and injects
+// the edited monmap back into the store.
+func (m *CephMon) GetRecoveryJob(monCluster *CephMonCluster, removeMonIDs []string, restoreBackup string) *batchv1.Job {
+	env := []corev1.EnvVar{
+		corev1.EnvVar{
+			Name:  "REMOVE_MON_IDS",
+			Value: strings.Join(removeMonIDs, ","),
+		},
+	}
+
+	var destination *MonBackupDestination
+	if restoreBackup != "" && monCluster.Spec.Backup != nil {
+		env = append(env, corev1.EnvVar{Name: "RESTORE_BACKUP", Value: restoreBackup})
+		destination = &monCluster.Spec.Backup.Destination
+	}
+
+	return m.getStoreJob(monCluster, m.GetRecoveryJobName(), "recover_mon", env, false, destination)
+}
+
+func (m *CephMon) GetBackupJobName(backupName string) string {
+	return fmt.Sprintf("ceph-%s", backupName)
+}
+
+// GetBackupJob returns a job that copies this monitor's store to the backup destination.  The
+// monitor must be stopped while the job runs so the copied store is consistent, the job runs on
+// the monitor's node so it can mount the monitor's volume.  Old backups are pruned by the operator.
+func (m *CephMon) GetBackupJob(monCluster *CephMonCluster, backupName, nodeName string) *batchv1.Job {
+	backup := monCluster.Spec.Backup
+	env := []corev1.EnvVar{
+		corev1.EnvVar{
+			Name:  "BACKUP_NAME",
+			Value: backupName,
+		},
+	}
+
+	job := m.getStoreJob(monCluster, m.GetBackupJobName(backupName), "backup_mon", env, true, &backup.Destination)
+	job.Spec.Template.Spec.NodeName = nodeName
+	job.SetLabels(map[string]string{
+		ClusterNameLabel: m.Spec.ClusterName,
+		MonBackupLabel:   backupName,
+	})
+
+	return job
+}
+
+// getStoreJob returns a job running cmd against this monitor's store
+func (m *CephMon) getStoreJob(monCluster *CephMonCluster, name, cmd string, env []corev1.EnvVar, readOnly bool,
+	destination *MonBackupDestination) *batchv1.Job {
+
+	job := &batchv1.Job{}
+
+	job.APIVersion = "batch/v1"
+	job.Kind = "Job"
+
+	job.Name = name
+
+	backoffLimit := int32(0)
+	job.Spec.BackoffLimit = &backoffLimit
+
+	container := corev1.Container{}
+	container.Name = "ceph-mon-store"
+	container.Image = monCluster.GetImage().String()
+	container.Env = append([]corev1.EnvVar{
+		corev1.EnvVar{
+			Name:  "CMD",
+			Value: cmd,
+		},
+		corev1.EnvVar{
+			Name:  "MON_ID",
+			Value: m.Spec.ID,
+		},
+		corev1.EnvVar{
+			Name:  "CLUSTER",
+			Value: m.Spec.ClusterName,
+		},
+	}, env...)
+
+	container.VolumeMounts = []corev1.VolumeMount{
+		corev1.VolumeMount{
+			Name:      "ceph-conf",
+			MountPath: "/etc/ceph",
+		},
+		corev1.VolumeMount{
+			Name:      "ceph-mon-data",
+			MountPath: "/mon",
+			ReadOnly:  readOnly,
+		},
+	}
+
+	container.ImagePullPolicy = corev1.PullAlways
+
+	spec := &job.Spec.Template.Spec
+	spec.RestartPolicy = corev1.RestartPolicyNever
+	spec.ServiceAccountName = GetServiceAccountName(m.Spec.ClusterName, CephDaemonTypeMon)
+	spec.Volumes = []corev1.Volume{
+		corev1.Volume{
+			Name: "ceph-mon-data",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: m.GetName(),
+					ReadOnly:  readOnly,
+				},
+			},
+		},
+		corev1.Volume{
+			Name: "ceph-conf",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: monCluster.GetCephConfConfigMapName(),
+					},
+				},
+			},
+		},
+	}
+
+	if destination != nil {
+		destination.ApplyToPodSpec(spec, &container)
+	}
+	spec.Containers = []corev1.Container{container}
+
+	return job
+}
+
+func (m *CephMon) GetPvLabelSelector() *metav1.LabelSelector {
+	return m.Spec.PvSelector.DeepCopy()
+}
+
+func (m *CephMon) GetAPIVersion() string {
+	return m.APIVersion
+}
+
+func (m *CephMon) SetAPIVersion(version string) {
+	m.APIVersion = version
+}
+
+func (m *CephMon) GetKind() string {
+	return m.Kind
+}
+
+func (m *CephMon) SetKind(kind string) {
+	m.Kind = kind
+}
+
+func (c *CephMon) GetMonState() MonState {
+	return c.Status.State
+}
+
+func (c *CephMon) SetMonState(state MonState) {
+	c.Status.State = state
+}
+
+func (c *CephMon) CheckMonState(state ...MonState) bool {
+
+	for _, st := range state {
+		if c.GetMonState() == st {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetPort returns the legacy (msgr1) port
+func (c *CephMon) GetPort() int {
+	if c.Spec.Port == 0 {
+		return DefaultMonV1Port
+	}
+	return c.Spec.Port
+}
+
+// GetV2Port returns the msgr2 port
+func (c *CephMon) GetV2Port() int {
+	if c.Spec.V2Port == 0 {
+		return DefaultMonV2Port
+	}
+	return c.Spec.V2Port
+}
+
+func (c *CephMon) GetAddrVec() MonAddrVec {
+	return NewMonAddrVec(c.GetAddress().String(), c.GetV2Port(), c.GetPort(), false)
+}
+
+func (c *CephMon) GetMonMapEntry() MonMapEntry {
+	return MonMapEntry{
+		IP:               c.GetAddress(),
+		Port:             c.GetPort(),
+		Addrs:            c.GetAddrVec(),
+		StartEpoch:       c.Status.StartEpoch,
+		State:            c.Status.State,
+		InitialMember:    c.Status.InitialMember,
+		OutOfQuorumSince: c.Status.OutOfQuorumSince,
+		NamespacedName: types.NamespacedName{
+			Name:      c.GetName(),
+			Namespace: c.GetNamespace(),
+		},
+	}
+}
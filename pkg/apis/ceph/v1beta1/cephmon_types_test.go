@@ -0,0 +1,48 @@
+package v1beta1
+
+import (
+	"testing"
+)
+
+func TestGetMonIPEnv(t *testing.T) {
+	tests := map[string]struct {
+		ServiceIP        string
+		ExpectedMonIP    string
+		ExpectBindFromIP bool
+	}{
+		"pod-address":     {},
+		"service-address": {ServiceIP: "10.96.0.10", ExpectedMonIP: "10.96.0.10", ExpectBindFromIP: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mon := &CephMon{}
+			mon.Status.PodIP = "10.0.0.1"
+			mon.Status.ServiceIP = test.ServiceIP
+
+			env := map[string]string{}
+			bindFromPod := false
+			for _, e := range mon.getMonIPEnv(NetworkSpec{}) {
+				env[e.Name] = e.Value
+				if e.Name == "MON_BIND_IP" && e.ValueFrom != nil && e.ValueFrom.FieldRef.FieldPath == "status.podIP" {
+					bindFromPod = true
+				}
+			}
+
+			if env["MON_IP"] != test.ExpectedMonIP {
+				t.Errorf("expected MON_IP %q, got %q", test.ExpectedMonIP, env["MON_IP"])
+			}
+			if bindFromPod != test.ExpectBindFromIP {
+				t.Errorf("expected binding to the pod address %t, got %t", test.ExpectBindFromIP, bindFromPod)
+			}
+
+			expectedAddr := test.ServiceIP
+			if expectedAddr == "" {
+				expectedAddr = "10.0.0.1"
+			}
+			if addr := mon.GetMonMapEntry().IP.String(); addr != expectedAddr {
+				t.Errorf("expected the monmap address %s, got %s", expectedAddr, addr)
+			}
+		})
+	}
+}
@@ -0,0 +1,514 @@
+package v1beta1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type JsonMonMap MonMap
+
+type MonMap map[string]MonMapEntry
+
+type MonMapEntry struct {
+	IP               net.IP
+	Port             int
+	Addrs            MonAddrVec
+	StartEpoch       int
+	State            MonState
+	InitialMember    bool
+	OutOfQuorumSince *metav1.Time
+	NamespacedName   types.NamespacedName
+}
+
+// MonAddrType is the messenger protocol version of a monitor address
+type MonAddrType string
+
+const (
+	MonAddrV1 MonAddrType = "v1"
+	MonAddrV2 MonAddrType = "v2"
+)
+
+// MonAddr is a single monitor address, ceph only accepts IP addresses in address vectors
+type MonAddr struct {
+	Type MonAddrType
+	Host string
+	Port int
+}
+
+func (a MonAddr) String() string {
+	return fmt.Sprintf("%s:%s", a.Type, net.JoinHostPort(a.Host, strconv.Itoa(a.Port)))
+}
+
+// MonAddrVec is the list of addresses a monitor listens on, in order of preference
+type MonAddrVec []MonAddr
+
+// String returns the address vector in the [v2:host:port,v1:host:port] form
+func (v MonAddrVec) String() string {
+	addrs := make([]string, 0, len(v))
+	for _, addr := range v {
+		addrs = append(addrs, addr.String())
+	}
+	return fmt.Sprintf("[%s]", strings.Join(addrs, ","))
+}
+
+// NewMonAddrVec returns the msgr2 and, unless msgr2 is required, the legacy address of a monitor on host
+func NewMonAddrVec(host string, v2Port, v1Port int, requireMsgr2 bool) MonAddrVec {
+	addrs := MonAddrVec{MonAddr{Type: MonAddrV2, Host: host, Port: v2Port}}
+	if !requireMsgr2 {
+		addrs = append(addrs, MonAddr{Type: MonAddrV1, Host: host, Port: v1Port})
+	}
+	return addrs
+}
+
+func (m JsonMonMap) MarshalJSON() ([]byte, error) {
+	out := make([]map[string]string, 0, len(m))
+
+	for k, v := range m {
+		out = append(out, map[string]string{
+			"id":    k,
+			"port":  fmt.Sprintf("%d", v.Port),
+			"ip":    v.IP.String(),
+			"addrs": v.Addrs.String(),
+		})
+	}
+
+	return json.Marshal(out)
+}
+
+func (m MonMap) Contains(mon *CephMon) bool {
+	_, ok := m[mon.Spec.ID]
+
+	return ok
+}
+
+func (m MonMap) Empty() bool {
+	return len(m) == 0
+}
+
+func (m MonMap) QuorumAtEpoch(epoch int) bool {
+	atEpoch := 0
+
+	for _, mon := range m {
+		if mon.StartEpoch == epoch {
+			atEpoch++
+		}
+	}
+
+	return atEpoch >= m.QuorumCount()
+}
+
+func (m MonMap) QuorumCount() int {
+	return (len(m) / 2) + 1
+}
+
+// FailureDomainSafe returns false if a failure domain holds more than one monitor and losing
+// it would leave too few monitors for quorum.  domains maps monitor ids to the failure domain
+// they run in.
+func (m MonMap) FailureDomainSafe(domains map[string]string) bool {
+	counts := make(map[string]int)
+	for id := range m {
+		counts[domains[id]]++
+	}
+
+	for _, count := range counts {
+		if count > 1 && len(m)-count < m.QuorumCount() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CanRemove returns true if the monitors left after removing id still form a quorum
+func (m MonMap) CanRemove(id string) bool {
+	remaining := make(MonMap, len(m))
+	for k, v := range m {
+		if k != id {
+			remaining[k] = v
+		}
+	}
+
+	if remaining.Empty() {
+		return false
+	}
+
+	return remaining.CountInState(MonInQuorum) >= remaining.QuorumCount()
+}
+
+// GetRemovalCandidate returns the id of the monitor to remove when scaling down
+func (m MonMap) GetRemovalCandidate() string {
+	ids := make([]string, 0, len(m))
+	for id, e := range m {
+		if e.State != MonInQuorum {
+			return id
+		}
+		ids = append(ids, id)
+	}
+
+	if len(ids) == 0 {
+		return ""
+	}
+
+	sort.Strings(ids)
+	return ids[len(ids)-1]
+}
+
+// GetFailed returns the sorted ids of monitors that have been out of quorum for longer than timeout
+func (m MonMap) GetFailed(now time.Time, timeout time.Duration) []string {
+	failed := make([]string, 0)
+	for id, e := range m {
+		if e.OutOfQuorumSince != nil && now.Sub(e.OutOfQuorumSince.Time) > timeout {
+			failed = append(failed, id)
+		}
+	}
+
+	sort.Strings(failed)
+	return failed
+}
+
+// NextFailureIn returns the time until the next monitor out of quorum reaches timeout
+func (m MonMap) NextFailureIn(now time.Time, timeout time.Duration) (time.Duration, bool) {
+	var next time.Duration
+	found := false
+	for _, e := range m {
+		if e.OutOfQuorumSince == nil {
+			continue
+		}
+		remaining := timeout - now.Sub(e.OutOfQuorumSince.Time)
+		if remaining > 0 && (!found || remaining < next) {
+			next = remaining
+			found = true
+		}
+	}
+
+	return next, found
+}
+
+func (m MonMap) AllInState(state MonState) bool {
+	for _, e := range m {
+		if e.State != state {
+			return false
+		}
+	}
+	return true
+}
+
+// InState returns the monitors in state
+func (m MonMap) InState(state MonState) MonMap {
+	out := make(MonMap)
+	for id, mon := range m {
+		if mon.State == state {
+			out[id] = mon
+		}
+	}
+	return out
+}
+
+// GetIDs returns the sorted monitor ids
+func (m MonMap) GetIDs() []string {
+	ids := make([]string, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (m MonMap) CountInState(state MonState) int {
+	var count int
+	for _, e := range m {
+		if e.State == state {
+			count++
+		}
+	}
+	return count
+}
+
+func (m MonMap) CountInitialMembers() int {
+	var count int
+	for _, e := range m {
+		if e.InitialMember {
+			count++
+		}
+	}
+	return count
+}
+
+func (m MonMap) GetRandomEntry() MonMapEntry {
+	for _, e := range m {
+		return e
+	}
+
+	return MonMapEntry{}
+}
+
+// withMsgr2 returns a copy of the monmap with legacy addresses dropped if msgr2 is required
+func (m MonMap) withMsgr2(msgr2 Msgr2Spec) MonMap {
+	if !msgr2.Require {
+		return m
+	}
+
+	out := make(MonMap, len(m))
+	for id, entry := range m {
+		addrs := MonAddrVec{}
+		for _, addr := range entry.Addrs {
+			if addr.Type == MonAddrV2 {
+				addrs = append(addrs, addr)
+			}
+		}
+		entry.Addrs = addrs
+		out[id] = entry
+	}
+	return out
+}
+
+func (m MonMap) GetInitalMonMap() MonMap {
+	initMonMap := make(MonMap)
+
+	for k, v := range m {
+		if v.InitialMember {
+			initMonMap[k] = v
+		}
+	}
+
+	return initMonMap
+}
+
+const DefaultMonFailureTimeout = 10 * time.Minute
+
+type MonClusterState string
+
+const (
+	MonClusterIdle               MonClusterState = "Idle"
+	MonClusterLaunching          MonClusterState = "Launching"
+	MonClusterEstablishingQuorum MonClusterState = "Establishing Quorum"
+	MonClusterInQuorum           MonClusterState = "In Quorum"
+	MonClusterLostQuorum         MonClusterState = "Lost Quorum"
+	MonClusterRecovering         MonClusterState = "Recovering"
+)
+
+// MonRecoverySpec requests that quorum is rebuilt from a single surviving monitor
+type MonRecoverySpec struct {
+	// Nonce identifies the request, recovery runs once for each new nonce
+	Nonce      string `json:"nonce"`
+	SurvivorID string `json:"survivorId"`
+	// Backup is the name of a backup restored into the survivor's store before its monmap is rebuilt
+	Backup string `json:"backup,omitempty"`
+}
+
+// MonRecoveryStatus records the last completed quorum recovery
+type MonRecoveryStatus struct {
+	// Nonce is the nonce of the completed request
+	Nonce          string      `json:"nonce,omitempty"`
+	SurvivorID     string      `json:"survivorId"`
+	RemovedMonIDs  []string    `json:"removedMonIds"`
+	CompletionTime metav1.Time `json:"completionTime"`
+}
+
+// CephMonClusterSpec defines the desired state of CephMonCluster
+type CephMonClusterSpec struct {
+	ClusterName           string        `json:"clusterName"`
+	Image                 ImageSpec     `json:"image"`
+	CephConfConfigMapName string        `json:"cephConfConfigMapName"`
+	Placement             PlacementSpec `json:"placement"`
+	// Count is the desired number of monitors, monitors are not managed if it is 0
+	Count int `json:"count"`
+	// PvSelector selects the persistent volumes new monitors are placed on
+	PvSelector *metav1.LabelSelector `json:"pvSelector,omitempty"`
+	// FailureTimeout is how long a monitor may be out of quorum before it is replaced
+	FailureTimeout *metav1.Duration `json:"failureTimeout,omitempty"`
+	// Recovery rebuilds quorum from a surviving monitor, it runs once for each nonce and the request is
+	// left in place once status.lastRecovery records its nonce
+	Recovery *MonRecoverySpec `json:"recovery,omitempty"`
+	Backup   *MonBackupSpec   `json:"backup,omitempty"`
+	Msgr2    Msgr2Spec        `json:"msgr2"`
+	Network  NetworkSpec      `json:"network"`
+	// ExternalQuorum is set while monitors outside the mon cluster hold quorum, monitors join the existing
+	// quorum instead of forming one
+	ExternalQuorum bool `json:"externalQuorum,omitempty"`
+}
+
+// CephMonClusterStatus defines the observed state of CephMonCluster
+type CephMonClusterStatus struct {
+	StartEpoch int             `json:"monStartEpoch"`
+	State      MonClusterState `json:"monClusterState"`
+	// InQuorum is the number of monitors in quorum
+	InQuorum       int                `json:"inQuorum"`
+	LastRecovery   *MonRecoveryStatus `json:"lastRecovery,omitempty"`
+	LastBackupTime *metav1.Time       `json:"lastBackupTime,omitempty"`
+	// ActiveBackup is the backup in progress, if any
+	ActiveBackup *MonActiveBackup `json:"activeBackup,omitempty"`
+	// Backups are the retained monitor store backups, oldest first
+	Backups []MonBackupRecord `json:"backups,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephMonCluster is the Schema for the cephmonclusters API
+// +k8s:openapi-gen=true
+type CephMonCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CephMonClusterSpec   `json:"spec,omitempty"`
+	Status CephMonClusterStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephMonClusterList contains a list of CephMonCluster
+type CephMonClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CephMonCluster `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CephMonCluster{}, &CephMonClusterList{})
+}
+
+func (c *CephMonCluster) GetMonMapConfigMap(monMap MonMap) (*corev1.ConfigMap, error) {
+	cm := &corev1.ConfigMap{}
+
+	data := struct {
+		StartEpoch int        `json:"startEpoch"`
+		MonMap     JsonMonMap `json:"monMap"`
+	}{
+		StartEpoch: c.Status.StartEpoch,
+		MonMap:     JsonMonMap(monMap.GetInitalMonMap().withMsgr2(c.Spec.Msgr2)),
+	}
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	cm.Name = c.GetConfigMapName()
+	cm.Data = map[string]string{
+		"jsonMonMap": string(jsonData),
+	}
+
+	return cm, nil
+}
+
+func (c *CephMonCluster) GetConfigMapName() string {
+	return fmt.Sprintf("%s-monmap", c.GetName())
+}
+
+func (c *CephMonCluster) GetMonClusterState() MonClusterState {
+	return c.Status.State
+}
+
+func (c *CephMonCluster) SetMonClusterState(state MonClusterState) {
+	c.Status.State = state
+}
+
+func (c *CephMonCluster) CheckMonClusterState(state ...MonClusterState) bool {
+
+	for _, st := range state {
+		if c.GetMonClusterState() == st {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *CephMonCluster) SetCephClusterName(name string) {
+	c.Spec.ClusterName = name
+}
+
+func (c *CephMonCluster) GetCephClusterName() string {
+	return c.Spec.ClusterName
+}
+
+func (c *CephMonCluster) SetImage(image ImageSpec) {
+	c.Spec.Image = image
+}
+
+func (c *CephMonCluster) GetImage() ImageSpec {
+	return c.Spec.Image
+}
+
+func (c *CephMonCluster) SetCephConfConfigMapName(name string) {
+	c.Spec.CephConfConfigMapName = name
+}
+
+func (c *CephMonCluster) GetCephConfConfigMapName() string {
+	return c.Spec.CephConfConfigMapName
+}
+
+func (c *CephMonCluster) SetPlacement(placement PlacementSpec) {
+	c.Spec.Placement = placement
+}
+
+func (c *CephMonCluster) GetPlacement() PlacementSpec {
+	return c.Spec.Placement
+}
+
+func (c *CephMonCluster) SetNetwork(network NetworkSpec) {
+	c.Spec.Network = network
+}
+
+func (c *CephMonCluster) GetFailureTimeout() time.Duration {
+	if c.Spec.FailureTimeout == nil {
+		return DefaultMonFailureTimeout
+	}
+	return c.Spec.FailureTimeout.Duration
+}
+
+// RecoveryRequested returns true if the recovery spec holds a nonce that hasn't been recovered yet
+func (c *CephMonCluster) RecoveryRequested() bool {
+	if c.Spec.Recovery == nil || c.Spec.Recovery.Nonce == "" {
+		return false
+	}
+	return c.Status.LastRecovery == nil || c.Status.LastRecovery.Nonce != c.Spec.Recovery.Nonce
+}
+
+// AddBackup records a completed backup and returns the records that are no longer retained
+func (c *CephMonCluster) AddBackup(record MonBackupRecord, retain int) []MonBackupRecord {
+	c.Status.Backups = append(c.Status.Backups, record)
+	sort.SliceStable(c.Status.Backups, func(i, j int) bool {
+		return c.Status.Backups[i].CompletionTime.Before(&c.Status.Backups[j].CompletionTime)
+	})
+
+	if len(c.Status.Backups) <= retain {
+		return nil
+	}
+
+	pruned := c.Status.Backups[:len(c.Status.Backups)-retain]
+	c.Status.Backups = c.Status.Backups[len(c.Status.Backups)-retain:]
+	return pruned
+}
+
+// GetBackup returns the named backup record
+func (c *CephMonCluster) GetBackup(name string) (MonBackupRecord, bool) {
+	for _, record := range c.Status.Backups {
+		if record.Name == name {
+			return record, true
+		}
+	}
+	return MonBackupRecord{}, false
+}
+
+func (c *CephMonCluster) GetDaemonType() CephDaemonType {
+	return CephDaemonType("mon")
+}
+
+func (c *CephMonClusterList) AllInState(state MonClusterState) bool {
+	for _, e := range c.Items {
+		if e.GetMonClusterState() != state {
+			return false
+		}
+	}
+	return true
+}
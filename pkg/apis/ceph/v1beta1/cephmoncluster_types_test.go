@@ -0,0 +1,165 @@
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFailureDomainSafe(t *testing.T) {
+	threeMons := MonMap{"a": MonMapEntry{}, "b": MonMapEntry{}, "c": MonMapEntry{}}
+
+	testCases := []struct {
+		Name     string
+		MonMap   MonMap
+		Domains  map[string]string
+		Expected bool
+	}{
+		{
+			Name:     "one-per-node",
+			MonMap:   threeMons,
+			Domains:  map[string]string{"a": "node1", "b": "node2", "c": "node3"},
+			Expected: true,
+		},
+		{
+			Name:     "two-on-one-node",
+			MonMap:   threeMons,
+			Domains:  map[string]string{"a": "node1", "b": "node1", "c": "node3"},
+			Expected: false,
+		},
+		{
+			Name: "five-mons-two-per-zone",
+			MonMap: MonMap{"a": MonMapEntry{}, "b": MonMapEntry{}, "c": MonMapEntry{},
+				"d": MonMapEntry{}, "e": MonMapEntry{}},
+			Domains:  map[string]string{"a": "zone1", "b": "zone1", "c": "zone2", "d": "zone2", "e": "zone3"},
+			Expected: true,
+		},
+		{
+			Name:     "single-mon",
+			MonMap:   MonMap{"a": MonMapEntry{}},
+			Domains:  map[string]string{"a": "node1"},
+			Expected: true,
+		},
+		{
+			Name:     "two-mons-one-node",
+			MonMap:   MonMap{"a": MonMapEntry{}, "b": MonMapEntry{}},
+			Domains:  map[string]string{"a": "node1", "b": "node1"},
+			Expected: false,
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(st *testing.T) {
+			if safe := c.MonMap.FailureDomainSafe(c.Domains); safe != c.Expected {
+				st.Errorf("Got %t expected %t", safe, c.Expected)
+			}
+		})
+	}
+}
+
+func TestCanRemove(t *testing.T) {
+	monMap := MonMap{
+		"a": MonMapEntry{State: MonInQuorum},
+		"b": MonMapEntry{State: MonInQuorum},
+		"c": MonMapEntry{State: MonError},
+	}
+
+	if !monMap.CanRemove("c") {
+		t.Errorf("Expected to be able to remove the monitor out of quorum")
+	}
+
+	if monMap.CanRemove("a") {
+		t.Errorf("Removing an in quorum monitor should leave the cluster without quorum")
+	}
+
+	if (MonMap{"a": MonMapEntry{State: MonInQuorum}}).CanRemove("a") {
+		t.Errorf("Should not be able to remove the last monitor")
+	}
+}
+
+func TestGetFailed(t *testing.T) {
+	now := time.Now()
+	longAgo := metav1.NewTime(now.Add(-time.Hour))
+	recent := metav1.NewTime(now.Add(-time.Minute))
+
+	monMap := MonMap{
+		"a": MonMapEntry{State: MonInQuorum},
+		"b": MonMapEntry{State: MonWaitForPodRun, OutOfQuorumSince: &longAgo},
+		"c": MonMapEntry{State: MonError, OutOfQuorumSince: &recent},
+	}
+
+	failed := monMap.GetFailed(now, 10*time.Minute)
+	if diff := deep.Equal(failed, []string{"b"}); len(diff) > 0 {
+		for _, l := range diff {
+			t.Error(l)
+		}
+	}
+
+	next, ok := monMap.NextFailureIn(now, 10*time.Minute)
+	if !ok || next != 9*time.Minute {
+		t.Errorf("Got next failure in %s expected 9m", next)
+	}
+}
+
+func TestAddBackup(t *testing.T) {
+	now := time.Now()
+	monCluster := &CephMonCluster{}
+	for i, name := range []string{"b", "a", "c"} {
+		record := MonBackupRecord{Name: name, CompletionTime: metav1.NewTime(now.Add(time.Duration(i) * time.Hour))}
+		if name == "a" {
+			record.CompletionTime = metav1.NewTime(now.Add(-time.Hour))
+		}
+		monCluster.AddBackup(record, 3)
+	}
+
+	pruned := monCluster.AddBackup(MonBackupRecord{Name: "d", CompletionTime: metav1.NewTime(now.Add(3 * time.Hour))}, 2)
+
+	names := make([]string, 0, len(monCluster.Status.Backups))
+	for _, record := range monCluster.Status.Backups {
+		names = append(names, record.Name)
+	}
+
+	if diff := deep.Equal(names, []string{"c", "d"}); len(diff) > 0 {
+		for _, l := range diff {
+			t.Error(l)
+		}
+	}
+
+	if len(pruned) != 2 || pruned[0].Name != "a" || pruned[1].Name != "b" {
+		t.Errorf("Expected the oldest backups to be pruned, got %v", pruned)
+	}
+}
+
+func TestMonAddrVecString(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		AddrVec  MonAddrVec
+		Expected string
+	}{
+		{
+			Name:     "dual",
+			AddrVec:  NewMonAddrVec("10.0.0.1", 3300, 6789, false),
+			Expected: "[v2:10.0.0.1:3300,v1:10.0.0.1:6789]",
+		},
+		{
+			Name:     "msgr2-only",
+			AddrVec:  NewMonAddrVec("10.0.0.1", 3300, 6789, true),
+			Expected: "[v2:10.0.0.1:3300]",
+		},
+		{
+			Name:     "ipv6",
+			AddrVec:  NewMonAddrVec("fd00::1", 3300, 6789, false),
+			Expected: "[v2:[fd00::1]:3300,v1:[fd00::1]:6789]",
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(st *testing.T) {
+			if s := c.AddrVec.String(); s != c.Expected {
+				st.Errorf("Got %s expected %s", s, c.Expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+package v1beta1
+
+import (
+	"fmt"
+	"strconv"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CephOsdSpec defines the desired state of CephOsd
+type CephOsdSpec struct {
+	ID          int                   `json:"id"`
+	ClusterName string                `json:"clusterName"`
+	PvSelector  *metav1.LabelSelector `json:"pvSelector,omitempty"`
+	Disabled    bool                  `json:"disabled"`
+	// ReclaimPolicy overrides the cluster's reclaim policy for the osd's PVC
+	ReclaimPolicy ReclaimPolicy `json:"reclaimPolicy,omitempty"`
+}
+
+// CephOsdState describes the state of the osd
+type CephOsdState string
+
+const (
+	CephOsdStateIdle          CephOsdState = "Idle"
+	CephOsdStatePreparing     CephOsdState = "Preparing"
+	CephOsdStatePrepareFailed CephOsdState = "Prepare Failed"
+	CephOsdStatePrepared      CephOsdState = "Prepared"
+)
+
+// CephOsdStatus defines the observed state of CephOsd
+type CephOsdStatus struct {
+	State   CephOsdState `json:"state"`
+	OsdFsid string       `json:"osdFsid"`
+	OsdID   int          `json:"osdId"`
+	// FailureDomain is the value of the cluster's osd failure domain label on the node the osd runs on
+	FailureDomain string `json:"failureDomain,omitempty"`
+	// NodeName is the node the osd runs on, it's kept while the osd is stopped for maintenance
+	NodeName string `json:"nodeName,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephOsd is the Schema for the cephosds API
+// +k8s:openapi-gen=true
+type CephOsd struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CephOsdSpec   `json:"spec,omitempty"`
+	Status CephOsdStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephOsdList contains a list of CephOsd
+type CephOsdList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CephOsd `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CephOsd{}, &CephOsdList{})
+}
+
+func (o *CephOsd) GetDisabled() bool {
+	return o.Spec.Disabled
+}
+
+func (o *CephOsd) GetVolumeClaimTemplate() (*corev1.PersistentVolumeClaim, error) {
+	pvc := &corev1.PersistentVolumeClaim{}
+	blockMode := corev1.PersistentVolumeBlock
+
+	pvc.APIVersion = "v1"
+	pvc.Kind = "PersistentVolumeCliam"
+
+	pvc.Name = o.GetName()
+
+	pvc.Spec.Selector = o.GetPvLabelSelector()
+	pvc.Spec.VolumeMode = &blockMode
+
+	pvc.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{
+		corev1.ReadWriteOnce,
+	}
+	storageClass := "local-storage"
+	pvc.Spec.StorageClassName = &storageClass
+
+	qty := resource.NewQuantity(100000, resource.DecimalSI)
+	pvc.Spec.Resources = corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceStorage: *qty,
+		},
+	}
+
+	return pvc, nil
+}
+
+func (o *CephOsd) GetPodName() string {
+	return fmt.Sprintf("ceph-%s-osd.%d", o.Spec.ClusterName, o.Spec.ID)
+}
+
+func (o *CephOsd) GetPrepareJobName() string {
+	return fmt.Sprintf("ceph-%s-osd.%d-prepare", o.Spec.ClusterName, o.Spec.ID)
+}
+
+// GetPrepareJob returns a one-shot job that prepares the osd's volume.  The
+// job reports the resulting osd fsid and id through its termination message.
+func (o *CephOsd) GetPrepareJob(osdImage, cephConfConfigMap, serviceAccountName string, network NetworkSpec) *batchv1.Job {
+	job := &batchv1.Job{}
+
+	job.APIVersion = "batch/v1"
+	job.Kind = "Job"
+
+	job.Name = o.GetPrepareJobName()
+
+	backoffLimit := int32(3)
+	job.Spec.BackoffLimit = &backoffLimit
+
+	container := o.getContainer(osdImage, "prepare_osd")
+	container.TerminationMessagePath = corev1.TerminationMessagePathDefault
+
+	job.Spec.Template.Spec.ServiceAccountName = serviceAccountName
+	job.Spec.Template.Spec.RestartPolicy = corev1.RestartPolicyNever
+	job.Spec.Template.Spec.Containers = []corev1.Container{container}
+	job.Spec.Template.Spec.Volumes = o.getVolumes(cephConfConfigMap)
+
+	network.ApplyToPodTemplate(&job.Spec.Template.ObjectMeta, &job.Spec.Template.Spec, false)
+
+	return job
+}
+
+func (o *CephOsd) GetPod(osdImage, cephConfConfigMap, serviceAccountName string, network NetworkSpec) *corev1.Pod {
+	pod := &corev1.Pod{}
+
+	pod.APIVersion = "v1"
+	pod.Kind = "Pod"
+
+	pod.Name = o.GetPodName()
+
+	pod.SetLabels(o.GetPodLabels())
+
+	container := o.getContainer(osdImage, "start_osd")
+	container.Env = append(container.Env,
+		corev1.EnvVar{
+			Name:  "OSD_FSID",
+			Value: o.Status.OsdFsid,
+		},
+	)
+
+	pod.Spec.ServiceAccountName = serviceAccountName
+
+	pod.Spec.Containers = []corev1.Container{container}
+
+	pod.Spec.Volumes = o.getVolumes(cephConfConfigMap)
+
+	network.ApplyToPodTemplate(&pod.ObjectMeta, &pod.Spec, true)
+
+	return pod
+}
+
+// GetPodLabels returns the labels of the osd's pod, including its failure domain once it's known
+func (o *CephOsd) GetPodLabels() map[string]string {
+	labels := map[string]string{
+		ClusterNameLabel: o.Spec.ClusterName,
+		DaemonTypeLabel:  CephDaemonTypeOsd.String(),
+	}
+	if o.Status.FailureDomain != "" {
+		labels[FailureDomainLabel] = o.Status.FailureDomain
+	}
+	return labels
+}
+
+func (o *CephOsd) getContainer(osdImage, cmd string) corev1.Container {
+	container := corev1.Container{}
+	container.Name = "ceph-osd"
+	container.Image = osdImage
+	container.Env = []corev1.EnvVar{
+		corev1.EnvVar{
+			Name:  "CMD",
+			Value: cmd,
+		},
+		corev1.EnvVar{
+			Name:  "CLUSTER",
+			Value: o.Spec.ClusterName,
+		},
+		corev1.EnvVar{
+			Name:  "OSD_ID",
+			Value: strconv.Itoa(o.Spec.ID),
+		},
+		corev1.EnvVar{
+			Name: "NODE_NAME",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: "spec.nodeName",
+				},
+			},
+		},
+	}
+
+	container.VolumeDevices = []corev1.VolumeDevice{
+		corev1.VolumeDevice{
+			Name:       "ceph-osd-data",
+			DevicePath: "/dev/osd",
+		},
+	}
+
+	container.VolumeMounts = []corev1.VolumeMount{
+		corev1.VolumeMount{
+			Name:      "ceph-conf",
+			MountPath: "/etc/ceph",
+		},
+	}
+
+	container.ImagePullPolicy = corev1.PullAlways
+
+	return container
+}
+
+func (o *CephOsd) getVolumes(cephConfConfigMap string) []corev1.Volume {
+	return []corev1.Volume{
+		corev1.Volume{
+			Name: "ceph-osd-data",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: o.GetName(),
+				},
+			},
+		},
+		corev1.Volume{
+			Name: "ceph-conf",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: cephConfConfigMap,
+					},
+				},
+			},
+		},
+	}
+}
+
+func (o *CephOsd) GetPvLabelSelector() *metav1.LabelSelector {
+	return o.Spec.PvSelector.DeepCopy()
+}
+
+func (o *CephOsd) GetAPIVersion() string {
+	return o.APIVersion
+}
+
+func (o *CephOsd) SetAPIVersion(version string) {
+	o.APIVersion = version
+}
+
+func (o *CephOsd) GetKind() string {
+	return o.Kind
+}
+
+func (o *CephOsd) SetKind(kind string) {
+	o.Kind = kind
+}
+
+func (o *CephOsd) GetState() CephOsdState {
+	return o.Status.State
+}
+
+func (o *CephOsd) SetState(s CephOsdState) {
+	o.Status.State = s
+}
@@ -0,0 +1,70 @@
+package v1beta1
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// GetClientPodName returns the name of the cluster's client pod
+func (c *CephCluster) GetClientPodName() string {
+	return fmt.Sprintf("ceph-%s-client", c.GetName())
+}
+
+// GetClientPod returns a pod that idles with the cluster's ceph.conf and the keyring in the named secret
+// mounted.  The operator runs the ceph cli in it to reach monitors it doesn't run itself, those of an
+// imported cluster before its first monitor joins and those of an external cluster.
+func (c *CephCluster) GetClientPod(keyringSecretName string) *corev1.Pod {
+	pod := &corev1.Pod{}
+	pod.APIVersion = "v1"
+	pod.Kind = "Pod"
+	pod.Name = c.GetClientPodName()
+	pod.Namespace = c.GetNamespace()
+	pod.SetLabels(map[string]string{
+		ClusterNameLabel: c.GetName(),
+	})
+
+	container := corev1.Container{
+		Name:    "ceph-client",
+		Image:   c.GetMonImage(),
+		Command: []string{"sleep", "infinity"},
+		VolumeMounts: []corev1.VolumeMount{
+			corev1.VolumeMount{
+				Name:      "ceph-conf",
+				MountPath: "/etc/ceph",
+			},
+			corev1.VolumeMount{
+				Name:      "client-keyring",
+				MountPath: "/keyrings/client.admin",
+			},
+		},
+	}
+
+	// sleep ignores SIGTERM, there's nothing to shut down
+	var gracePeriod int64
+	pod.Spec.TerminationGracePeriodSeconds = &gracePeriod
+	pod.Spec.Containers = []corev1.Container{container}
+	pod.Spec.Volumes = []corev1.Volume{
+		corev1.Volume{
+			Name: "ceph-conf",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: c.GetCephConfigMapName(),
+					},
+				},
+			},
+		},
+		corev1.Volume{
+			Name: "client-keyring",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: keyringSecretName,
+				},
+			},
+		},
+	}
+
+	c.Spec.Network.ApplyToPodTemplate(&pod.ObjectMeta, &pod.Spec, false)
+	return pod
+}
@@ -0,0 +1,21 @@
+package v1beta1
+
+// v1beta1 is the storage version, older api versions are converted to and from it.
+
+// Hub marks CephCluster as a conversion hub
+func (*CephCluster) Hub() {}
+
+// Hub marks CephMonCluster as a conversion hub
+func (*CephMonCluster) Hub() {}
+
+// Hub marks CephMon as a conversion hub
+func (*CephMon) Hub() {}
+
+// Hub marks CephOsd as a conversion hub
+func (*CephOsd) Hub() {}
+
+// Hub marks CephDaemonCluster as a conversion hub
+func (*CephDaemonCluster) Hub() {}
+
+// Hub marks CephDaemon as a conversion hub
+func (*CephDaemon) Hub() {}
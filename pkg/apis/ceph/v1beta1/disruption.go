@@ -0,0 +1,114 @@
+package v1beta1
+
+import (
+	"fmt"
+
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// FailureDomainLabel records the failure domain of the node an osd runs on, osd disruption budgets select
+// osd pods by it
+const FailureDomainLabel = "ceph.k8s.pgc.umn.edu/failureDomain"
+
+// GetOsdFailureDomainKey returns the node label that defines an osd failure domain, defaulting to the hostname
+func (c *CephCluster) GetOsdFailureDomainKey() string {
+	if c.Spec.OsdFailureDomainKey == "" {
+		return DefaultTopologyKey
+	}
+	return c.Spec.OsdFailureDomainKey
+}
+
+// GetMonDisruptionBudget returns the budget allowing as many monitors to be evicted as the monmap can lose
+// without losing quorum
+func (c *CephCluster) GetMonDisruptionBudget(monMap MonMap) *policyv1beta1.PodDisruptionBudget {
+	maxUnavailable := len(monMap) - monMap.QuorumCount()
+	if maxUnavailable < 0 {
+		maxUnavailable = 0
+	}
+	return c.getDisruptionBudget(c.getDisruptionBudgetName(CephDaemonTypeMon), CephDaemonTypeMon, nil, maxUnavailable)
+}
+
+// GetDaemonDisruptionBudget returns the budget for a mgr or mds daemon cluster.  A daemon can only be evicted
+// while a standby is able to take over, so a daemon cluster without standbys doesn't get a budget.
+func (c *CephCluster) GetDaemonDisruptionBudget(daemonType CephDaemonType, replicas int) *policyv1beta1.PodDisruptionBudget {
+	if replicas < 2 {
+		return nil
+	}
+	return c.getDisruptionBudget(c.getDisruptionBudgetName(daemonType), daemonType, nil, 1)
+}
+
+// GetOsdDisruptionBudget returns the budget covering every osd of the cluster
+func (c *CephCluster) GetOsdDisruptionBudget(maxUnavailable int) *policyv1beta1.PodDisruptionBudget {
+	return c.getDisruptionBudget(c.getDisruptionBudgetName(CephDaemonTypeOsd), CephDaemonTypeOsd, nil, maxUnavailable)
+}
+
+// GetOsdDomainDisruptionBudget returns the budget for the osds in a failure domain, its name ends with a hash of
+// the domain so domains that sanitize to the same name get their own budgets
+func (c *CephCluster) GetOsdDomainDisruptionBudget(domain string, maxUnavailable int) *policyv1beta1.PodDisruptionBudget {
+	name := fmt.Sprintf("%s-%s-%s", c.getDisruptionBudgetName(CephDaemonTypeOsd), sanitizeNamePart(domain), shortHash(domain))
+	return c.getDisruptionBudget(name, CephDaemonTypeOsd, map[string]string{FailureDomainLabel: domain}, maxUnavailable)
+}
+
+func (c *CephCluster) getDisruptionBudgetName(daemonType CephDaemonType) string {
+	return fmt.Sprintf("ceph-%s-%s", c.GetName(), daemonType)
+}
+
+func (c *CephCluster) getDisruptionBudget(name string, daemonType CephDaemonType, selector map[string]string,
+	maxUnavailable int) *policyv1beta1.PodDisruptionBudget {
+
+	labels := map[string]string{
+		ClusterNameLabel: c.GetName(),
+		DaemonTypeLabel:  daemonType.String(),
+	}
+
+	matchLabels := map[string]string{}
+	for k, v := range labels {
+		matchLabels[k] = v
+	}
+	for k, v := range selector {
+		matchLabels[k] = v
+		labels[k] = v
+	}
+
+	unavailable := intstr.FromInt(maxUnavailable)
+
+	pdb := &policyv1beta1.PodDisruptionBudget{}
+	pdb.Name = name
+	pdb.Namespace = c.GetNamespace()
+	pdb.SetLabels(labels)
+	pdb.Spec.Selector = &metav1.LabelSelector{MatchLabels: matchLabels}
+	pdb.Spec.MaxUnavailable = &unavailable
+	return pdb
+}
+
+// GetOsdDisruptionBudgets returns the budgets of the cluster's osds.  While every osd is up, a single budget
+// allows one osd to be evicted if ceph is healthy, and none while it's recovering.  Once a failure domain is
+// disrupted, the cluster wide budget is replaced by a budget per failure domain, allowing evictions only from
+// disrupted domains until their osds are back.  A pod covered by more than one budget can't be evicted, so the
+// two kinds of budget are never returned together.  domains maps each failure domain to its osd count and
+// disrupted lists domains with osds that are down.
+func (c *CephCluster) GetOsdDisruptionBudgets(domains map[string]int, disrupted map[string]bool, healthy bool) []*policyv1beta1.PodDisruptionBudget {
+	if len(domains) == 0 {
+		return nil
+	}
+
+	if len(disrupted) == 0 {
+		maxUnavailable := 0
+		if healthy {
+			maxUnavailable = 1
+		}
+		return []*policyv1beta1.PodDisruptionBudget{c.GetOsdDisruptionBudget(maxUnavailable)}
+	}
+
+	budgets := make([]*policyv1beta1.PodDisruptionBudget, 0, len(domains))
+	for domain, count := range domains {
+		maxUnavailable := 0
+		if disrupted[domain] {
+			maxUnavailable = count
+		}
+		budgets = append(budgets, c.GetOsdDomainDisruptionBudget(domain, maxUnavailable))
+	}
+	return budgets
+}
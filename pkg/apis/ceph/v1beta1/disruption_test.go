@@ -0,0 +1,129 @@
+package v1beta1
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+func TestMonDisruptionBudget(t *testing.T) {
+	cluster := &CephCluster{}
+	cluster.Name = "test"
+
+	cases := []struct {
+		Monitors       int
+		MaxUnavailable int
+	}{
+		{Monitors: 1, MaxUnavailable: 0},
+		{Monitors: 2, MaxUnavailable: 0},
+		{Monitors: 3, MaxUnavailable: 1},
+		{Monitors: 5, MaxUnavailable: 2},
+	}
+
+	for _, c := range cases {
+		monMap := MonMap{}
+		for i := 0; i < c.Monitors; i++ {
+			monMap[string(rune('a'+i))] = MonMapEntry{}
+		}
+
+		pdb := cluster.GetMonDisruptionBudget(monMap)
+		if pdb.Spec.MaxUnavailable.IntValue() != c.MaxUnavailable {
+			t.Errorf("%d monitors: expected %d unavailable, got %s", c.Monitors, c.MaxUnavailable, pdb.Spec.MaxUnavailable.String())
+		}
+	}
+}
+
+func TestDaemonDisruptionBudget(t *testing.T) {
+	cluster := &CephCluster{}
+	cluster.Name = "test"
+
+	if cluster.GetDaemonDisruptionBudget(CephDaemonTypeMgr, 1) != nil {
+		t.Errorf("expected no budget for a daemon without standbys")
+	}
+
+	pdb := cluster.GetDaemonDisruptionBudget(CephDaemonTypeMds, 3)
+	if pdb == nil || pdb.Spec.MaxUnavailable.IntValue() != 1 {
+		t.Errorf("expected one mds to be evictable while standbys exist, got %v", pdb)
+	}
+}
+
+func TestOsdDisruptionBudgets(t *testing.T) {
+	cluster := &CephCluster{}
+	cluster.Name = "test"
+
+	domains := map[string]int{"node-a": 2, "node_b": 3, "node-c": 1}
+
+	cases := []struct {
+		Name      string
+		Domains   map[string]int
+		Disrupted map[string]bool
+		Healthy   bool
+		Expected  map[string]int
+	}{
+		{
+			Name:     "healthy",
+			Domains:  domains,
+			Healthy:  true,
+			Expected: map[string]int{"ceph-test-osd": 1},
+		},
+		{
+			Name:     "recovering",
+			Domains:  domains,
+			Expected: map[string]int{"ceph-test-osd": 0},
+		},
+		{
+			Name:      "disrupted",
+			Domains:   domains,
+			Disrupted: map[string]bool{"node_b": true},
+			Healthy:   true,
+			Expected: map[string]int{
+				"ceph-test-osd-node-a-66570ff0": 0, "ceph-test-osd-node-b-b2299682": 3, "ceph-test-osd-node-c-092cd5e2": 0,
+			},
+		},
+		{
+			Name:     "no-osds",
+			Healthy:  true,
+			Expected: map[string]int{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			disrupted := c.Disrupted
+			if disrupted == nil {
+				disrupted = map[string]bool{}
+			}
+
+			budgets := map[string]int{}
+			for _, pdb := range cluster.GetOsdDisruptionBudgets(c.Domains, disrupted, c.Healthy) {
+				budgets[pdb.GetName()] = pdb.Spec.MaxUnavailable.IntValue()
+			}
+
+			if diff := deep.Equal(budgets, c.Expected); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+
+	pdb := cluster.GetOsdDomainDisruptionBudget("node_b", 3)
+	if pdb.Spec.Selector.MatchLabels[FailureDomainLabel] != "node_b" {
+		t.Errorf("expected the failure domain budget to select the domain's osds, got %v", pdb.Spec.Selector)
+	}
+	if _, ok := cluster.GetOsdDisruptionBudget(1).Spec.Selector.MatchLabels[FailureDomainLabel]; ok {
+		t.Errorf("expected the cluster wide budget to select every osd")
+	}
+
+	names := map[string]string{}
+	for _, domain := range []string{"node_b", "node-b", "Node.B", "rack 1/row 2", strings.Repeat("long-domain-", 10)} {
+		name := cluster.GetOsdDomainDisruptionBudget(domain, 1).GetName()
+		if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+			t.Errorf("invalid budget name %s for domain %q: %v", name, domain, errs)
+		}
+		if other, ok := names[name]; ok {
+			t.Errorf("domains %q and %q share the budget name %s", domain, other, name)
+		}
+		names[name] = domain
+	}
+}
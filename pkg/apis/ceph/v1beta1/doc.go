@@ -0,0 +1,4 @@
+// Package v1beta1 contains API Schema definitions for the ceph v1beta1 API group
+// +k8s:deepcopy-gen=package,register
+// +groupName=ceph.k8s.pgc.umn.edu
+package v1beta1
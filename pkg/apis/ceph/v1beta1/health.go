@@ -0,0 +1,81 @@
+package v1beta1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultHealthCheckInterval is the time between polls of ceph health if healthCheckInterval is unset
+const DefaultHealthCheckInterval = time.Minute
+
+// CephClusterHealth summarizes the health of the cluster reported by ceph
+type CephClusterHealth struct {
+	// Status is HEALTH_OK, HEALTH_WARN or HEALTH_ERR
+	Status string `json:"status,omitempty"`
+	// Checks are the active health checks
+	Checks []CephHealthCheck `json:"checks,omitempty"`
+	// Capacity is the raw capacity of the cluster
+	Capacity CephCapacity `json:"capacity,omitempty"`
+	// Pgs is the number of placement groups
+	Pgs int `json:"pgs,omitempty"`
+	// PgStates counts the placement groups in each state
+	PgStates map[string]int `json:"pgStates,omitempty"`
+	// Osds counts the osds in the osd map
+	Osds CephOsdCounts `json:"osds,omitempty"`
+	// QuorumMembers are the monitors in quorum
+	QuorumMembers []string `json:"quorumMembers,omitempty"`
+	// LastUpdated is the time of the last poll
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+	// Error is the reason the last poll failed, the rest of the summary is from the last successful poll
+	Error string `json:"error,omitempty"`
+}
+
+// CephHealthCheck is an active ceph health check
+type CephHealthCheck struct {
+	Name     string `json:"name"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// CephCapacity is the raw capacity of the cluster in bytes
+type CephCapacity struct {
+	TotalBytes     int64 `json:"totalBytes"`
+	UsedBytes      int64 `json:"usedBytes"`
+	AvailableBytes int64 `json:"availableBytes"`
+}
+
+// CephOsdCounts counts the osds that exist, are up and are in
+type CephOsdCounts struct {
+	Total int `json:"total"`
+	Up    int `json:"up"`
+	In    int `json:"in"`
+}
+
+// HasCheck returns true if any of the named health checks is active
+func (h *CephClusterHealth) HasCheck(names ...string) bool {
+	for _, check := range h.Checks {
+		for _, name := range names {
+			if check.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetHealthCheckInterval returns the time between polls of ceph health
+func (c *CephCluster) GetHealthCheckInterval() time.Duration {
+	if c.Spec.HealthCheckInterval == nil {
+		return DefaultHealthCheckInterval
+	}
+	return c.Spec.HealthCheckInterval.Duration
+}
+
+// HealthCheckDue returns the time until ceph health should next be polled, it's zero or less if a poll is due
+func (c *CephCluster) HealthCheckDue(now time.Time) time.Duration {
+	if c.Status.Health == nil || c.Status.Health.LastUpdated == nil {
+		return 0
+	}
+	return c.Status.Health.LastUpdated.Add(c.GetHealthCheckInterval()).Sub(now)
+}
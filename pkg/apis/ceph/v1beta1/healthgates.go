@@ -0,0 +1,104 @@
+package v1beta1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SkipHealthGatesAnnotation lets every transition of the cluster proceed without waiting on its health gates
+// while set to true
+const SkipHealthGatesAnnotation = "ceph.k8s.pgc.umn.edu/skip-health-gates"
+
+// DefaultHealthGateTimeout is how long an enabled gate holds a transition if its timeout is unset
+const DefaultHealthGateTimeout = 10 * time.Minute
+
+// HealthGate is a ceph health condition a state transition waits for
+type HealthGate string
+
+const (
+	// HealthGateMgrAvailable waits for an available mgr before starting osds
+	HealthGateMgrAvailable HealthGate = "MgrAvailable"
+	// HealthGatePgsActive waits for every placement group to be active before declaring the cluster running
+	HealthGatePgsActive HealthGate = "PgsActive"
+	// HealthGateNoDegradedObjects waits for ceph to report no degraded objects before a shutdown stops any daemon,
+	// while the mgr still reports placement group stats and every osd is up
+	HealthGateNoDegradedObjects HealthGate = "NoDegradedObjects"
+)
+
+// HealthGateSpec configures a health gate
+type HealthGateSpec struct {
+	// Enabled makes the transition wait for the gate
+	Enabled bool `json:"enabled,omitempty"`
+	// Timeout is how long the transition waits before proceeding anyway, ten minutes if unset and forever if zero
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// HealthGatesSpec configures the health gates of the cluster's state transitions, every gate is disabled by
+// default
+type HealthGatesSpec struct {
+	MgrAvailable      HealthGateSpec `json:"mgrAvailable,omitempty"`
+	PgsActive         HealthGateSpec `json:"pgsActive,omitempty"`
+	NoDegradedObjects HealthGateSpec `json:"noDegradedObjects,omitempty"`
+}
+
+// Get returns the configuration of the gate
+func (s HealthGatesSpec) Get(gate HealthGate) HealthGateSpec {
+	switch gate {
+	case HealthGateMgrAvailable:
+		return s.MgrAvailable
+	case HealthGatePgsActive:
+		return s.PgsActive
+	case HealthGateNoDegradedObjects:
+		return s.NoDegradedObjects
+	}
+	return HealthGateSpec{}
+}
+
+// CephClusterTransitionBlock records a transition held back by a health gate
+type CephClusterTransitionBlock struct {
+	// Gate is the health gate holding the transition
+	Gate HealthGate `json:"gate"`
+	// NextState is the state the cluster is waiting to transition to
+	NextState CephClusterState `json:"nextState"`
+	// Reason is why the gate isn't satisfied
+	Reason string `json:"reason"`
+	// Since is when the gate started holding the transition
+	Since metav1.Time `json:"since"`
+}
+
+// GetHealthGate returns the gate guarding the transition between the states, if there is one
+func GetHealthGate(current, next CephClusterState) (HealthGate, bool) {
+	switch {
+	case current == CephClusterStartDaemons && next == CephClusterStartOsds:
+		return HealthGateMgrAvailable, true
+	case current == CephClusterStartOsds && next == CephClusterRunning:
+		return HealthGatePgsActive, true
+	case current == CephClusterShutdown && next == CephClusterStopDaemons:
+		return HealthGateNoDegradedObjects, true
+	}
+	return "", false
+}
+
+// HealthGateEnabled returns true if transitions wait for the gate, the skip annotation disables every gate
+func (c *CephCluster) HealthGateEnabled(gate HealthGate) bool {
+	if c.GetAnnotations()[SkipHealthGatesAnnotation] == "true" {
+		return false
+	}
+	return c.Spec.HealthGates.Get(gate).Enabled
+}
+
+// GetHealthGateTimeout returns how long the gate holds a transition, zero if it never times out
+func (c *CephCluster) GetHealthGateTimeout(gate HealthGate) time.Duration {
+	spec := c.Spec.HealthGates.Get(gate)
+	if spec.Timeout == nil {
+		return DefaultHealthGateTimeout
+	}
+	return spec.Timeout.Duration
+}
+
+// HealthGateTimedOut returns true if the block has held the transition longer than the gate's timeout
+func (c *CephCluster) HealthGateTimedOut(block *CephClusterTransitionBlock, now time.Time) bool {
+	timeout := c.GetHealthGateTimeout(block.Gate)
+	return timeout > 0 && now.Sub(block.Since.Time) >= timeout
+}
@@ -0,0 +1,71 @@
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetHealthGate(t *testing.T) {
+	cases := []struct {
+		Current  CephClusterState
+		Next     CephClusterState
+		Expected HealthGate
+		Gated    bool
+	}{
+		{Current: CephClusterStartDaemons, Next: CephClusterStartOsds, Expected: HealthGateMgrAvailable, Gated: true},
+		{Current: CephClusterStartOsds, Next: CephClusterRunning, Expected: HealthGatePgsActive, Gated: true},
+		{Current: CephClusterShutdown, Next: CephClusterStopDaemons, Expected: HealthGateNoDegradedObjects, Gated: true},
+		{Current: CephClusterStopOsds, Next: CephClusterStopMons},
+		{Current: CephClusterStartOsds, Next: CephClusterShutdown},
+		{Current: CephClusterIdle, Next: CephClusterStartMons},
+	}
+
+	for _, c := range cases {
+		gate, gated := GetHealthGate(c.Current, c.Next)
+		if gate != c.Expected || gated != c.Gated {
+			t.Errorf("%s to %s: expected %q %t, got %q %t", c.Current, c.Next, c.Expected, c.Gated, gate, gated)
+		}
+	}
+}
+
+func TestHealthGateEnabled(t *testing.T) {
+	cluster := &CephCluster{}
+	cluster.Spec.HealthGates.PgsActive.Enabled = true
+
+	if cluster.HealthGateEnabled(HealthGateMgrAvailable) {
+		t.Errorf("mgr available gate should be disabled by default")
+	}
+	if !cluster.HealthGateEnabled(HealthGatePgsActive) {
+		t.Errorf("pgs active gate should be enabled")
+	}
+
+	cluster.SetAnnotations(map[string]string{SkipHealthGatesAnnotation: "true"})
+	if cluster.HealthGateEnabled(HealthGatePgsActive) {
+		t.Errorf("skip annotation should disable the pgs active gate")
+	}
+}
+
+func TestHealthGateTimedOut(t *testing.T) {
+	now := time.Now()
+	block := &CephClusterTransitionBlock{Gate: HealthGatePgsActive, Since: metav1.NewTime(now.Add(-15 * time.Minute))}
+
+	cases := []struct {
+		Name     string
+		Timeout  *metav1.Duration
+		Expected bool
+	}{
+		{Name: "default", Expected: true},
+		{Name: "longer timeout", Timeout: &metav1.Duration{Duration: time.Hour}},
+		{Name: "never", Timeout: &metav1.Duration{}},
+	}
+
+	for _, c := range cases {
+		cluster := &CephCluster{}
+		cluster.Spec.HealthGates.PgsActive.Timeout = c.Timeout
+		if timedOut := cluster.HealthGateTimedOut(block, now); timedOut != c.Expected {
+			t.Errorf("%s: expected %t, got %t", c.Name, c.Expected, timedOut)
+		}
+	}
+}
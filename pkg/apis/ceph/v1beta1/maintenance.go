@@ -0,0 +1,41 @@
+package v1beta1
+
+import (
+	"strings"
+)
+
+// MaintenanceAnnotation puts nodes into maintenance.  On a Node any value other than false puts the node into
+// maintenance, on a CephCluster it's a comma separated list of node names.
+const MaintenanceAnnotation = "ceph.k8s.pgc.umn.edu/maintenance"
+
+// GetAnnotatedMaintenanceNodes returns the nodes listed in the cluster's maintenance annotation
+func (c *CephCluster) GetAnnotatedMaintenanceNodes() []string {
+	nodes := []string{}
+	for _, node := range strings.Split(c.GetAnnotations()[MaintenanceAnnotation], ",") {
+		node = strings.TrimSpace(node)
+		if node != "" {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// NodeInMaintenance returns true if the node's maintenance annotation is set
+func NodeInMaintenance(annotations map[string]string) bool {
+	value, ok := annotations[MaintenanceAnnotation]
+	return ok && value != "false"
+}
+
+// InMaintenance returns true if the operator has put the node into maintenance for the cluster
+func (c *CephCluster) InMaintenance(nodeName string) bool {
+	if nodeName == "" {
+		return false
+	}
+
+	for _, node := range c.Status.MaintenanceNodes {
+		if node == nodeName {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestGetAnnotatedMaintenanceNodes(t *testing.T) {
+	cluster := &CephCluster{}
+	if diff := deep.Equal(cluster.GetAnnotatedMaintenanceNodes(), []string{}); diff != nil {
+		t.Error(diff)
+	}
+
+	cluster.SetAnnotations(map[string]string{MaintenanceAnnotation: "node-a, node-b,"})
+	if diff := deep.Equal(cluster.GetAnnotatedMaintenanceNodes(), []string{"node-a", "node-b"}); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestNodeInMaintenance(t *testing.T) {
+	cases := []struct {
+		Annotations map[string]string
+		Expected    bool
+	}{
+		{Annotations: nil, Expected: false},
+		{Annotations: map[string]string{MaintenanceAnnotation: "false"}, Expected: false},
+		{Annotations: map[string]string{MaintenanceAnnotation: "true"}, Expected: true},
+		{Annotations: map[string]string{MaintenanceAnnotation: ""}, Expected: true},
+	}
+
+	for _, c := range cases {
+		if NodeInMaintenance(c.Annotations) != c.Expected {
+			t.Errorf("%v: expected in maintenance to be %t", c.Annotations, c.Expected)
+		}
+	}
+}
+
+func TestInMaintenance(t *testing.T) {
+	cluster := &CephCluster{}
+	cluster.Status.MaintenanceNodes = []string{"node-a"}
+
+	if !cluster.InMaintenance("node-a") {
+		t.Errorf("expected node-a to be in maintenance")
+	}
+
+	if cluster.InMaintenance("node-b") || cluster.InMaintenance("") {
+		t.Errorf("expected only node-a to be in maintenance")
+	}
+}
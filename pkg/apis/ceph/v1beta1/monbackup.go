@@ -0,0 +1,161 @@
+package v1beta1
+
+import (
+	"fmt"
+	"path"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	MonBackupLabel      = "ceph.k8s.pgc.umn.edu/monBackup"
+	MonBackupPruneLabel = "ceph.k8s.pgc.umn.edu/monBackupPrune"
+
+	DefaultMonBackupInterval = 24 * time.Hour
+	DefaultMonBackupRetain   = 7
+)
+
+// MonBackupSpec schedules periodic backups of a monitor store
+type MonBackupSpec struct {
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// Retain is the number of backups kept at the destination
+	Retain      int                  `json:"retain,omitempty"`
+	Destination MonBackupDestination `json:"destination"`
+}
+
+// MonBackupDestination is where monitor store backups are written, exactly one destination should be set
+type MonBackupDestination struct {
+	PersistentVolumeClaim *corev1.PersistentVolumeClaimVolumeSource `json:"persistentVolumeClaim,omitempty"`
+	S3                    *S3BackupDestination                      `json:"s3,omitempty"`
+}
+
+// S3BackupDestination is an S3 compatible bucket.  Path style addressing is used so MinIO endpoints work.
+type S3BackupDestination struct {
+	Endpoint string `json:"endpoint"`
+	Bucket   string `json:"bucket"`
+	Prefix   string `json:"prefix,omitempty"`
+	// Region is used to sign requests, us-east-1 if unset
+	Region string `json:"region,omitempty"`
+	// CredentialsSecretName is a secret holding the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY keys
+	CredentialsSecretName string `json:"credentialsSecretName"`
+}
+
+// MonBackupRecord is a completed monitor store backup
+type MonBackupRecord struct {
+	Name           string      `json:"name"`
+	MonID          string      `json:"monId"`
+	CompletionTime metav1.Time `json:"completionTime"`
+	Location       string      `json:"location"`
+}
+
+// MonActiveBackup is a backup in progress.  The source monitor is stopped until its store has been copied.
+type MonActiveBackup struct {
+	Name     string `json:"name"`
+	MonID    string `json:"monId"`
+	NodeName string `json:"nodeName"`
+}
+
+// GetInterval returns the time between backups, defaulting to a day
+func (s *MonBackupSpec) GetInterval() time.Duration {
+	if s.Interval == nil {
+		return DefaultMonBackupInterval
+	}
+	return s.Interval.Duration
+}
+
+// GetRetain returns the number of backups to keep
+func (s *MonBackupSpec) GetRetain() int {
+	if s.Retain <= 0 {
+		return DefaultMonBackupRetain
+	}
+	return s.Retain
+}
+
+// GetLocation returns a human readable location of the named backup
+func (d MonBackupDestination) GetLocation(name string) string {
+	switch {
+	case d.S3 != nil:
+		return fmt.Sprintf("%s/%s/%s", d.S3.Endpoint, d.S3.Bucket, d.S3.GetS3Key(name))
+	case d.PersistentVolumeClaim != nil:
+		return fmt.Sprintf("pvc/%s/%s", d.PersistentVolumeClaim.ClaimName, name)
+	}
+	return ""
+}
+
+// GetS3Key returns the key of the named backup in the bucket
+func (d *S3BackupDestination) GetS3Key(name string) string {
+	return d.Prefix + name
+}
+
+// GetBackupPruneJob returns a job that deletes the named backups from a persistent volume claim destination.
+// S3 destinations are pruned by the operator directly.
+func (c *CephMonCluster) GetBackupPruneJob(names []string) *batchv1.Job {
+	job := &batchv1.Job{}
+
+	job.APIVersion = "batch/v1"
+	job.Kind = "Job"
+
+	job.Name = fmt.Sprintf("ceph-%s-prune", names[0])
+	job.SetLabels(map[string]string{
+		ClusterNameLabel:    c.GetCephClusterName(),
+		MonBackupPruneLabel: names[0],
+	})
+
+	backoffLimit := int32(0)
+	job.Spec.BackoffLimit = &backoffLimit
+
+	container := corev1.Container{}
+	container.Name = "ceph-mon-backup-prune"
+	container.Image = c.GetImage().String()
+	container.Command = []string{"rm", "-rf"}
+	for _, name := range names {
+		container.Command = append(container.Command, path.Join("/backup", name))
+	}
+
+	spec := &job.Spec.Template.Spec
+	spec.RestartPolicy = corev1.RestartPolicyNever
+	spec.ServiceAccountName = GetServiceAccountName(c.GetCephClusterName(), CephDaemonTypeMon)
+	c.Spec.Backup.Destination.ApplyToPodSpec(spec, &container)
+	spec.Containers = []corev1.Container{container}
+
+	return job
+}
+
+// ApplyToPodSpec adds the volumes and environment container needs to reach the destination
+func (d MonBackupDestination) ApplyToPodSpec(spec *corev1.PodSpec, container *corev1.Container) {
+	if d.PersistentVolumeClaim != nil {
+		spec.Volumes = append(spec.Volumes, corev1.Volume{
+			Name: "ceph-mon-backup",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: d.PersistentVolumeClaim,
+			},
+		})
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      "ceph-mon-backup",
+			MountPath: "/backup",
+		})
+		container.Env = append(container.Env, corev1.EnvVar{Name: "BACKUP_PATH", Value: "/backup"})
+	}
+
+	if d.S3 != nil {
+		container.Env = append(container.Env,
+			corev1.EnvVar{Name: "S3_ENDPOINT", Value: d.S3.Endpoint},
+			corev1.EnvVar{Name: "S3_BUCKET", Value: d.S3.Bucket},
+			corev1.EnvVar{Name: "S3_PREFIX", Value: d.S3.Prefix},
+		)
+		for _, key := range []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY"} {
+			container.Env = append(container.Env, corev1.EnvVar{
+				Name: key,
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: d.S3.CredentialsSecretName},
+						Key:                  key,
+					},
+				},
+			})
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package v1beta1
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+)
+
+// maxNamePartLength limits the part of a generated name taken from user input, leaving room for the prefix and
+// hash within a 63 character DNS-1123 label
+const maxNamePartLength = 30
+
+// shortHash returns a short digest of s, it tells apart names that would otherwise be built the same way
+func shortHash(s string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(s)))[:8]
+}
+
+// sanitizeNamePart lowercases s and replaces anything not allowed in a DNS-1123 label with dashes.  Different
+// inputs can sanitize to the same part, names built from it need a hash of the input.
+func sanitizeNamePart(s string) string {
+	part := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '-'
+	}, strings.ToLower(s))
+
+	if len(part) > maxNamePartLength {
+		part = part[:maxNamePartLength]
+	}
+	return strings.Trim(part, "-")
+}
@@ -0,0 +1,173 @@
+package v1beta1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type NetworkProvider string
+
+const (
+	NetworkProviderPod    NetworkProvider = ""
+	NetworkProviderHost   NetworkProvider = "host"
+	NetworkProviderMultus NetworkProvider = "multus"
+)
+
+const (
+	MultusNetworksAnnotation = "k8s.v1.cni.cncf.io/networks"
+	// Multus has published attachment status under both names
+	MultusNetworkStatusAnnotation       = "k8s.v1.cni.cncf.io/network-status"
+	MultusLegacyNetworkStatusAnnotation = "k8s.v1.cni.cncf.io/networks-status"
+)
+
+// NetworkSpec describes the networks ceph daemons communicate on
+type NetworkSpec struct {
+	Provider NetworkProvider `json:"provider,omitempty"`
+	// PublicNetwork and ClusterNetwork are CIDRs written to ceph.conf
+	PublicNetwork  string `json:"publicNetwork,omitempty"`
+	ClusterNetwork string `json:"clusterNetwork,omitempty"`
+	// PublicNetworkAttachment and ClusterNetworkAttachment name multus NetworkAttachmentDefinitions
+	// as name or namespace/name
+	PublicNetworkAttachment  string `json:"publicNetworkAttachment,omitempty"`
+	ClusterNetworkAttachment string `json:"clusterNetworkAttachment,omitempty"`
+}
+
+type multusNetworkStatus struct {
+	Name string   `json:"name"`
+	IPs  []string `json:"ips"`
+}
+
+// ApplyToPodTemplate configures networking for a pod.  The cluster network is only attached when
+// cluster is true, for daemons that replicate data.
+func (n NetworkSpec) ApplyToPodTemplate(meta *metav1.ObjectMeta, spec *corev1.PodSpec, cluster bool) {
+	switch n.Provider {
+	case NetworkProviderHost:
+		spec.HostNetwork = true
+		spec.DNSPolicy = corev1.DNSClusterFirstWithHostNet
+
+	case NetworkProviderMultus:
+		networks := []string{}
+		if n.PublicNetworkAttachment != "" {
+			networks = append(networks, n.PublicNetworkAttachment)
+		}
+		if cluster && n.ClusterNetworkAttachment != "" {
+			networks = append(networks, n.ClusterNetworkAttachment)
+		}
+
+		if len(networks) > 0 {
+			annotations := meta.GetAnnotations()
+			if annotations == nil {
+				annotations = make(map[string]string)
+			}
+			annotations[MultusNetworksAnnotation] = strings.Join(networks, ",")
+			meta.SetAnnotations(annotations)
+		}
+	}
+
+	env := []corev1.EnvVar{}
+	if n.PublicNetwork != "" {
+		env = append(env, corev1.EnvVar{Name: "PUBLIC_NETWORK", Value: n.PublicNetwork})
+	}
+	if cluster && n.ClusterNetwork != "" {
+		env = append(env, corev1.EnvVar{Name: "CLUSTER_NETWORK", Value: n.ClusterNetwork})
+	}
+
+	for i := range spec.Containers {
+		spec.Containers[i].Env = append(spec.Containers[i].Env, env...)
+	}
+}
+
+// UsesMonServices returns true if each monitor is reached through its own service address
+func (n NetworkSpec) UsesMonServices() bool {
+	return n.Provider == NetworkProviderPod
+}
+
+// GetMonIPEnv returns the MON_IP variable for a monitor container.  With multus the address isn't
+// known until the pod's interfaces are attached, so the monitor picks the address of its interface
+// on the public network instead.
+func (n NetworkSpec) GetMonIPEnv() []corev1.EnvVar {
+	fieldPath := "status.podIP"
+	switch n.Provider {
+	case NetworkProviderHost:
+		fieldPath = "status.hostIP"
+	case NetworkProviderMultus:
+		return nil
+	}
+
+	return []corev1.EnvVar{
+		corev1.EnvVar{
+			Name: "MON_IP",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: fieldPath,
+				},
+			},
+		},
+	}
+}
+
+// GetPodIP returns the address of a pod on the public network
+func (n NetworkSpec) GetPodIP(pod *corev1.Pod) (net.IP, error) {
+	switch n.Provider {
+	case NetworkProviderHost:
+		return net.ParseIP(pod.Status.HostIP), nil
+
+	case NetworkProviderMultus:
+		if n.PublicNetworkAttachment == "" {
+			return net.ParseIP(pod.Status.PodIP), nil
+		}
+		return n.getMultusIP(pod)
+	}
+
+	return net.ParseIP(pod.Status.PodIP), nil
+}
+
+func (n NetworkSpec) getMultusIP(pod *corev1.Pod) (net.IP, error) {
+	annotations := pod.GetAnnotations()
+	status, ok := annotations[MultusNetworkStatusAnnotation]
+	if !ok {
+		status, ok = annotations[MultusLegacyNetworkStatusAnnotation]
+	}
+	if !ok {
+		return nil, fmt.Errorf("pod %s has no multus network status", pod.GetName())
+	}
+
+	networks := []multusNetworkStatus{}
+	err := json.Unmarshal([]byte(status), &networks)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment := n.PublicNetworkAttachment
+	if !strings.Contains(attachment, "/") {
+		attachment = fmt.Sprintf("%s/%s", pod.GetNamespace(), attachment)
+	}
+
+	var publicNet *net.IPNet
+	if n.PublicNetwork != "" {
+		_, publicNet, err = net.ParseCIDR(n.PublicNetwork)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, network := range networks {
+		if network.Name != attachment && network.Name != n.PublicNetworkAttachment {
+			continue
+		}
+
+		for _, s := range network.IPs {
+			ip := net.ParseIP(s)
+			if ip != nil && (publicNet == nil || publicNet.Contains(ip)) {
+				return ip, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("pod %s has no address on network %s", pod.GetName(), n.PublicNetworkAttachment)
+}
@@ -0,0 +1,74 @@
+package v1beta1
+
+import (
+	"net"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetPodIP(t *testing.T) {
+	multusPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "mon",
+			Namespace: "ceph",
+			Annotations: map[string]string{
+				MultusNetworkStatusAnnotation: `[{"name":"k8s-pod-network","ips":["10.244.0.5"]},` +
+					`{"name":"ceph/public","ips":["192.168.10.5"]},` +
+					`{"name":"ceph/cluster","ips":["192.168.20.5"]}]`,
+			},
+		},
+		Status: corev1.PodStatus{PodIP: "10.244.0.5", HostIP: "172.16.0.5"},
+	}
+
+	testCases := []struct {
+		Name     string
+		Network  NetworkSpec
+		Pod      *corev1.Pod
+		Expected net.IP
+	}{
+		{
+			Name:     "pod",
+			Network:  NetworkSpec{},
+			Pod:      multusPod,
+			Expected: net.ParseIP("10.244.0.5"),
+		},
+		{
+			Name:     "host",
+			Network:  NetworkSpec{Provider: NetworkProviderHost},
+			Pod:      multusPod,
+			Expected: net.ParseIP("172.16.0.5"),
+		},
+		{
+			Name:     "multus",
+			Network:  NetworkSpec{Provider: NetworkProviderMultus, PublicNetworkAttachment: "public"},
+			Pod:      multusPod,
+			Expected: net.ParseIP("192.168.10.5"),
+		},
+		{
+			Name: "multus-namespaced",
+			Network: NetworkSpec{Provider: NetworkProviderMultus, PublicNetworkAttachment: "ceph/cluster",
+				PublicNetwork: "192.168.20.0/24"},
+			Pod:      multusPod,
+			Expected: net.ParseIP("192.168.20.5"),
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(st *testing.T) {
+			ip, err := c.Network.GetPodIP(c.Pod)
+			if err != nil {
+				st.Fatalf("Error getting pod ip: %v", err)
+			}
+			if !ip.Equal(c.Expected) {
+				st.Errorf("Got %s expected %s", ip, c.Expected)
+			}
+		})
+	}
+
+	_, err := NetworkSpec{Provider: NetworkProviderMultus, PublicNetworkAttachment: "missing"}.GetPodIP(multusPod)
+	if err == nil {
+		t.Errorf("Expected an error for a network the pod isn't attached to")
+	}
+}
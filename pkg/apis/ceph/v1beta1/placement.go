@@ -0,0 +1,76 @@
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const DefaultTopologyKey = "kubernetes.io/hostname"
+
+type AntiAffinityType string
+
+const (
+	AntiAffinityRequired  AntiAffinityType = "Required"
+	AntiAffinityPreferred AntiAffinityType = "Preferred"
+)
+
+// PlacementSpec describes how the pods of a daemon type are spread across the kubernetes cluster
+type PlacementSpec struct {
+	AntiAffinity AntiAffinityType    `json:"antiAffinity,omitempty"`
+	TopologyKey  string              `json:"topologyKey,omitempty"`
+	NodeSelector map[string]string   `json:"nodeSelector,omitempty"`
+	Tolerations  []corev1.Toleration `json:"tolerations,omitempty"`
+	// AllowUnsafe allows quorum to be declared while a single failure domain holds enough daemons to break it,
+	// it only applies to required anti-affinity
+	AllowUnsafe bool `json:"allowUnsafe,omitempty"`
+}
+
+// GetAntiAffinity returns the anti-affinity type, defaulting to preferred so clusters with fewer nodes than
+// daemons can still schedule them
+func (p PlacementSpec) GetAntiAffinity() AntiAffinityType {
+	if p.AntiAffinity == "" {
+		return AntiAffinityPreferred
+	}
+	return p.AntiAffinity
+}
+
+// GetTopologyKey returns the node label that defines a failure domain, defaulting to the hostname
+func (p PlacementSpec) GetTopologyKey() string {
+	if p.TopologyKey == "" {
+		return DefaultTopologyKey
+	}
+	return p.TopologyKey
+}
+
+// ApplyToPod sets the affinity, node selector and tolerations on the pod.  Pods matching the
+// selector labels are kept out of the pod's failure domain.
+func (p PlacementSpec) ApplyToPod(pod *corev1.Pod, selector map[string]string) {
+	term := corev1.PodAffinityTerm{
+		LabelSelector: &metav1.LabelSelector{MatchLabels: selector},
+		TopologyKey:   p.GetTopologyKey(),
+	}
+
+	antiAffinity := &corev1.PodAntiAffinity{}
+	switch p.GetAntiAffinity() {
+	case AntiAffinityPreferred:
+		antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = []corev1.WeightedPodAffinityTerm{
+			corev1.WeightedPodAffinityTerm{
+				Weight:          100,
+				PodAffinityTerm: term,
+			},
+		}
+	default:
+		antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = []corev1.PodAffinityTerm{term}
+	}
+
+	pod.Spec.Affinity = &corev1.Affinity{PodAntiAffinity: antiAffinity}
+
+	if len(p.NodeSelector) > 0 {
+		pod.Spec.NodeSelector = make(map[string]string, len(p.NodeSelector))
+		for k, v := range p.NodeSelector {
+			pod.Spec.NodeSelector[k] = v
+		}
+	}
+
+	pod.Spec.Tolerations = append(pod.Spec.Tolerations, p.Tolerations...)
+}
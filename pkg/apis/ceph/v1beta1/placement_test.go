@@ -0,0 +1,64 @@
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestApplyToPod(t *testing.T) {
+	selector := map[string]string{ClusterNameLabel: "test", DaemonTypeLabel: "mon"}
+	toleration := corev1.Toleration{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "ceph"}
+
+	cases := []struct {
+		Name      string
+		Placement PlacementSpec
+		Required  bool
+	}{
+		{Name: "default", Placement: PlacementSpec{}},
+		{Name: "preferred", Placement: PlacementSpec{AntiAffinity: AntiAffinityPreferred}},
+		{Name: "required", Placement: PlacementSpec{AntiAffinity: AntiAffinityRequired}, Required: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			placement := c.Placement
+			placement.TopologyKey = "topology.kubernetes.io/zone"
+			placement.NodeSelector = map[string]string{"ceph": "true"}
+			placement.Tolerations = []corev1.Toleration{toleration}
+
+			pod := &corev1.Pod{}
+			placement.ApplyToPod(pod, selector)
+
+			antiAffinity := pod.Spec.Affinity.PodAntiAffinity
+			var term corev1.PodAffinityTerm
+			if c.Required {
+				if len(antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution) != 1 ||
+					len(antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution) != 0 {
+					t.Fatalf("expected a single required term, got %v", antiAffinity)
+				}
+				term = antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution[0]
+			} else {
+				if len(antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution) != 1 ||
+					len(antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution) != 0 {
+					t.Fatalf("expected a single preferred term, got %v", antiAffinity)
+				}
+				term = antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution[0].PodAffinityTerm
+			}
+
+			if term.TopologyKey != "topology.kubernetes.io/zone" {
+				t.Errorf("unexpected topology key %s", term.TopologyKey)
+			}
+			if diff := deep.Equal(term.LabelSelector.MatchLabels, selector); diff != nil {
+				t.Errorf("unexpected anti-affinity selector: %v", diff)
+			}
+			if diff := deep.Equal(pod.Spec.NodeSelector, map[string]string{"ceph": "true"}); diff != nil {
+				t.Errorf("unexpected node selector: %v", diff)
+			}
+			if diff := deep.Equal(pod.Spec.Tolerations, []corev1.Toleration{toleration}); diff != nil {
+				t.Errorf("unexpected tolerations: %v", diff)
+			}
+		})
+	}
+}
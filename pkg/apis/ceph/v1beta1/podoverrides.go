@@ -0,0 +1,119 @@
+package v1beta1
+
+import (
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+)
+
+// PodOverrides are strategic merged over the pods the operator generates.  Container fields apply to every
+// container in the pod, including init containers.  Overrides are applied as pods are created, running pods
+// keep the overrides they were created with.
+type PodOverrides struct {
+	// Resources are the requests and limits of the daemon containers
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// PriorityClassName is the priority class of the pods
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// SecurityContext is the security context of the daemon containers, osds may need capabilities or
+	// privileges for device access
+	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+	// PodSecurityContext is the security context of the pods
+	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+	// ImagePullSecrets are added to the pods
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	// ImagePullPolicy of the daemon containers, images are always pulled if unset
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+}
+
+// PodOverridesSpec holds the overrides applied to every daemon and to each daemon type
+type PodOverridesSpec struct {
+	// All is applied to every pod, the overrides of the daemon type are applied over it
+	All *PodOverrides `json:"all,omitempty"`
+	Mon *PodOverrides `json:"mon,omitempty"`
+	Mgr *PodOverrides `json:"mgr,omitempty"`
+	Mds *PodOverrides `json:"mds,omitempty"`
+	Osd *PodOverrides `json:"osd,omitempty"`
+}
+
+// Get returns the overrides of the daemon type, or nil if there are none
+func (s PodOverridesSpec) Get(daemonType CephDaemonType) *PodOverrides {
+	switch daemonType {
+	case CephDaemonTypeMon:
+		return s.Mon
+	case CephDaemonTypeMgr:
+		return s.Mgr
+	case CephDaemonTypeMds:
+		return s.Mds
+	case CephDaemonTypeOsd:
+		return s.Osd
+	}
+	return nil
+}
+
+// ApplyPodOverrides merges the cluster's overrides for the daemon type over the pod spec
+func (c *CephCluster) ApplyPodOverrides(daemonType CephDaemonType, spec *corev1.PodSpec) error {
+	for _, overrides := range []*PodOverrides{c.Spec.PodOverrides.All, c.Spec.PodOverrides.Get(daemonType)} {
+		if overrides == nil {
+			continue
+		}
+
+		err := overrides.ApplyToPodSpec(spec)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApplyToPodSpec strategic merges the overrides over the pod spec
+func (o *PodOverrides) ApplyToPodSpec(spec *corev1.PodSpec) error {
+	patch := corev1.PodSpec{
+		PriorityClassName: o.PriorityClassName,
+		SecurityContext:   o.PodSecurityContext,
+		ImagePullSecrets:  o.ImagePullSecrets,
+		InitContainers:    o.containerPatches(spec.InitContainers),
+		Containers:        o.containerPatches(spec.Containers),
+	}
+
+	original, err := json.Marshal(spec)
+	if err != nil {
+		return err
+	}
+
+	patchJSON, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+
+	merged, err := strategicpatch.StrategicMergePatch(original, patchJSON, corev1.PodSpec{})
+	if err != nil {
+		return err
+	}
+
+	result := corev1.PodSpec{}
+	err = json.Unmarshal(merged, &result)
+	if err != nil {
+		return err
+	}
+
+	*spec = result
+	return nil
+}
+
+// containerPatches returns the container overrides for each of the containers, merged by name
+func (o *PodOverrides) containerPatches(containers []corev1.Container) []corev1.Container {
+	patches := make([]corev1.Container, 0, len(containers))
+	for _, container := range containers {
+		override := corev1.Container{
+			Name:            container.Name,
+			SecurityContext: o.SecurityContext,
+			ImagePullPolicy: o.ImagePullPolicy,
+		}
+		if o.Resources != nil {
+			override.Resources = *o.Resources
+		}
+		patches = append(patches, override)
+	}
+	return patches
+}
@@ -0,0 +1,99 @@
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestApplyPodOverrides(t *testing.T) {
+	cluster := &CephCluster{}
+	cluster.Spec.PodOverrides = PodOverridesSpec{
+		All: &PodOverrides{
+			PriorityClassName: "system-cluster-critical",
+			ImagePullSecrets:  []corev1.LocalObjectReference{{Name: "registry"}},
+			ImagePullPolicy:   corev1.PullIfNotPresent,
+		},
+		Osd: &PodOverrides{
+			Resources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+			},
+			SecurityContext: &corev1.SecurityContext{
+				Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"SYS_ADMIN"}},
+			},
+		},
+	}
+
+	osd := &CephOsd{}
+	osd.Name = "test-osd-1"
+	osd.Spec.ClusterName = "test"
+	spec := osd.GetPod("ceph/osd:latest", "ceph-conf", "ceph-test-osd", NetworkSpec{}).Spec
+
+	err := cluster.ApplyPodOverrides(CephDaemonTypeOsd, &spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if spec.PriorityClassName != "system-cluster-critical" {
+		t.Errorf("expected priority class from all daemon overrides, got %q", spec.PriorityClassName)
+	}
+
+	if diff := deep.Equal(spec.ImagePullSecrets, []corev1.LocalObjectReference{{Name: "registry"}}); diff != nil {
+		t.Error(diff)
+	}
+
+	container := spec.Containers[0]
+	if container.ImagePullPolicy != corev1.PullIfNotPresent {
+		t.Errorf("expected pull policy %s, got %s", corev1.PullIfNotPresent, container.ImagePullPolicy)
+	}
+
+	if limit := container.Resources.Limits[corev1.ResourceMemory]; limit.String() != "4Gi" {
+		t.Errorf("expected memory limit of 4Gi, got %s", limit.String())
+	}
+
+	if container.SecurityContext == nil || len(container.SecurityContext.Capabilities.Add) != 1 {
+		t.Errorf("expected osd security context to be applied, got %v", container.SecurityContext)
+	}
+
+	if container.Image != "ceph/osd:latest" || len(container.VolumeDevices) != 1 || len(spec.Volumes) != 2 {
+		t.Errorf("expected generated fields to be preserved, got %v", spec)
+	}
+
+	mon := &corev1.PodSpec{
+		InitContainers: []corev1.Container{{Name: "init"}},
+		Containers:     []corev1.Container{{Name: "ceph-mon", ImagePullPolicy: corev1.PullAlways}},
+	}
+	err = cluster.ApplyPodOverrides(CephDaemonTypeMon, mon)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mon.Containers[0].SecurityContext != nil {
+		t.Errorf("expected osd overrides not to apply to monitors")
+	}
+	if mon.InitContainers[0].ImagePullPolicy != corev1.PullIfNotPresent {
+		t.Errorf("expected overrides to apply to init containers, got %s", mon.InitContainers[0].ImagePullPolicy)
+	}
+}
+
+func TestPodOverridesKeepPullAlways(t *testing.T) {
+	cluster := &CephCluster{}
+	cluster.Spec.PodOverrides = PodOverridesSpec{
+		All: &PodOverrides{PriorityClassName: "system-cluster-critical"},
+	}
+
+	osd := &CephOsd{}
+	osd.Name = "test-osd-1"
+	osd.Spec.ClusterName = "test"
+	spec := osd.GetPod("ceph/daemon:latest-mimic", "ceph-conf", "ceph-test-osd", NetworkSpec{}).Spec
+
+	err := cluster.ApplyPodOverrides(CephDaemonTypeOsd, &spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if policy := spec.Containers[0].ImagePullPolicy; policy != corev1.PullAlways {
+		t.Errorf("expected floating tags to be pulled unless overridden, got %s", policy)
+	}
+}
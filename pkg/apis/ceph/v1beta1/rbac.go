@@ -0,0 +1,114 @@
+package v1beta1
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+// OsdClusterRoleName is the ClusterRole granting osds the cluster scoped access they need, it's installed with
+// the operator
+const OsdClusterRoleName = "ceph-operator-osd"
+
+// ServiceAccountDaemonTypes are the daemon types that run as their own ServiceAccount
+var ServiceAccountDaemonTypes = []CephDaemonType{
+	CephDaemonTypeMon,
+	CephDaemonTypeMgr,
+	CephDaemonTypeMds,
+	CephDaemonTypeRgw,
+	CephDaemonTypeOsd,
+}
+
+// daemonRoleRules are the namespaced permissions of each daemon type's Role
+var daemonRoleRules = map[CephDaemonType][]rbacv1.PolicyRule{
+	// The orchestrator and prometheus modules inspect the cluster's pods and services
+	CephDaemonTypeMgr: []rbacv1.PolicyRule{
+		rbacv1.PolicyRule{
+			APIGroups: []string{""},
+			Resources: []string{"pods", "services", "persistentvolumeclaims"},
+			Verbs:     []string{"get", "list", "watch"},
+		},
+	},
+}
+
+// GetServiceAccountName returns the name of the ServiceAccount the cluster's daemons of the type run as
+func GetServiceAccountName(clusterName string, daemonType CephDaemonType) string {
+	return fmt.Sprintf("ceph-%s-%s", clusterName, daemonType)
+}
+
+// GetServiceAccountName returns the name of the ServiceAccount the cluster's daemons of the type run as
+func (c *CephCluster) GetServiceAccountName(daemonType CephDaemonType) string {
+	return GetServiceAccountName(c.GetName(), daemonType)
+}
+
+// GetServiceAccount returns the ServiceAccount the cluster's daemons of the type run as
+func (c *CephCluster) GetServiceAccount(daemonType CephDaemonType) *corev1.ServiceAccount {
+	sa := &corev1.ServiceAccount{}
+	sa.Name = c.GetServiceAccountName(daemonType)
+	sa.Namespace = c.GetNamespace()
+	sa.SetLabels(c.getRBACLabels(daemonType))
+	return sa
+}
+
+// GetRole returns the Role granting the daemons of the type access to the cluster's namespace.  Daemon types
+// that don't use the Kubernetes api get a Role without rules.
+func (c *CephCluster) GetRole(daemonType CephDaemonType) *rbacv1.Role {
+	role := &rbacv1.Role{}
+	role.Name = c.GetServiceAccountName(daemonType)
+	role.Namespace = c.GetNamespace()
+	role.SetLabels(c.getRBACLabels(daemonType))
+	role.Rules = daemonRoleRules[daemonType]
+	return role
+}
+
+// GetRoleBinding binds the daemon type's Role to its ServiceAccount
+func (c *CephCluster) GetRoleBinding(daemonType CephDaemonType) *rbacv1.RoleBinding {
+	binding := &rbacv1.RoleBinding{}
+	binding.Name = c.GetServiceAccountName(daemonType)
+	binding.Namespace = c.GetNamespace()
+	binding.SetLabels(c.getRBACLabels(daemonType))
+	binding.Subjects = []rbacv1.Subject{c.getServiceAccountSubject(daemonType)}
+	binding.RoleRef = rbacv1.RoleRef{
+		APIGroup: rbacv1.GroupName,
+		Kind:     "Role",
+		Name:     c.GetServiceAccountName(daemonType),
+	}
+	return binding
+}
+
+// GetOsdClusterRoleBindingName returns the name of the binding for the osd ServiceAccount.  ClusterRoleBindings
+// aren't namespaced, so the name includes the cluster's namespace and a hash that keeps it unique when the
+// namespace or cluster name contain dashes.
+func (c *CephCluster) GetOsdClusterRoleBindingName() string {
+	return fmt.Sprintf("ceph-%s-%s-osd-%s", c.GetNamespace(), c.GetName(), shortHash(c.GetNamespace()+"/"+c.GetName()))
+}
+
+// GetOsdClusterRoleBinding binds the osd ClusterRole, granting node reads, to the cluster's osd ServiceAccount
+func (c *CephCluster) GetOsdClusterRoleBinding() *rbacv1.ClusterRoleBinding {
+	binding := &rbacv1.ClusterRoleBinding{}
+	binding.Name = c.GetOsdClusterRoleBindingName()
+	binding.SetLabels(c.getRBACLabels(CephDaemonTypeOsd))
+	binding.Subjects = []rbacv1.Subject{c.getServiceAccountSubject(CephDaemonTypeOsd)}
+	binding.RoleRef = rbacv1.RoleRef{
+		APIGroup: rbacv1.GroupName,
+		Kind:     "ClusterRole",
+		Name:     OsdClusterRoleName,
+	}
+	return binding
+}
+
+func (c *CephCluster) getServiceAccountSubject(daemonType CephDaemonType) rbacv1.Subject {
+	return rbacv1.Subject{
+		Kind:      rbacv1.ServiceAccountKind,
+		Name:      c.GetServiceAccountName(daemonType),
+		Namespace: c.GetNamespace(),
+	}
+}
+
+func (c *CephCluster) getRBACLabels(daemonType CephDaemonType) map[string]string {
+	return map[string]string{
+		ClusterNameLabel: c.GetName(),
+		DaemonTypeLabel:  daemonType.String(),
+	}
+}
@@ -0,0 +1,60 @@
+package v1beta1
+
+import (
+	"strings"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+func TestDaemonRBAC(t *testing.T) {
+	cluster := &CephCluster{}
+	cluster.Name = "test"
+	cluster.Namespace = "ceph"
+
+	for _, daemonType := range ServiceAccountDaemonTypes {
+		binding := cluster.GetRoleBinding(daemonType)
+		if binding.RoleRef.Name != cluster.GetRole(daemonType).Name {
+			t.Errorf("%s: binding references role %s", daemonType, binding.RoleRef.Name)
+		}
+
+		subject := binding.Subjects[0]
+		sa := cluster.GetServiceAccount(daemonType)
+		if subject.Kind != rbacv1.ServiceAccountKind || subject.Name != sa.Name || subject.Namespace != sa.Namespace {
+			t.Errorf("%s: binding subject %v doesn't match service account %s/%s", daemonType, subject, sa.Namespace, sa.Name)
+		}
+	}
+
+	// Every daemon's pod runs as its type's ServiceAccount, so each type must have one created
+	for _, daemonType := range []CephDaemonType{CephDaemonTypeMon, CephDaemonTypeMgr, CephDaemonTypeMds, CephDaemonTypeRgw, CephDaemonTypeOsd} {
+		found := false
+		for _, saType := range ServiceAccountDaemonTypes {
+			found = found || saType == daemonType
+		}
+		if !found {
+			t.Errorf("%s: no service account is created for the daemon type", daemonType)
+		}
+	}
+
+	if len(cluster.GetRole(CephDaemonTypeMgr).Rules) == 0 {
+		t.Errorf("expected the mgr role to allow listing pods")
+	}
+
+	if len(cluster.GetRole(CephDaemonTypeMon).Rules) != 0 {
+		t.Errorf("expected the mon role to have no rules")
+	}
+
+	clusterBinding := cluster.GetOsdClusterRoleBinding()
+	if !strings.HasPrefix(clusterBinding.Name, "ceph-ceph-test-osd-") || clusterBinding.Subjects[0].Name != "ceph-test-osd" {
+		t.Errorf("unexpected osd cluster role binding %v", clusterBinding)
+	}
+
+	// "a-b" in namespace "ceph" and "b" in namespace "ceph-a" would share a name without the hash
+	other := cluster.DeepCopy()
+	other.Namespace = "ceph-a"
+	other.Name = "b"
+	cluster.Name = "a-b"
+	if cluster.GetOsdClusterRoleBindingName() == other.GetOsdClusterRoleBindingName() {
+		t.Errorf("osd cluster role binding names collide: %s", cluster.GetOsdClusterRoleBindingName())
+	}
+}
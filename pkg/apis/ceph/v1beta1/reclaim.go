@@ -0,0 +1,49 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FsidLabel records the fsid of the cluster a retained PVC or keyring secret belongs to
+const FsidLabel = "ceph.k8s.pgc.umn.edu/fsid"
+
+// ReclaimPolicy describes what happens to PVCs and keyring secrets when the resource owning them is deleted
+type ReclaimPolicy string
+
+const (
+	// ReclaimPolicyRetain orphans PVCs and keyring secrets so they can be adopted by a re-created resource
+	ReclaimPolicyRetain ReclaimPolicy = "Retain"
+	// ReclaimPolicyDelete garbage collects PVCs and keyring secrets with their owner
+	ReclaimPolicyDelete ReclaimPolicy = "Delete"
+)
+
+// Or returns the policy, or def if the policy is unset
+func (p ReclaimPolicy) Or(def ReclaimPolicy) ReclaimPolicy {
+	if p == "" {
+		return def
+	}
+	return p
+}
+
+// GetReclaimPolicy returns the cluster's reclaim policy, monitors and osds use it unless they set their own
+func (c *CephCluster) GetReclaimPolicy() ReclaimPolicy {
+	return c.Spec.ReclaimPolicy.Or(ReclaimPolicyDelete)
+}
+
+// GetRetainLabels returns the labels a re-created resource uses to find its retained PVCs and keyring secrets
+func (c *CephCluster) GetRetainLabels() map[string]string {
+	return map[string]string{
+		ClusterNameLabel: c.GetName(),
+		FsidLabel:        c.Spec.Fsid,
+	}
+}
+
+// CheckRetainLabels returns false if the object was retained from a cluster with a different name or fsid
+func (c *CephCluster) CheckRetainLabels(o metav1.Object) bool {
+	for k, v := range c.GetRetainLabels() {
+		if existing, ok := o.GetLabels()[k]; ok && existing != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,247 @@
+package v1beta1
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const DefaultClusterDomain = "cluster.local"
+
+// Validator is implemented by resources checked by the admission webhook
+type Validator interface {
+	runtime.Object
+	metav1.Object
+	Validate() error
+	ValidateUpdate(old runtime.Object) error
+}
+
+var (
+	_ Validator = &CephCluster{}
+	_ Validator = &CephMonCluster{}
+	_ Validator = &CephMon{}
+	_ Validator = &CephOsd{}
+	_ Validator = &CephDaemonCluster{}
+	_ Validator = &CephDaemon{}
+)
+
+var specPath = field.NewPath("spec")
+
+func validateImage(path *field.Path, image ImageSpec) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if image.Registry == "" {
+		allErrs = append(allErrs, field.Required(path.Child("registry"), ""))
+	}
+	if image.Tag == "" {
+		allErrs = append(allErrs, field.Required(path.Child("tag"), ""))
+	}
+	return allErrs
+}
+
+func validatePvSelector(path *field.Path, selector *metav1.LabelSelector) field.ErrorList {
+	if _, err := metav1.LabelSelectorAsSelector(selector); err != nil {
+		return field.ErrorList{field.Invalid(path, selector, err.Error())}
+	}
+	return nil
+}
+
+func validateImmutable(path *field.Path, value, old interface{}) field.ErrorList {
+	if value != old {
+		return field.ErrorList{field.Forbidden(path, fmt.Sprintf("field is immutable, was %v", old))}
+	}
+	return nil
+}
+
+func wrongType(expected, old runtime.Object) error {
+	return fmt.Errorf("expected old object of type %T, got %T", expected, old)
+}
+
+// Default sets the fsid, monitor service name and cluster domain if they are unset.  The fsid of an imported
+// or external cluster is never generated.
+func (c *CephCluster) Default() {
+	if c.Spec.Fsid == "" && c.Spec.Import == nil && !c.Spec.External {
+		c.Spec.Fsid = uuid.New().String()
+	}
+	if c.Spec.MonServiceName == "" {
+		c.Spec.MonServiceName = fmt.Sprintf("%s-mon", c.GetName())
+	}
+	if c.Spec.ClusterDomain == "" {
+		c.Spec.ClusterDomain = DefaultClusterDomain
+	}
+}
+
+func (c *CephCluster) Validate() error {
+	allErrs := field.ErrorList{}
+
+	if c.Spec.Fsid == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("fsid"), ""))
+	} else if _, err := uuid.Parse(c.Spec.Fsid); err != nil {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("fsid"), c.Spec.Fsid, err.Error()))
+	}
+
+	if c.Spec.MonServiceName == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("monServiceName"), ""))
+	}
+
+	allErrs = append(allErrs, validateImage(specPath.Child("monImage"), c.Spec.MonImage)...)
+	allErrs = append(allErrs, validateImage(specPath.Child("osdImage"), c.Spec.OsdImage)...)
+	allErrs = append(allErrs, validateImage(specPath.Child("mgrImage"), c.Spec.MgrImage)...)
+	allErrs = append(allErrs, validateImage(specPath.Child("mdsImage"), c.Spec.MdsImage)...)
+
+	if c.Spec.Import != nil {
+		importPath := specPath.Child("import")
+		if c.Spec.Import.MonKeyringSecretName == "" {
+			allErrs = append(allErrs, field.Required(importPath.Child("monKeyringSecretName"), ""))
+		}
+		if c.Spec.Import.AdminKeyringSecretName == "" {
+			allErrs = append(allErrs, field.Required(importPath.Child("adminKeyringSecretName"), ""))
+		}
+	}
+
+	if c.Spec.External {
+		externalPath := specPath.Child("externalCluster")
+		switch {
+		case c.Spec.ExternalCluster == nil:
+			allErrs = append(allErrs, field.Required(externalPath, "external clusters must set externalCluster"))
+		default:
+			if len(c.Spec.ExternalCluster.MonHosts) == 0 {
+				allErrs = append(allErrs, field.Required(externalPath.Child("monHosts"), ""))
+			}
+			if c.Spec.ExternalCluster.ClientKeyringSecretName == "" {
+				allErrs = append(allErrs, field.Required(externalPath.Child("clientKeyringSecretName"), ""))
+			}
+		}
+
+		if c.Spec.Import != nil {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("import"), "external clusters can't be imported"))
+		}
+	}
+
+	return allErrs.ToAggregate()
+}
+
+func (c *CephCluster) ValidateUpdate(old runtime.Object) error {
+	oldCluster, ok := old.(*CephCluster)
+	if !ok {
+		return wrongType(c, old)
+	}
+
+	allErrs := validateImmutable(specPath.Child("fsid"), c.Spec.Fsid, oldCluster.Spec.Fsid)
+	// The operator neither stops the daemons of a cluster that becomes external nor starts them for one that stops
+	// being external
+	allErrs = append(allErrs, validateImmutable(specPath.Child("external"), c.Spec.External, oldCluster.Spec.External)...)
+	return allErrs.ToAggregate()
+}
+
+func (c *CephMonCluster) Validate() error {
+	allErrs := validateImage(specPath.Child("image"), c.Spec.Image)
+
+	if c.Spec.Count < 0 || (c.Spec.Count > 0 && c.Spec.Count%2 == 0) {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("count"), c.Spec.Count, "must be 0 or odd"))
+	}
+
+	allErrs = append(allErrs, validatePvSelector(specPath.Child("pvSelector"), c.Spec.PvSelector)...)
+
+	if c.Spec.Recovery != nil {
+		recoveryPath := specPath.Child("recovery")
+		if c.Spec.Recovery.Nonce == "" {
+			allErrs = append(allErrs, field.Required(recoveryPath.Child("nonce"), "recovery runs once for each nonce"))
+		}
+		if c.Spec.Recovery.SurvivorID == "" {
+			allErrs = append(allErrs, field.Required(recoveryPath.Child("survivorId"), ""))
+		}
+	}
+
+	return allErrs.ToAggregate()
+}
+
+func (c *CephMonCluster) ValidateUpdate(old runtime.Object) error {
+	oldCluster, ok := old.(*CephMonCluster)
+	if !ok {
+		return wrongType(c, old)
+	}
+
+	return validateImmutable(specPath.Child("clusterName"), c.Spec.ClusterName, oldCluster.Spec.ClusterName).ToAggregate()
+}
+
+func (m *CephMon) Validate() error {
+	allErrs := field.ErrorList{}
+	if m.Spec.ID == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("id"), ""))
+	}
+
+	allErrs = append(allErrs, validatePvSelector(specPath.Child("pvSelector"), m.Spec.PvSelector)...)
+
+	return allErrs.ToAggregate()
+}
+
+func (m *CephMon) ValidateUpdate(old runtime.Object) error {
+	oldMon, ok := old.(*CephMon)
+	if !ok {
+		return wrongType(m, old)
+	}
+
+	allErrs := validateImmutable(specPath.Child("clusterName"), m.Spec.ClusterName, oldMon.Spec.ClusterName)
+	allErrs = append(allErrs, validateImmutable(specPath.Child("id"), m.Spec.ID, oldMon.Spec.ID)...)
+	return allErrs.ToAggregate()
+}
+
+func (o *CephOsd) Validate() error {
+	allErrs := field.ErrorList{}
+	if o.Spec.ID < 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("id"), o.Spec.ID, "must not be negative"))
+	}
+
+	allErrs = append(allErrs, validatePvSelector(specPath.Child("pvSelector"), o.Spec.PvSelector)...)
+
+	return allErrs.ToAggregate()
+}
+
+func (o *CephOsd) ValidateUpdate(old runtime.Object) error {
+	oldOsd, ok := old.(*CephOsd)
+	if !ok {
+		return wrongType(o, old)
+	}
+
+	allErrs := validateImmutable(specPath.Child("clusterName"), o.Spec.ClusterName, oldOsd.Spec.ClusterName)
+	allErrs = append(allErrs, validateImmutable(specPath.Child("id"), o.Spec.ID, oldOsd.Spec.ID)...)
+	return allErrs.ToAggregate()
+}
+
+func (c *CephDaemonCluster) Validate() error {
+	return validateImage(specPath.Child("image"), c.Spec.Image).ToAggregate()
+}
+
+func (c *CephDaemonCluster) ValidateUpdate(old runtime.Object) error {
+	oldCluster, ok := old.(*CephDaemonCluster)
+	if !ok {
+		return wrongType(c, old)
+	}
+
+	allErrs := validateImmutable(specPath.Child("clusterName"), c.Spec.ClusterName, oldCluster.Spec.ClusterName)
+	allErrs = append(allErrs, validateImmutable(specPath.Child("daemonType"), c.Spec.DaemonType, oldCluster.Spec.DaemonType)...)
+	return allErrs.ToAggregate()
+}
+
+func (d *CephDaemon) Validate() error {
+	allErrs := validateImage(specPath.Child("image"), d.Spec.Image)
+	if d.Spec.ID == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("id"), ""))
+	}
+	return allErrs.ToAggregate()
+}
+
+func (d *CephDaemon) ValidateUpdate(old runtime.Object) error {
+	oldDaemon, ok := old.(*CephDaemon)
+	if !ok {
+		return wrongType(d, old)
+	}
+
+	allErrs := validateImmutable(specPath.Child("clusterName"), d.Spec.ClusterName, oldDaemon.Spec.ClusterName)
+	allErrs = append(allErrs, validateImmutable(specPath.Child("id"), d.Spec.ID, oldDaemon.Spec.ID)...)
+	allErrs = append(allErrs, validateImmutable(specPath.Child("daemonType"), d.Spec.DaemonType, oldDaemon.Spec.DaemonType)...)
+	return allErrs.ToAggregate()
+}
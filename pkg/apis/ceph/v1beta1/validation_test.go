@@ -0,0 +1,128 @@
+package v1beta1
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCephClusterDefault(t *testing.T) {
+	cluster := &CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+	cluster.Default()
+
+	if cluster.Spec.MonServiceName != "test-mon" || cluster.Spec.ClusterDomain != DefaultClusterDomain {
+		t.Errorf("Unexpected defaults: %v", cluster.Spec)
+	}
+
+	image := ImageSpec{Registry: "ceph/daemon", Tag: "latest"}
+	cluster.Spec.MonImage = image
+	cluster.Spec.OsdImage = image
+	cluster.Spec.MgrImage = image
+	cluster.Spec.MdsImage = image
+	if err := cluster.Validate(); err != nil {
+		t.Errorf("Defaulted cluster should be valid: %v", err)
+	}
+}
+
+func TestCephClusterDefaultImport(t *testing.T) {
+	cluster := &CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+	cluster.Spec.Import = &ImportSpec{MonKeyringSecretName: "mon", AdminKeyringSecretName: "admin"}
+	cluster.Default()
+
+	if cluster.Spec.Fsid != "" {
+		t.Errorf("Generated an fsid for an imported cluster: %s", cluster.Spec.Fsid)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		Name   string
+		Object Validator
+		Valid  bool
+	}{
+		{
+			Name:   "empty-fsid",
+			Object: &CephCluster{Spec: CephClusterSpec{MonServiceName: "mon"}},
+			Valid:  false,
+		},
+		{
+			Name: "import-without-admin-keyring",
+			Object: &CephCluster{Spec: CephClusterSpec{
+				Fsid:           "3f6d3ee2-9cbb-4e3b-9a3c-0b9cd0a8b5e1",
+				MonServiceName: "mon",
+				Import:         &ImportSpec{MonKeyringSecretName: "mon"},
+			}},
+			Valid: false,
+		},
+		{
+			Name: "external-without-mon-hosts",
+			Object: &CephCluster{Spec: CephClusterSpec{
+				Fsid:            "3f6d3ee2-9cbb-4e3b-9a3c-0b9cd0a8b5e1",
+				MonServiceName:  "mon",
+				External:        true,
+				ExternalCluster: &ExternalClusterSpec{ClientKeyringSecretName: "client"},
+			}},
+			Valid: false,
+		},
+		{
+			Name:   "image-without-tag",
+			Object: &CephDaemon{Spec: CephDaemonSpec{ID: "a", Image: ImageSpec{Registry: "ceph/daemon"}}},
+			Valid:  false,
+		},
+		{
+			Name: "malformed-pv-selector",
+			Object: &CephOsd{Spec: CephOsdSpec{PvSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "a", Operator: metav1.LabelSelectorOpIn}},
+			}}},
+			Valid: false,
+		},
+		{
+			Name:   "even-mon-count",
+			Object: &CephMonCluster{Spec: CephMonClusterSpec{Image: ImageSpec{Registry: "ceph/daemon", Tag: "latest"}, Count: 2}},
+			Valid:  false,
+		},
+		{
+			Name: "mon-recovery-without-nonce",
+			Object: &CephMonCluster{Spec: CephMonClusterSpec{Image: ImageSpec{Registry: "ceph/daemon", Tag: "latest"}, Count: 3,
+				Recovery: &MonRecoverySpec{SurvivorID: "a"}}},
+			Valid: false,
+		},
+		{
+			Name:   "valid-mon",
+			Object: &CephMon{Spec: CephMonSpec{ID: "a", PvSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"node": "a"}}}},
+			Valid:  true,
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(st *testing.T) {
+			err := c.Object.Validate()
+			if c.Valid && err != nil {
+				st.Errorf("Expected valid, got %v", err)
+			}
+			if !c.Valid && err == nil {
+				st.Errorf("Expected validation error")
+			}
+		})
+	}
+}
+
+func TestValidateUpdate(t *testing.T) {
+	old := &CephOsd{Spec: CephOsdSpec{ClusterName: "ceph", ID: 1}}
+
+	if err := (&CephOsd{Spec: CephOsdSpec{ClusterName: "ceph", ID: 1}}).ValidateUpdate(old); err != nil {
+		t.Errorf("Unchanged osd should be valid: %v", err)
+	}
+
+	if err := (&CephOsd{Spec: CephOsdSpec{ClusterName: "ceph", ID: 2}}).ValidateUpdate(old); err == nil {
+		t.Errorf("Changing the osd id should be rejected")
+	}
+
+	if err := (&CephCluster{Spec: CephClusterSpec{Fsid: "b"}}).ValidateUpdate(&CephCluster{Spec: CephClusterSpec{Fsid: "a"}}); err == nil {
+		t.Errorf("Changing the fsid should be rejected")
+	}
+
+	if err := (&CephCluster{Spec: CephClusterSpec{External: true}}).ValidateUpdate(&CephCluster{}); err == nil {
+		t.Errorf("Making a cluster external should be rejected")
+	}
+}
@@ -0,0 +1,1303 @@
+// +build !ignore_autogenerated
+
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by deepcopy-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	net "net"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephCapacity) DeepCopyInto(out *CephCapacity) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephCapacity.
+func (in *CephCapacity) DeepCopy() *CephCapacity {
+	if in == nil {
+		return nil
+	}
+	out := new(CephCapacity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephCluster) DeepCopyInto(out *CephCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephCluster.
+func (in *CephCluster) DeepCopy() *CephCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(CephCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephCluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephClusterHealth) DeepCopyInto(out *CephClusterHealth) {
+	*out = *in
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]CephHealthCheck, len(*in))
+		copy(*out, *in)
+	}
+	out.Capacity = in.Capacity
+	if in.PgStates != nil {
+		in, out := &in.PgStates, &out.PgStates
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	out.Osds = in.Osds
+	if in.QuorumMembers != nil {
+		in, out := &in.QuorumMembers, &out.QuorumMembers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephClusterHealth.
+func (in *CephClusterHealth) DeepCopy() *CephClusterHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(CephClusterHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephClusterList) DeepCopyInto(out *CephClusterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CephCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephClusterList.
+func (in *CephClusterList) DeepCopy() *CephClusterList {
+	if in == nil {
+		return nil
+	}
+	out := new(CephClusterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephClusterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephClusterSpec) DeepCopyInto(out *CephClusterSpec) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]map[string]string, len(*in))
+		for key, val := range *in {
+			var outVal map[string]string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(map[string]string, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	out.MonImage = in.MonImage
+	out.OsdImage = in.OsdImage
+	out.MgrImage = in.MgrImage
+	out.MdsImage = in.MdsImage
+	in.MonPlacement.DeepCopyInto(&out.MonPlacement)
+	out.Msgr2 = in.Msgr2
+	out.Network = in.Network
+	if in.ClientConfigNamespaces != nil {
+		in, out := &in.ClientConfigNamespaces, &out.ClientConfigNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Import != nil {
+		in, out := &in.Import, &out.Import
+		*out = new(ImportSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalCluster != nil {
+		in, out := &in.ExternalCluster, &out.ExternalCluster
+		*out = new(ExternalClusterSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	in.PodOverrides.DeepCopyInto(&out.PodOverrides)
+	if in.HealthCheckInterval != nil {
+		in, out := &in.HealthCheckInterval, &out.HealthCheckInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	in.HealthGates.DeepCopyInto(&out.HealthGates)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephClusterSpec.
+func (in *CephClusterSpec) DeepCopy() *CephClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CephClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephClusterStatus) DeepCopyInto(out *CephClusterStatus) {
+	*out = *in
+	if in.ClientConfigNamespaces != nil {
+		in, out := &in.ClientConfigNamespaces, &out.ClientConfigNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaintenanceNodes != nil {
+		in, out := &in.MaintenanceNodes, &out.MaintenanceNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Health != nil {
+		in, out := &in.Health, &out.Health
+		*out = new(CephClusterHealth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TransitionBlocked != nil {
+		in, out := &in.TransitionBlocked, &out.TransitionBlocked
+		*out = new(CephClusterTransitionBlock)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephClusterStatus.
+func (in *CephClusterStatus) DeepCopy() *CephClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CephClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephClusterTransitionBlock) DeepCopyInto(out *CephClusterTransitionBlock) {
+	*out = *in
+	in.Since.DeepCopyInto(&out.Since)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephClusterTransitionBlock.
+func (in *CephClusterTransitionBlock) DeepCopy() *CephClusterTransitionBlock {
+	if in == nil {
+		return nil
+	}
+	out := new(CephClusterTransitionBlock)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephDaemon) DeepCopyInto(out *CephDaemon) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	out.Status = in.Status
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephDaemon.
+func (in *CephDaemon) DeepCopy() *CephDaemon {
+	if in == nil {
+		return nil
+	}
+	out := new(CephDaemon)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephDaemon) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephDaemonCluster) DeepCopyInto(out *CephDaemonCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	out.Status = in.Status
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephDaemonCluster.
+func (in *CephDaemonCluster) DeepCopy() *CephDaemonCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(CephDaemonCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephDaemonCluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephDaemonClusterList) DeepCopyInto(out *CephDaemonClusterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CephDaemonCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephDaemonClusterList.
+func (in *CephDaemonClusterList) DeepCopy() *CephDaemonClusterList {
+	if in == nil {
+		return nil
+	}
+	out := new(CephDaemonClusterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephDaemonClusterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephDaemonClusterSpec) DeepCopyInto(out *CephDaemonClusterSpec) {
+	*out = *in
+	out.Image = in.Image
+	out.Network = in.Network
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephDaemonClusterSpec.
+func (in *CephDaemonClusterSpec) DeepCopy() *CephDaemonClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CephDaemonClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephDaemonClusterStatus) DeepCopyInto(out *CephDaemonClusterStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephDaemonClusterStatus.
+func (in *CephDaemonClusterStatus) DeepCopy() *CephDaemonClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CephDaemonClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephDaemonList) DeepCopyInto(out *CephDaemonList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CephDaemon, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephDaemonList.
+func (in *CephDaemonList) DeepCopy() *CephDaemonList {
+	if in == nil {
+		return nil
+	}
+	out := new(CephDaemonList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephDaemonList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephDaemonSpec) DeepCopyInto(out *CephDaemonSpec) {
+	*out = *in
+	out.Image = in.Image
+	out.Network = in.Network
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephDaemonSpec.
+func (in *CephDaemonSpec) DeepCopy() *CephDaemonSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CephDaemonSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephDaemonStatus) DeepCopyInto(out *CephDaemonStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephDaemonStatus.
+func (in *CephDaemonStatus) DeepCopy() *CephDaemonStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CephDaemonStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephHealthCheck) DeepCopyInto(out *CephHealthCheck) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephHealthCheck.
+func (in *CephHealthCheck) DeepCopy() *CephHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(CephHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephMon) DeepCopyInto(out *CephMon) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephMon.
+func (in *CephMon) DeepCopy() *CephMon {
+	if in == nil {
+		return nil
+	}
+	out := new(CephMon)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephMon) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephMonCluster) DeepCopyInto(out *CephMonCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephMonCluster.
+func (in *CephMonCluster) DeepCopy() *CephMonCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(CephMonCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephMonCluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephMonClusterList) DeepCopyInto(out *CephMonClusterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CephMonCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephMonClusterList.
+func (in *CephMonClusterList) DeepCopy() *CephMonClusterList {
+	if in == nil {
+		return nil
+	}
+	out := new(CephMonClusterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephMonClusterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephMonClusterSpec) DeepCopyInto(out *CephMonClusterSpec) {
+	*out = *in
+	out.Image = in.Image
+	in.Placement.DeepCopyInto(&out.Placement)
+	if in.PvSelector != nil {
+		in, out := &in.PvSelector, &out.PvSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FailureTimeout != nil {
+		in, out := &in.FailureTimeout, &out.FailureTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Recovery != nil {
+		in, out := &in.Recovery, &out.Recovery
+		*out = new(MonRecoverySpec)
+		**out = **in
+	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(MonBackupSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	out.Msgr2 = in.Msgr2
+	out.Network = in.Network
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephMonClusterSpec.
+func (in *CephMonClusterSpec) DeepCopy() *CephMonClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CephMonClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephMonClusterStatus) DeepCopyInto(out *CephMonClusterStatus) {
+	*out = *in
+	if in.LastRecovery != nil {
+		in, out := &in.LastRecovery, &out.LastRecovery
+		*out = new(MonRecoveryStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastBackupTime != nil {
+		in, out := &in.LastBackupTime, &out.LastBackupTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ActiveBackup != nil {
+		in, out := &in.ActiveBackup, &out.ActiveBackup
+		*out = new(MonActiveBackup)
+		**out = **in
+	}
+	if in.Backups != nil {
+		in, out := &in.Backups, &out.Backups
+		*out = make([]MonBackupRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephMonClusterStatus.
+func (in *CephMonClusterStatus) DeepCopy() *CephMonClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CephMonClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephMonList) DeepCopyInto(out *CephMonList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CephMon, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephMonList.
+func (in *CephMonList) DeepCopy() *CephMonList {
+	if in == nil {
+		return nil
+	}
+	out := new(CephMonList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephMonList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephMonSpec) DeepCopyInto(out *CephMonSpec) {
+	*out = *in
+	if in.PvSelector != nil {
+		in, out := &in.PvSelector, &out.PvSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephMonSpec.
+func (in *CephMonSpec) DeepCopy() *CephMonSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CephMonSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephMonStatus) DeepCopyInto(out *CephMonStatus) {
+	*out = *in
+	if in.OutOfQuorumSince != nil {
+		in, out := &in.OutOfQuorumSince, &out.OutOfQuorumSince
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephMonStatus.
+func (in *CephMonStatus) DeepCopy() *CephMonStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CephMonStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephOsd) DeepCopyInto(out *CephOsd) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephOsd.
+func (in *CephOsd) DeepCopy() *CephOsd {
+	if in == nil {
+		return nil
+	}
+	out := new(CephOsd)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephOsd) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephOsdCounts) DeepCopyInto(out *CephOsdCounts) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephOsdCounts.
+func (in *CephOsdCounts) DeepCopy() *CephOsdCounts {
+	if in == nil {
+		return nil
+	}
+	out := new(CephOsdCounts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephOsdList) DeepCopyInto(out *CephOsdList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CephOsd, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephOsdList.
+func (in *CephOsdList) DeepCopy() *CephOsdList {
+	if in == nil {
+		return nil
+	}
+	out := new(CephOsdList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephOsdList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephOsdSpec) DeepCopyInto(out *CephOsdSpec) {
+	*out = *in
+	if in.PvSelector != nil {
+		in, out := &in.PvSelector, &out.PvSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephOsdSpec.
+func (in *CephOsdSpec) DeepCopy() *CephOsdSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CephOsdSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephOsdStatus) DeepCopyInto(out *CephOsdStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephOsdStatus.
+func (in *CephOsdStatus) DeepCopy() *CephOsdStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CephOsdStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalClusterSpec) DeepCopyInto(out *ExternalClusterSpec) {
+	*out = *in
+	if in.MonHosts != nil {
+		in, out := &in.MonHosts, &out.MonHosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalClusterSpec.
+func (in *ExternalClusterSpec) DeepCopy() *ExternalClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthGateSpec) DeepCopyInto(out *HealthGateSpec) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthGateSpec.
+func (in *HealthGateSpec) DeepCopy() *HealthGateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthGateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthGatesSpec) DeepCopyInto(out *HealthGatesSpec) {
+	*out = *in
+	in.MgrAvailable.DeepCopyInto(&out.MgrAvailable)
+	in.PgsActive.DeepCopyInto(&out.PgsActive)
+	in.NoDegradedObjects.DeepCopyInto(&out.NoDegradedObjects)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthGatesSpec.
+func (in *HealthGatesSpec) DeepCopy() *HealthGatesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthGatesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageSpec) DeepCopyInto(out *ImageSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSpec.
+func (in *ImageSpec) DeepCopy() *ImageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImportSpec) DeepCopyInto(out *ImportSpec) {
+	*out = *in
+	if in.MonHosts != nil {
+		in, out := &in.MonHosts, &out.MonHosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImportSpec.
+func (in *ImportSpec) DeepCopy() *ImportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ImportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in JsonMonMap) DeepCopyInto(out *JsonMonMap) {
+	{
+		in := &in
+		*out = make(JsonMonMap, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+		return
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JsonMonMap.
+func (in JsonMonMap) DeepCopy() JsonMonMap {
+	if in == nil {
+		return nil
+	}
+	out := new(JsonMonMap)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonActiveBackup) DeepCopyInto(out *MonActiveBackup) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonActiveBackup.
+func (in *MonActiveBackup) DeepCopy() *MonActiveBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(MonActiveBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonAddr) DeepCopyInto(out *MonAddr) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonAddr.
+func (in *MonAddr) DeepCopy() *MonAddr {
+	if in == nil {
+		return nil
+	}
+	out := new(MonAddr)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in MonAddrVec) DeepCopyInto(out *MonAddrVec) {
+	{
+		in := &in
+		*out = make(MonAddrVec, len(*in))
+		copy(*out, *in)
+		return
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonAddrVec.
+func (in MonAddrVec) DeepCopy() MonAddrVec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonAddrVec)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonBackupDestination) DeepCopyInto(out *MonBackupDestination) {
+	*out = *in
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = new(corev1.PersistentVolumeClaimVolumeSource)
+		**out = **in
+	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3BackupDestination)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonBackupDestination.
+func (in *MonBackupDestination) DeepCopy() *MonBackupDestination {
+	if in == nil {
+		return nil
+	}
+	out := new(MonBackupDestination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonBackupRecord) DeepCopyInto(out *MonBackupRecord) {
+	*out = *in
+	in.CompletionTime.DeepCopyInto(&out.CompletionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonBackupRecord.
+func (in *MonBackupRecord) DeepCopy() *MonBackupRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(MonBackupRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonBackupSpec) DeepCopyInto(out *MonBackupSpec) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	in.Destination.DeepCopyInto(&out.Destination)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonBackupSpec.
+func (in *MonBackupSpec) DeepCopy() *MonBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in MonMap) DeepCopyInto(out *MonMap) {
+	{
+		in := &in
+		*out = make(MonMap, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+		return
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonMap.
+func (in MonMap) DeepCopy() MonMap {
+	if in == nil {
+		return nil
+	}
+	out := new(MonMap)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonMapEntry) DeepCopyInto(out *MonMapEntry) {
+	*out = *in
+	if in.IP != nil {
+		in, out := &in.IP, &out.IP
+		*out = make(net.IP, len(*in))
+		copy(*out, *in)
+	}
+	if in.Addrs != nil {
+		in, out := &in.Addrs, &out.Addrs
+		*out = make(MonAddrVec, len(*in))
+		copy(*out, *in)
+	}
+	if in.OutOfQuorumSince != nil {
+		in, out := &in.OutOfQuorumSince, &out.OutOfQuorumSince
+		*out = (*in).DeepCopy()
+	}
+	out.NamespacedName = in.NamespacedName
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonMapEntry.
+func (in *MonMapEntry) DeepCopy() *MonMapEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(MonMapEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonRecoverySpec) DeepCopyInto(out *MonRecoverySpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonRecoverySpec.
+func (in *MonRecoverySpec) DeepCopy() *MonRecoverySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonRecoverySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonRecoveryStatus) DeepCopyInto(out *MonRecoveryStatus) {
+	*out = *in
+	if in.RemovedMonIDs != nil {
+		in, out := &in.RemovedMonIDs, &out.RemovedMonIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.CompletionTime.DeepCopyInto(&out.CompletionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonRecoveryStatus.
+func (in *MonRecoveryStatus) DeepCopy() *MonRecoveryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MonRecoveryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Msgr2Spec) DeepCopyInto(out *Msgr2Spec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Msgr2Spec.
+func (in *Msgr2Spec) DeepCopy() *Msgr2Spec {
+	if in == nil {
+		return nil
+	}
+	out := new(Msgr2Spec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkSpec) DeepCopyInto(out *NetworkSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkSpec.
+func (in *NetworkSpec) DeepCopy() *NetworkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementSpec) DeepCopyInto(out *PlacementSpec) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementSpec.
+func (in *PlacementSpec) DeepCopy() *PlacementSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodOverrides) DeepCopyInto(out *PodOverrides) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(corev1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodSecurityContext != nil {
+		in, out := &in.PodSecurityContext, &out.PodSecurityContext
+		*out = new(corev1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodOverrides.
+func (in *PodOverrides) DeepCopy() *PodOverrides {
+	if in == nil {
+		return nil
+	}
+	out := new(PodOverrides)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodOverridesSpec) DeepCopyInto(out *PodOverridesSpec) {
+	*out = *in
+	if in.All != nil {
+		in, out := &in.All, &out.All
+		*out = new(PodOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Mon != nil {
+		in, out := &in.Mon, &out.Mon
+		*out = new(PodOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Mgr != nil {
+		in, out := &in.Mgr, &out.Mgr
+		*out = new(PodOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Mds != nil {
+		in, out := &in.Mds, &out.Mds
+		*out = new(PodOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Osd != nil {
+		in, out := &in.Osd, &out.Osd
+		*out = new(PodOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodOverridesSpec.
+func (in *PodOverridesSpec) DeepCopy() *PodOverridesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PodOverridesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3BackupDestination) DeepCopyInto(out *S3BackupDestination) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3BackupDestination.
+func (in *S3BackupDestination) DeepCopy() *S3BackupDestination {
+	if in == nil {
+		return nil
+	}
+	out := new(S3BackupDestination)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,289 @@
+package ceph
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	MonContainerName       = "ceph-mon"
+	ClientContainerName    = "ceph-client"
+	ClientAdminKeyringPath = "/keyrings/client.admin/keyring"
+
+	// ConnectTimeout is how long the ceph cli waits to connect to the monitors before failing
+	ConnectTimeout = 15 * time.Second
+	// CommandTimeout is how long a command may run before the runner gives up on it, it allows for the
+	// connect timeout so a cli that can't reach the monitors fails on its own first
+	CommandTimeout = 30 * time.Second
+)
+
+// Admin runs ceph administrative commands from inside a running monitor or client pod
+type Admin struct {
+	runner     CommandRunner
+	namespace  string
+	podName    string
+	container  string
+	cluster    string
+	clientName string
+}
+
+// NewAdmin returns an Admin that runs commands in the named monitor pod
+func NewAdmin(runner CommandRunner, namespace, monPodName, cluster string) *Admin {
+	return &Admin{runner: runner, namespace: namespace, podName: monPodName, container: MonContainerName, cluster: cluster}
+}
+
+// NewClientAdmin returns an Admin that runs commands in the named client pod as the ceph user clientName, the
+// user's keyring is mounted in place of the client.admin keyring
+func NewClientAdmin(runner CommandRunner, namespace, clientPodName, cluster, clientName string) *Admin {
+	return &Admin{runner: runner, namespace: namespace, podName: clientPodName, container: ClientContainerName,
+		cluster: cluster, clientName: clientName}
+}
+
+// Command runs the ceph cli with the given arguments as client.admin, or the client pod's user
+func (a *Admin) Command(args ...string) ([]byte, error) {
+	command := []string{"ceph", "--cluster", a.cluster, "--keyring", ClientAdminKeyringPath,
+		"--connect-timeout", strconv.Itoa(int(ConnectTimeout.Seconds()))}
+	if a.clientName != "" {
+		command = append(command, "--name", a.clientName)
+	}
+	command = append(command, args...)
+	return a.runner.Run(a.namespace, a.podName, a.container, CommandTimeout, command...)
+}
+
+// MonDumpAddr is a monitor address in the monmap
+type MonDumpAddr struct {
+	Type string `json:"type"`
+	Addr string `json:"addr"`
+}
+
+// MonDumpEntry is a monitor in the monmap
+type MonDumpEntry struct {
+	Rank int    `json:"rank"`
+	Name string `json:"name"`
+	// Addr is the v1 address in the ip:port/nonce form
+	Addr        string `json:"addr"`
+	PublicAddrs struct {
+		// AddrVec is only reported from nautilus
+		AddrVec []MonDumpAddr `json:"addrvec"`
+	} `json:"public_addrs"`
+}
+
+// MonDump is the monmap reported by ceph mon dump
+type MonDump struct {
+	Epoch int            `json:"epoch"`
+	Fsid  string         `json:"fsid"`
+	Mons  []MonDumpEntry `json:"mons"`
+}
+
+// MonDump returns the cluster's current monmap
+func (a *Admin) MonDump() (*MonDump, error) {
+	out, err := a.Command("mon", "dump", "--format", "json")
+	if err != nil {
+		return nil, err
+	}
+
+	dump := &MonDump{}
+	err = json.Unmarshal(out, dump)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse ceph mon dump: %v", err)
+	}
+	return dump, nil
+}
+
+// RemoveMon removes the monitor from the monmap
+func (a *Admin) RemoveMon(id string) error {
+	_, err := a.Command("mon", "remove", id)
+	return err
+}
+
+// HealthCheck is an active ceph health check
+type HealthCheck struct {
+	Severity string `json:"severity"`
+	Summary  struct {
+		Message string `json:"message"`
+	} `json:"summary"`
+	Detail []struct {
+		Message string `json:"message"`
+	} `json:"detail"`
+}
+
+// Health is the health of the cluster as reported by ceph health detail
+type Health struct {
+	Status string                 `json:"status"`
+	Checks map[string]HealthCheck `json:"checks"`
+}
+
+// HasCheck returns true if any of the named health checks is active
+func (h *Health) HasCheck(names ...string) bool {
+	for _, name := range names {
+		if _, ok := h.Checks[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Health returns the health of the cluster and its active health checks
+func (a *Admin) Health() (*Health, error) {
+	out, err := a.Command("health", "detail", "--format", "json")
+	if err != nil {
+		return nil, err
+	}
+
+	health := &Health{}
+	err = json.Unmarshal(out, health)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse ceph health: %v", err)
+	}
+	return health, nil
+}
+
+// OsdCounts are the osd counts of the osd map
+type OsdCounts struct {
+	NumOsds   int `json:"num_osds"`
+	NumUpOsds int `json:"num_up_osds"`
+	NumInOsds int `json:"num_in_osds"`
+}
+
+// Status is the cluster status reported by ceph status
+type Status struct {
+	Health      Health   `json:"health"`
+	QuorumNames []string `json:"quorum_names"`
+	OsdMap      struct {
+		OsdCounts
+		// OsdMap holds the counts in releases before octopus
+		OsdMap *OsdCounts `json:"osdmap"`
+	} `json:"osdmap"`
+	MgrMap struct {
+		Available bool `json:"available"`
+	} `json:"mgrmap"`
+	PgMap struct {
+		PgsByState []struct {
+			StateName string `json:"state_name"`
+			Count     int    `json:"count"`
+		} `json:"pgs_by_state"`
+		NumPgs          int   `json:"num_pgs"`
+		DegradedObjects int64 `json:"degraded_objects"`
+		BytesUsed       int64 `json:"bytes_used"`
+		BytesAvail      int64 `json:"bytes_avail"`
+		BytesTotal      int64 `json:"bytes_total"`
+	} `json:"pgmap"`
+}
+
+// InactivePgs returns the number of placement groups that aren't active
+func (s *Status) InactivePgs() int {
+	inactive := 0
+	for _, state := range s.PgMap.PgsByState {
+		active := false
+		for _, name := range strings.Split(state.StateName, "+") {
+			if name == "active" {
+				active = true
+				break
+			}
+		}
+		if !active {
+			inactive += state.Count
+		}
+	}
+	return inactive
+}
+
+// GetOsdCounts returns the osd counts of the osd map
+func (s *Status) GetOsdCounts() OsdCounts {
+	if s.OsdMap.OsdMap != nil {
+		return *s.OsdMap.OsdMap
+	}
+	return s.OsdMap.OsdCounts
+}
+
+// Status returns the status of the cluster
+func (a *Admin) Status() (*Status, error) {
+	out, err := a.Command("status", "--format", "json")
+	if err != nil {
+		return nil, err
+	}
+
+	status := &Status{}
+	err = json.Unmarshal(out, status)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse ceph status: %v", err)
+	}
+	return status, nil
+}
+
+// SetNoOut keeps the osds under the crush node from being marked out while they're down
+func (a *Admin) SetNoOut(crushNode string) error {
+	_, err := a.Command("osd", "set-group", "noout", crushNode)
+	return err
+}
+
+// UnsetNoOut clears the noout flag set on the crush node
+func (a *Admin) UnsetNoOut(crushNode string) error {
+	_, err := a.Command("osd", "unset-group", "noout", crushNode)
+	return err
+}
+
+// ActiveMgr returns the name of the active mgr, or an empty string if no mgr is active
+func (a *Admin) ActiveMgr() (string, error) {
+	out, err := a.Command("mgr", "dump", "--format", "json")
+	if err != nil {
+		return "", err
+	}
+
+	mgrMap := struct {
+		ActiveName string `json:"active_name"`
+	}{}
+	err = json.Unmarshal(out, &mgrMap)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse mgr map: %v", err)
+	}
+	return mgrMap.ActiveName, nil
+}
+
+// FailMgr fails the named mgr over to a standby
+func (a *Admin) FailMgr(name string) error {
+	_, err := a.Command("mgr", "fail", name)
+	return err
+}
+
+// ActiveMds returns the names of the active mds daemons of every filesystem
+func (a *Admin) ActiveMds() ([]string, error) {
+	out, err := a.Command("fs", "dump", "--format", "json")
+	if err != nil {
+		return nil, err
+	}
+
+	fsMap := struct {
+		Filesystems []struct {
+			MdsMap struct {
+				Info map[string]struct {
+					Name  string `json:"name"`
+					State string `json:"state"`
+				} `json:"info"`
+			} `json:"mdsmap"`
+		} `json:"filesystems"`
+	}{}
+	err = json.Unmarshal(out, &fsMap)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse fs map: %v", err)
+	}
+
+	active := []string{}
+	for _, fs := range fsMap.Filesystems {
+		for _, info := range fs.MdsMap.Info {
+			if info.State == "up:active" {
+				active = append(active, info.Name)
+			}
+		}
+	}
+	return active, nil
+}
+
+// FailMds fails the named mds over to a standby
+func (a *Admin) FailMds(name string) error {
+	_, err := a.Command("mds", "fail", name)
+	return err
+}
@@ -0,0 +1,55 @@
+package ceph
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// FakeRunner is a CommandRunner for tests.  It records the commands it runs and returns the output of the
+// first entry in Outputs that the command ends with.
+type FakeRunner struct {
+	// Outputs maps the end of a command, joined with spaces, to its output
+	Outputs map[string]string
+	// Errors maps the end of a command, joined with spaces, to the error it returns
+	Errors map[string]error
+	// Commands are the commands that have been run, joined with spaces
+	Commands []string
+	// Timeouts are the timeouts the commands were run with
+	Timeouts []time.Duration
+}
+
+func (f *FakeRunner) Run(namespace, podName, container string, timeout time.Duration, command ...string) ([]byte, error) {
+	joined := strings.Join(command, " ")
+	f.Commands = append(f.Commands, joined)
+	f.Timeouts = append(f.Timeouts, timeout)
+
+	for suffix, err := range f.Errors {
+		if strings.HasSuffix(joined, suffix) {
+			return nil, err
+		}
+	}
+
+	for suffix, output := range f.Outputs {
+		if strings.HasSuffix(joined, suffix) {
+			return []byte(output), nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Ran returns true if a command ending with suffix has been run
+func (f *FakeRunner) Ran(suffix string) bool {
+	for _, command := range f.Commands {
+		if strings.HasSuffix(command, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// String lists the commands that have been run
+func (f *FakeRunner) String() string {
+	return fmt.Sprintf("%q", f.Commands)
+}
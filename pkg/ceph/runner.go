@@ -0,0 +1,75 @@
+package ceph
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// CommandRunner runs a command in a container of a running pod, giving up on it once timeout has passed
+type CommandRunner interface {
+	Run(namespace, podName, container string, timeout time.Duration, command ...string) ([]byte, error)
+}
+
+type podExecRunner struct {
+	config    *rest.Config
+	clientset kubernetes.Interface
+}
+
+// NewPodExecRunner returns a CommandRunner that uses the pod exec api
+func NewPodExecRunner(config *rest.Config) (CommandRunner, error) {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &podExecRunner{config: config, clientset: clientset}, nil
+}
+
+// Run execs the command in the pod.  The exec api can't be cancelled, a command still running at the timeout is
+// abandoned and left to finish in the pod.
+func (r *podExecRunner) Run(namespace, podName, container string, timeout time.Duration, command ...string) ([]byte, error) {
+	req := r.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(podName).
+		Namespace(namespace).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	exec, err := remotecommand.NewSPDYExecutor(r.config, "POST", req.URL())
+	if err != nil {
+		return nil, err
+	}
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	done := make(chan error, 1)
+	go func() {
+		done <- exec.Stream(remotecommand.StreamOptions{
+			Stdout: stdout,
+			Stderr: stderr,
+		})
+	}()
+
+	select {
+	case err = <-done:
+	case <-time.After(timeout):
+		return nil, fmt.Errorf("command %v in pod %s/%s timed out after %s", command, namespace, podName, timeout)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("command %v failed in pod %s/%s: %v: %s", command, namespace, podName, err, stderr.String())
+	}
+
+	return stdout.Bytes(), nil
+}
@@ -3,7 +3,7 @@ package cephcluster
 import (
 	"context"
 
-	cephv1alpha1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1alpha1"
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -13,8 +13,8 @@ import (
 type TransitionFunc func(client.Client, *runtime.Scheme) error
 
 type CephClusterStateMachine interface {
-	State() cephv1alpha1.CephClusterState
-	GetTransition(ReadOnlyClient) (TransitionFunc, cephv1alpha1.CephClusterState)
+	State() cephv1beta1.CephClusterState
+	GetTransition(ReadOnlyClient) (TransitionFunc, cephv1beta1.CephClusterState)
 }
 
 type ReadOnlyClient interface {
@@ -22,25 +22,26 @@ type ReadOnlyClient interface {
 	List(context.Context, *client.ListOptions, runtime.Object) error
 }
 
-func NewCephClusterStateMachine(cluster *cephv1alpha1.CephCluster, logger logr.Logger) CephClusterStateMachine {
+func NewCephClusterStateMachine(cluster *cephv1beta1.CephCluster, logger logr.Logger) CephClusterStateMachine {
 
 	return newBaseStateMachine(cluster, logger)
 }
 
-func newBaseStateMachine(cluster *cephv1alpha1.CephCluster, logger logr.Logger) *BaseStateMachine {
+func newBaseStateMachine(cluster *cephv1beta1.CephCluster, logger logr.Logger) *BaseStateMachine {
 	return &BaseStateMachine{cluster: cluster, logger: logger}
 }
 
 type BaseStateMachine struct {
-	cluster *cephv1alpha1.CephCluster
+	cluster *cephv1beta1.CephCluster
 	logger  logr.Logger
 }
 
+// clusterEnabled is false for a disabled cluster and for a deleted cluster waiting on teardown
 func (s *BaseStateMachine) clusterEnabled() bool {
-	return !s.cluster.Spec.Disabled
+	return !s.cluster.Spec.Disabled && !s.cluster.IsDeleting()
 }
 
-func (s *BaseStateMachine) State() cephv1alpha1.CephClusterState {
+func (s *BaseStateMachine) State() cephv1beta1.CephClusterState {
 	return s.cluster.GetState()
 }
 
@@ -57,21 +58,21 @@ func (s *BaseStateMachine) emitError(err error) TransitionFunc {
 	})
 }
 
-func (s *BaseStateMachine) listMonCluster(readClient ReadOnlyClient) (*cephv1alpha1.CephMonClusterList, error) {
-	monClusterList := &cephv1alpha1.CephMonClusterList{}
-	monClusterListOptions := &client.ListOptions{}
+func (s *BaseStateMachine) listMonCluster(readClient ReadOnlyClient) (*cephv1beta1.CephMonClusterList, error) {
+	monClusterList := &cephv1beta1.CephMonClusterList{}
+	monClusterListOptions := &client.ListOptions{Namespace: s.cluster.GetNamespace()}
 	monClusterListOptions.MatchingLabels(map[string]string{
-		cephv1alpha1.ClusterNameLabel: s.cluster.GetName(),
+		cephv1beta1.ClusterNameLabel: s.cluster.GetName(),
 	})
 
 	return monClusterList, readClient.List(context.TODO(), monClusterListOptions, monClusterList)
 }
 
-func (s *BaseStateMachine) listDaemonCluster(readClient ReadOnlyClient) (*cephv1alpha1.CephDaemonClusterList, error) {
-	daemonClusterList := &cephv1alpha1.CephDaemonClusterList{}
-	daemonClusterListOptions := &client.ListOptions{}
+func (s *BaseStateMachine) listDaemonCluster(readClient ReadOnlyClient) (*cephv1beta1.CephDaemonClusterList, error) {
+	daemonClusterList := &cephv1beta1.CephDaemonClusterList{}
+	daemonClusterListOptions := &client.ListOptions{Namespace: s.cluster.GetNamespace()}
 	daemonClusterListOptions.MatchingLabels(map[string]string{
-		cephv1alpha1.ClusterNameLabel: s.cluster.GetName(),
+		cephv1beta1.ClusterNameLabel: s.cluster.GetName(),
 	})
 
 	return daemonClusterList, readClient.List(context.TODO(), daemonClusterListOptions, daemonClusterList)
@@ -84,7 +85,7 @@ func (s *BaseStateMachine) monClusterInQuorum(readClient ReadOnlyClient) (bool,
 		return false, err
 	}
 
-	return monList.AllInState(cephv1alpha1.MonClusterInQuorum), nil
+	return monList.AllInState(cephv1beta1.MonClusterInQuorum), nil
 }
 
 func (s *BaseStateMachine) daemonClustersRunning(readClient ReadOnlyClient) (bool, error) {
@@ -93,7 +94,7 @@ func (s *BaseStateMachine) daemonClustersRunning(readClient ReadOnlyClient) (boo
 		return false, err
 	}
 
-	return daemonList.AllInState(cephv1alpha1.CephDaemonClusterStateRunning), nil
+	return daemonList.AllInState(cephv1beta1.CephDaemonClusterStateRunning), nil
 }
 
 func (s *BaseStateMachine) osdsRunning(readClient ReadOnlyClient) (bool, error) {
@@ -107,7 +108,7 @@ func (s *BaseStateMachine) daemonClustersIdle(readClient ReadOnlyClient) (bool,
 		return false, err
 	}
 
-	return daemonList.AllInState(cephv1alpha1.CephDaemonClusterStateIdle), nil
+	return daemonList.AllInState(cephv1beta1.CephDaemonClusterStateIdle), nil
 }
 
 func (s *BaseStateMachine) osdsIdle(readClient ReadOnlyClient) (bool, error) {
@@ -121,62 +122,74 @@ func (s *BaseStateMachine) monClusterIdle(readClient ReadOnlyClient) (bool, erro
 		return false, err
 	}
 
-	return monList.AllInState(cephv1alpha1.MonClusterIdle), nil
+	return monList.AllInState(cephv1beta1.MonClusterIdle), nil
 }
 
-func (s *BaseStateMachine) GetTransition(readClient ReadOnlyClient) (TransitionFunc, cephv1alpha1.CephClusterState) {
+func (s *BaseStateMachine) GetTransition(readClient ReadOnlyClient) (TransitionFunc, cephv1beta1.CephClusterState) {
+	if s.cluster.Spec.External {
+		return s.getExternalTransition()
+	}
 
 	switch s.State() {
-	case cephv1alpha1.CephClusterIdle:
+	case cephv1beta1.CephClusterIdle:
 		if s.clusterEnabled() {
-			return nil, cephv1alpha1.CephClusterStartMons
+			return nil, cephv1beta1.CephClusterStartMons
 		}
-	case cephv1alpha1.CephClusterStartMons:
+	case cephv1beta1.CephClusterStartMons:
 		if !s.clusterEnabled() {
-			return nil, cephv1alpha1.CephClusterShutdown
+			return nil, cephv1beta1.CephClusterShutdown
 		}
-		return s.ifReady(readClient, s.monClusterInQuorum, cephv1alpha1.CephClusterStartDaemons)
+		return s.ifReady(readClient, s.monClusterInQuorum, cephv1beta1.CephClusterStartDaemons)
 
-	case cephv1alpha1.CephClusterStartDaemons:
+	case cephv1beta1.CephClusterStartDaemons:
 		if !s.clusterEnabled() {
-			return nil, cephv1alpha1.CephClusterShutdown
+			return nil, cephv1beta1.CephClusterShutdown
 		}
-		return s.ifReady(readClient, s.daemonClustersRunning, cephv1alpha1.CephClusterStartOsds)
+		return s.ifReady(readClient, s.daemonClustersRunning, cephv1beta1.CephClusterStartOsds)
 
-	case cephv1alpha1.CephClusterStartOsds:
+	case cephv1beta1.CephClusterStartOsds:
 		if !s.clusterEnabled() {
-			return nil, cephv1alpha1.CephClusterShutdown
+			return nil, cephv1beta1.CephClusterShutdown
 		}
-		return s.ifReady(readClient, s.osdsRunning, cephv1alpha1.CephClusterRunning)
+		return s.ifReady(readClient, s.osdsRunning, cephv1beta1.CephClusterRunning)
 
-	case cephv1alpha1.CephClusterRunning:
+	case cephv1beta1.CephClusterRunning:
 		if !s.clusterEnabled() {
-			return nil, cephv1alpha1.CephClusterShutdown
+			return nil, cephv1beta1.CephClusterShutdown
 		}
 
-	case cephv1alpha1.CephClusterShutdown:
-		return nil, cephv1alpha1.CephClusterStopDaemons
+	case cephv1beta1.CephClusterShutdown:
+		return nil, cephv1beta1.CephClusterStopDaemons
 
-	case cephv1alpha1.CephClusterStopDaemons:
-		return s.ifReady(readClient, s.daemonClustersIdle, cephv1alpha1.CephClusterStopOsds)
+	case cephv1beta1.CephClusterStopDaemons:
+		return s.ifReady(readClient, s.daemonClustersIdle, cephv1beta1.CephClusterStopOsds)
 
-	case cephv1alpha1.CephClusterStopOsds:
-		return s.ifReady(readClient, s.osdsIdle, cephv1alpha1.CephClusterStopMons)
+	case cephv1beta1.CephClusterStopOsds:
+		return s.ifReady(readClient, s.osdsIdle, cephv1beta1.CephClusterStopMons)
 
-	case cephv1alpha1.CephClusterStopMons:
-		return s.ifReady(readClient, s.monClusterIdle, cephv1alpha1.CephClusterIdle)
+	case cephv1beta1.CephClusterStopMons:
+		return s.ifReady(readClient, s.monClusterIdle, cephv1beta1.CephClusterIdle)
 
 	default:
-		return nil, cephv1alpha1.CephClusterShutdown
+		return nil, cephv1beta1.CephClusterShutdown
 	}
 
 	return nil, s.State()
 }
 
+// getExternalTransition moves an external cluster straight between Idle and Running, there are no daemons
+// to start or stop
+func (s *BaseStateMachine) getExternalTransition() (TransitionFunc, cephv1beta1.CephClusterState) {
+	if s.clusterEnabled() {
+		return nil, cephv1beta1.CephClusterRunning
+	}
+	return nil, cephv1beta1.CephClusterIdle
+}
+
 type readyCheck func(ReadOnlyClient) (bool, error)
 
 func (s *BaseStateMachine) ifReady(readClient ReadOnlyClient, ready readyCheck,
-	nextState cephv1alpha1.CephClusterState) (TransitionFunc, cephv1alpha1.CephClusterState) {
+	nextState cephv1beta1.CephClusterState) (TransitionFunc, cephv1beta1.CephClusterState) {
 
 	isReady, err := ready(readClient)
 	if err != nil {
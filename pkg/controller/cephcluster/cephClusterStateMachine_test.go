@@ -0,0 +1,44 @@
+package cephcluster
+
+import (
+	"testing"
+
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func TestGetTransition(t *testing.T) {
+	deleted := metav1.Now()
+
+	testCases := []struct {
+		Name          string
+		State         cephv1beta1.CephClusterState
+		Spec          cephv1beta1.CephClusterSpec
+		Deleted       bool
+		ExpectedState cephv1beta1.CephClusterState
+	}{
+		{Name: "idle-enabled", State: cephv1beta1.CephClusterIdle, ExpectedState: cephv1beta1.CephClusterStartMons},
+		{Name: "idle-deleted", State: cephv1beta1.CephClusterIdle, Deleted: true, ExpectedState: cephv1beta1.CephClusterIdle},
+		{Name: "running-deleted", State: cephv1beta1.CephClusterRunning, Deleted: true, ExpectedState: cephv1beta1.CephClusterShutdown},
+		{Name: "running-disabled", State: cephv1beta1.CephClusterRunning, Spec: cephv1beta1.CephClusterSpec{Disabled: true}, ExpectedState: cephv1beta1.CephClusterShutdown},
+		{Name: "external-idle", State: cephv1beta1.CephClusterIdle, Spec: cephv1beta1.CephClusterSpec{External: true}, ExpectedState: cephv1beta1.CephClusterRunning},
+		{Name: "external-deleted", State: cephv1beta1.CephClusterRunning, Spec: cephv1beta1.CephClusterSpec{External: true}, Deleted: true, ExpectedState: cephv1beta1.CephClusterIdle},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.Name, func(t *testing.T) {
+			cluster := &cephv1beta1.CephCluster{Spec: c.Spec}
+			cluster.SetState(c.State)
+			if c.Deleted {
+				cluster.SetDeletionTimestamp(&deleted)
+			}
+
+			sm := NewCephClusterStateMachine(cluster, logf.Log)
+			_, state := sm.GetTransition(nil)
+			if state != c.ExpectedState {
+				t.Errorf("expected transition to %s, got %s", c.ExpectedState, state)
+			}
+		})
+	}
+}
@@ -3,12 +3,21 @@ package cephcluster
 import (
 	"context"
 	"fmt"
-
-	cephv1alpha1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1alpha1"
+	"reflect"
+	"sort"
+	"time"
+
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/ceph"
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/controller/common"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -29,12 +38,20 @@ var log = logf.Log.WithName("controller_cephcluster")
 // Add creates a new CephCluster Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager) error {
-	return add(mgr, newReconciler(mgr))
+	r, err := newReconciler(mgr)
+	if err != nil {
+		return err
+	}
+	return add(mgr, r)
 }
 
 // newReconciler returns a new reconcile.Reconciler
-func newReconciler(mgr manager.Manager) reconcile.Reconciler {
-	return &ReconcileCephCluster{client: mgr.GetClient(), scheme: mgr.GetScheme()}
+func newReconciler(mgr manager.Manager) (reconcile.Reconciler, error) {
+	runner, err := ceph.NewPodExecRunner(mgr.GetConfig())
+	if err != nil {
+		return nil, err
+	}
+	return &ReconcileCephCluster{client: mgr.GetClient(), scheme: mgr.GetScheme(), runner: runner}, nil
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
@@ -46,23 +63,52 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	}
 
 	// Watch for changes to primary resource CephCluster
-	err = c.Watch(&source.Kind{Type: &cephv1alpha1.CephCluster{}}, &handler.EnqueueRequestForObject{})
+	err = c.Watch(&source.Kind{Type: &cephv1beta1.CephCluster{}}, &handler.EnqueueRequestForObject{}, common.WatchedNamespace)
 	if err != nil {
 		return err
 	}
 
-	err = c.Watch(&source.Kind{Type: &cephv1alpha1.CephMonCluster{}}, &handler.EnqueueRequestForOwner{
+	err = c.Watch(&source.Kind{Type: &cephv1beta1.CephMonCluster{}}, &handler.EnqueueRequestForOwner{
 		IsController: true,
-		OwnerType:    &cephv1alpha1.CephCluster{},
-	})
+		OwnerType:    &cephv1beta1.CephCluster{},
+	}, common.WatchedNamespace)
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(&source.Kind{Type: &cephv1beta1.CephMon{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: &MonEventMapper{},
+	}, common.WatchedNamespace)
 	if err != nil {
 		return err
 	}
 
-	err = c.Watch(&source.Kind{Type: &cephv1alpha1.CephDaemonCluster{}}, &handler.EnqueueRequestForOwner{
+	err = c.Watch(&source.Kind{Type: &cephv1beta1.CephDaemonCluster{}}, &handler.EnqueueRequestForOwner{
 		IsController: true,
-		OwnerType:    &cephv1alpha1.CephCluster{},
-	})
+		OwnerType:    &cephv1beta1.CephCluster{},
+	}, common.WatchedNamespace)
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(&source.Kind{Type: &corev1.Pod{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: &PodEventMapper{},
+	}, common.WatchedNamespace)
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(&source.Kind{Type: &corev1.Node{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: &NodeEventMapper{Client: mgr.GetClient()},
+	}, maintenanceAnnotationChanged)
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(&source.Kind{Type: &policyv1beta1.PodDisruptionBudget{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &cephv1beta1.CephCluster{},
+	}, common.WatchedNamespace)
 	if err != nil {
 		return err
 	}
@@ -78,6 +124,7 @@ type ReconcileCephCluster struct {
 	// that reads objects from the cache and writes to the apiserver
 	client client.Client
 	scheme *runtime.Scheme
+	runner ceph.CommandRunner
 }
 
 // The Controller will requeue the Request to be processed again if the returned error is non-nil or
@@ -87,7 +134,7 @@ func (r *ReconcileCephCluster) Reconcile(request reconcile.Request) (reconcile.R
 	reqLogger.Info("Reconciling CephCluster")
 
 	// Fetch the CephCluster instance
-	instance := &cephv1alpha1.CephCluster{}
+	instance := &cephv1beta1.CephCluster{}
 	err := r.client.Get(context.TODO(), request.NamespacedName, instance)
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -100,26 +147,55 @@ func (r *ReconcileCephCluster) Reconcile(request reconcile.Request) (reconcile.R
 		return reconcile.Result{}, err
 	}
 
+	if instance.IsDeleting() {
+		return r.teardown(instance, reqLogger)
+	}
+
+	if common.AddFinalizer(instance, cephv1beta1.CephClusterFinalizer) {
+		return reconcile.Result{}, r.updateObject(instance)
+	}
+
 	// Create Configmap
 	err = r.updateCephConfConfigMap(instance)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 
+	// External clusters don't run daemons
+	if instance.Spec.External {
+		err = r.syncExternalKeyring(instance)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		result, err := r.transition(instance, reqLogger)
+		if err != nil {
+			return result, err
+		}
+
+		nextPoll, err := r.pollHealth(instance)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		return requeueWithin(result, nextPoll), nil
+	}
+
 	// Create or update monitor Service
-	svc := instance.GetMonitorService()
-	svc.Namespace = instance.Namespace
+	err = r.ensureMonitorService(instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
 
-	err = r.createIfNotFound(svc)
+	err = r.ensureRBAC(instance)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 
 	// Create Daemon Clusters
 	for _, o := range []DaemonClusterObject{
-		&cephv1alpha1.CephMonCluster{},
-		cephv1alpha1.NewCephDaemonCluster(cephv1alpha1.CephDaemonTypeMgr),
-		cephv1alpha1.NewCephDaemonCluster(cephv1alpha1.CephDaemonTypeMds),
+		&cephv1beta1.CephMonCluster{},
+		cephv1beta1.NewCephDaemonCluster(cephv1beta1.CephDaemonTypeMgr),
+		cephv1beta1.NewCephDaemonCluster(cephv1beta1.CephDaemonTypeMds),
 	} {
 		err = r.createDaemonCluster(o, instance)
 		if err != nil {
@@ -127,24 +203,82 @@ func (r *ReconcileCephCluster) Reconcile(request reconcile.Request) (reconcile.R
 		}
 	}
 
+	err = r.syncMonCluster(instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if instance.Status.MonClusterName != instance.GetName() {
+		instance.Status.MonClusterName = instance.GetName()
+		return reconcile.Result{}, r.updateObject(instance)
+	}
+
+	err = r.syncMaintenance(instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	err = r.ensureDisruptionBudgets(instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	result, err := r.transition(instance, reqLogger)
+	if err != nil {
+		return result, err
+	}
+
+	nextPoll, err := r.pollHealth(instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	return requeueWithin(result, nextPoll), nil
+}
+
+// requeueWithin requeues the request after at most d, a result that already requeues sooner is unchanged
+func requeueWithin(result reconcile.Result, d time.Duration) reconcile.Result {
+	if d <= 0 || result.Requeue {
+		return result
+	}
+	if result.RequeueAfter == 0 || d < result.RequeueAfter {
+		result.RequeueAfter = d
+	}
+	return result
+}
+
+// transition moves the cluster to the next state of the state machine
+func (r *ReconcileCephCluster) transition(instance *cephv1beta1.CephCluster, reqLogger logr.Logger) (reconcile.Result, error) {
 	sm := NewCephClusterStateMachine(instance, reqLogger)
 
 	currentState := sm.State()
 	transtionFunc, nextState := sm.GetTransition(r.client)
 
 	if transtionFunc != nil {
-		err = transtionFunc(r.client, r.scheme)
+		err := transtionFunc(r.client, r.scheme)
 		if err != nil {
 			return reconcile.Result{}, err
 		}
 	}
 
 	if nextState == currentState {
+		if instance.Status.TransitionBlocked != nil {
+			instance.Status.TransitionBlocked = nil
+			return reconcile.Result{}, r.updateObject(instance)
+		}
 		return reconcile.Result{}, nil
 	}
 
+	blocked, err := r.healthGateBlocks(instance, nextState, reqLogger)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if blocked {
+		return reconcile.Result{RequeueAfter: healthGateRecheckInterval}, nil
+	}
+
 	reqLogger.Info(fmt.Sprintf("transitioning from %s to %s", currentState, nextState))
 	instance.SetState(nextState)
+	instance.Status.TransitionBlocked = nil
 	return reconcile.Result{}, r.updateObject(instance)
 
 }
@@ -153,31 +287,36 @@ type DaemonClusterObject interface {
 	metav1.Object
 	runtime.Object
 	SetCephClusterName(string)
-	SetImage(cephv1alpha1.ImageSpec)
+	SetImage(cephv1beta1.ImageSpec)
 	SetCephConfConfigMapName(string)
-	GetDaemonType() cephv1alpha1.CephDaemonType
+	SetNetwork(cephv1beta1.NetworkSpec)
+	GetDaemonType() cephv1beta1.CephDaemonType
 }
 
-func (r *ReconcileCephCluster) createDaemonCluster(o DaemonClusterObject, cluster *cephv1alpha1.CephCluster) error {
+func (r *ReconcileCephCluster) createDaemonCluster(o DaemonClusterObject, cluster *cephv1beta1.CephCluster) error {
 	o.SetName(cluster.GetName())
 	o.SetNamespace(cluster.GetNamespace())
 	o.SetCephClusterName(cluster.GetName())
 	o.SetCephConfConfigMapName(cluster.GetCephConfigMapName())
+	o.SetNetwork(cluster.Spec.Network)
 	o.SetLabels(map[string]string{
-		cephv1alpha1.ClusterNameLabel: cluster.GetName(),
-		cephv1alpha1.DaemonTypeLabel:  o.GetDaemonType().String(),
+		cephv1beta1.ClusterNameLabel: cluster.GetName(),
+		cephv1beta1.DaemonTypeLabel:  o.GetDaemonType().String(),
 	})
 
 	switch v := o.(type) {
-	case *cephv1alpha1.CephMonCluster:
+	case *cephv1beta1.CephMonCluster:
 		o.SetImage(cluster.Spec.MonImage)
-	case *cephv1alpha1.CephDaemonCluster:
+		v.SetPlacement(cluster.Spec.MonPlacement)
+		v.Spec.Msgr2 = cluster.Spec.Msgr2
+		v.Spec.ExternalQuorum = cluster.HasExternalQuorum()
+	case *cephv1beta1.CephDaemonCluster:
 		o.SetName(fmt.Sprintf("%s-%s", cluster.GetName(), v.Spec.DaemonType))
 		v.Spec.Replicas = 3
 		switch v.Spec.DaemonType {
-		case cephv1alpha1.CephDaemonTypeMgr:
+		case cephv1beta1.CephDaemonTypeMgr:
 			o.SetImage(cluster.Spec.MgrImage)
-		case cephv1alpha1.CephDaemonTypeMds:
+		case cephv1beta1.CephDaemonTypeMds:
 			o.SetImage(cluster.Spec.MdsImage)
 		default:
 			return fmt.Errorf("Could not determine image for type %s", v.Spec.DaemonType)
@@ -191,7 +330,155 @@ func (r *ReconcileCephCluster) createDaemonCluster(o DaemonClusterObject, cluste
 		return err
 	}
 
-	return r.createIfNotFound(o)
+	err := r.client.Create(context.TODO(), o)
+	if !errors.IsAlreadyExists(err) {
+		return err
+	}
+
+	// The mon cluster is kept in sync by syncMonCluster
+	if daemonCluster, ok := o.(*cephv1beta1.CephDaemonCluster); ok {
+		return r.syncDaemonClusterNetwork(daemonCluster, cluster)
+	}
+	return nil
+}
+
+// syncDaemonClusterNetwork updates the network settings of an existing daemon cluster to match the ceph cluster
+func (r *ReconcileCephCluster) syncDaemonClusterNetwork(o *cephv1beta1.CephDaemonCluster, cluster *cephv1beta1.CephCluster) error {
+	existing := &cephv1beta1.CephDaemonCluster{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: o.GetName(), Namespace: o.GetNamespace()}, existing)
+	if err != nil {
+		return err
+	}
+
+	if existing.Spec.Network == cluster.Spec.Network {
+		return nil
+	}
+
+	existing.SetNetwork(cluster.Spec.Network)
+	return r.updateObject(existing)
+}
+
+// syncMonCluster updates the mon cluster with monitor settings from the ceph cluster spec
+func (r *ReconcileCephCluster) syncMonCluster(cluster *cephv1beta1.CephCluster) error {
+	monCluster := &cephv1beta1.CephMonCluster{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{
+		Name:      cluster.GetName(),
+		Namespace: cluster.GetNamespace(),
+	}, monCluster)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if reflect.DeepEqual(monCluster.GetPlacement(), cluster.Spec.MonPlacement) &&
+		monCluster.Spec.Msgr2 == cluster.Spec.Msgr2 && monCluster.Spec.Network == cluster.Spec.Network &&
+		monCluster.Spec.ExternalQuorum == cluster.HasExternalQuorum() {
+		return nil
+	}
+
+	monCluster.SetPlacement(cluster.Spec.MonPlacement)
+	monCluster.Spec.Msgr2 = cluster.Spec.Msgr2
+	monCluster.SetNetwork(cluster.Spec.Network)
+	monCluster.Spec.ExternalQuorum = cluster.HasExternalQuorum()
+	return r.updateObject(monCluster)
+}
+
+// ensureMonitorService creates the monitor service, services created before the selector included the
+// cluster name are updated to select only this cluster's monitors once the running monitor pods carry the
+// new label
+func (r *ReconcileCephCluster) ensureMonitorService(instance *cephv1beta1.CephCluster) error {
+	svc := instance.GetMonitorService()
+	svc.Namespace = instance.Namespace
+
+	existing := &corev1.Service{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: svc.Namespace, Name: svc.Name}, existing)
+	if errors.IsNotFound(err) {
+		return r.createIfNotFound(svc)
+	}
+	if err != nil {
+		return err
+	}
+
+	if reflect.DeepEqual(existing.Spec.Selector, svc.Spec.Selector) {
+		return nil
+	}
+
+	err = r.labelMonitorPods(instance)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Updating monitor service selector", "Service", svc.Name)
+	existing.Spec.Selector = svc.Spec.Selector
+	return r.updateObject(existing)
+}
+
+// labelMonitorPods adds the cluster scoped monitor service label to monitor pods created before the
+// label held the cluster name, so they stay selected when the service selector is updated
+func (r *ReconcileCephCluster) labelMonitorPods(instance *cephv1beta1.CephCluster) error {
+	podList := &corev1.PodList{}
+	listOptions := &client.ListOptions{Namespace: instance.GetNamespace()}
+	listOptions.MatchingLabels(map[string]string{cephv1beta1.MonitorServiceLabel: ""})
+	err := r.client.List(context.TODO(), listOptions, podList)
+	if err != nil {
+		return err
+	}
+
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if value, ok := pod.Labels[cephv1beta1.MonitorServiceLabel]; !ok || value != "" {
+			continue
+		}
+
+		monitorPod, err := r.isMonitorPod(instance, pod)
+		if err != nil {
+			return err
+		}
+		if !monitorPod {
+			continue
+		}
+
+		log.Info("Labelling monitor pod for the monitor service", "Pod", pod.Name)
+		pod.Labels[cephv1beta1.MonitorServiceLabel] = instance.GetName()
+		pod.Labels[cephv1beta1.ClusterNameLabel] = instance.GetName()
+		pod.Labels[cephv1beta1.DaemonTypeLabel] = cephv1beta1.CephDaemonTypeMon.String()
+		err = r.updateObject(pod)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isMonitorPod returns true if the pod belongs to one of the cluster's monitors.  Pods launched before they
+// carried the cluster name label are tied to the cluster through their CephMon owner.
+func (r *ReconcileCephCluster) isMonitorPod(instance *cephv1beta1.CephCluster, pod *corev1.Pod) (bool, error) {
+	if clusterName, ok := pod.Labels[cephv1beta1.ClusterNameLabel]; ok {
+		return clusterName == instance.GetName() &&
+			pod.Labels[cephv1beta1.DaemonTypeLabel] == cephv1beta1.CephDaemonTypeMon.String(), nil
+	}
+
+	for _, ref := range pod.GetOwnerReferences() {
+		if ref.Kind != "CephMon" {
+			continue
+		}
+
+		mon := &cephv1beta1.CephMon{}
+		err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: pod.GetNamespace(), Name: ref.Name}, mon)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+
+		if mon.GetUID() == ref.UID && mon.Spec.ClusterName == instance.GetName() {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 func (r *ReconcileCephCluster) createIfNotFound(o runtime.Object) error {
@@ -210,12 +497,121 @@ func (r *ReconcileCephCluster) updateObject(object runtime.Object) error {
 	return nil
 }
 
-func (r *ReconcileCephCluster) updateCephConfConfigMap(instance *cephv1alpha1.CephCluster) error {
-	configMap, err := instance.GetCephConfigMap()
+// updateCephConfConfigMap writes ceph.conf for the current monitor membership and publishes it to
+// the client namespaces
+func (r *ReconcileCephCluster) updateCephConfConfigMap(instance *cephv1beta1.CephCluster) error {
+	monMap, err := r.getMonMap(instance)
+	if err != nil {
+		return err
+	}
+
+	configMap, err := instance.GetCephConfigMap(monMap)
 	if err != nil {
 		return err
 	}
 	configMap.Namespace = instance.GetNamespace()
 
-	return r.createIfNotFound(configMap)
+	existing := &corev1.ConfigMap{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: configMap.Namespace, Name: configMap.Name}, existing)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	changed := true
+	switch {
+	case errors.IsNotFound(err):
+		err = r.createIfNotFound(configMap)
+	case !reflect.DeepEqual(existing.Data, configMap.Data):
+		log.Info("Updating ceph.conf", "ConfigMap", configMap.Name)
+		existing.Data = configMap.Data
+		err = r.updateObject(existing)
+	default:
+		changed = false
+	}
+	if err != nil {
+		return err
+	}
+
+	return r.publishClientConfig(instance, configMap, changed)
+}
+
+// publishClientConfig copies ceph.conf to the client namespaces.  Every copy is rewritten when the
+// config changes, otherwise only namespaces that haven't received a copy are written.
+func (r *ReconcileCephCluster) publishClientConfig(instance *cephv1beta1.CephCluster, configMap *corev1.ConfigMap, changed bool) error {
+	published := make(map[string]bool)
+	for _, namespace := range instance.Status.ClientConfigNamespaces {
+		published[namespace] = true
+	}
+
+	wanted := make(map[string]bool)
+	for _, namespace := range instance.Spec.ClientConfigNamespaces {
+		if namespace == instance.GetNamespace() {
+			continue
+		}
+		wanted[namespace] = true
+
+		if published[namespace] && !changed {
+			continue
+		}
+
+		clientConfigMap := &corev1.ConfigMap{}
+		clientConfigMap.Name = configMap.Name
+		clientConfigMap.Namespace = namespace
+		clientConfigMap.SetLabels(map[string]string{cephv1beta1.ClusterNameLabel: instance.GetName()})
+		clientConfigMap.Data = configMap.Data
+
+		err := r.client.Create(context.TODO(), clientConfigMap)
+		if errors.IsAlreadyExists(err) {
+			err = r.client.Update(context.TODO(), clientConfigMap)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	for namespace := range published {
+		if wanted[namespace] {
+			continue
+		}
+
+		clientConfigMap := &corev1.ConfigMap{}
+		clientConfigMap.Name = configMap.Name
+		clientConfigMap.Namespace = namespace
+		err := r.client.Delete(context.TODO(), clientConfigMap)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	namespaces := make([]string, 0, len(wanted))
+	for namespace := range wanted {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+
+	if len(namespaces) == len(instance.Status.ClientConfigNamespaces) &&
+		(len(namespaces) == 0 || reflect.DeepEqual(namespaces, instance.Status.ClientConfigNamespaces)) {
+		return nil
+	}
+
+	instance.Status.ClientConfigNamespaces = namespaces
+	return r.updateObject(instance)
+}
+
+func (r *ReconcileCephCluster) getMonMap(instance *cephv1beta1.CephCluster) (cephv1beta1.MonMap, error) {
+	monitors := &cephv1beta1.CephMonList{}
+	listOptions := &client.ListOptions{Namespace: instance.GetNamespace()}
+	listOptions.MatchingLabels(map[string]string{cephv1beta1.ClusterNameLabel: instance.GetName()})
+
+	err := r.client.List(context.TODO(), listOptions, monitors)
+	if err != nil {
+		return nil, err
+	}
+
+	monMap := make(cephv1beta1.MonMap, len(monitors.Items))
+	for _, mon := range monitors.Items {
+		monMap[mon.Spec.ID] = mon.GetMonMapEntry()
+	}
+
+	return monMap, nil
 }
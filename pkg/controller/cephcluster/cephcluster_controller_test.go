@@ -0,0 +1,137 @@
+package cephcluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/apis"
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/ceph"
+	"github.com/go-test/deep"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testNamespace   = "ceph"
+	testClusterName = "test"
+)
+
+func init() {
+	if err := apis.AddToScheme(scheme.Scheme); err != nil {
+		panic(err)
+	}
+}
+
+func newTestReconciler(runner ceph.CommandRunner, objects ...runtime.Object) *ReconcileCephCluster {
+	return &ReconcileCephCluster{client: fake.NewFakeClient(objects...), scheme: scheme.Scheme, runner: runner}
+}
+
+func newTestCluster(state cephv1beta1.CephClusterState) *cephv1beta1.CephCluster {
+	cluster := &cephv1beta1.CephCluster{}
+	cluster.Name = testClusterName
+	cluster.Namespace = testNamespace
+	cluster.Spec.Fsid = "3f6d3ee2-9cbb-4e3b-9a3c-0b9cd0a8b5e1"
+	cluster.Spec.MonServiceName = "test-mon"
+	cluster.Status.State = state
+	return cluster
+}
+
+func TestCreateDaemonClusterSyncsNetwork(t *testing.T) {
+	cluster := newTestCluster(cephv1beta1.CephClusterRunning)
+	cluster.Spec.Network = cephv1beta1.NetworkSpec{Provider: cephv1beta1.NetworkProviderHost, PublicNetwork: "10.0.0.0/24"}
+
+	existing := cephv1beta1.NewCephDaemonCluster(cephv1beta1.CephDaemonTypeMgr)
+	existing.Name = testClusterName + "-mgr"
+	existing.Namespace = testNamespace
+
+	r := newTestReconciler(&ceph.FakeRunner{}, cluster, existing)
+
+	err := r.createDaemonCluster(cephv1beta1.NewCephDaemonCluster(cephv1beta1.CephDaemonTypeMgr), cluster)
+	if err != nil {
+		t.Fatalf("unable to create daemon cluster: %v", err)
+	}
+
+	daemonCluster := &cephv1beta1.CephDaemonCluster{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: existing.Name}, daemonCluster)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if daemonCluster.Spec.Network != cluster.Spec.Network {
+		t.Errorf("expected the existing daemon cluster's network to be updated, got %v", daemonCluster.Spec.Network)
+	}
+}
+
+func TestEnsureMonitorServiceRelabelsMonitorPods(t *testing.T) {
+	cluster := newTestCluster(cephv1beta1.CephClusterRunning)
+
+	existing := cluster.GetMonitorService()
+	existing.Namespace = testNamespace
+	existing.Spec.Selector = map[string]string{cephv1beta1.MonitorServiceLabel: ""}
+
+	// Monitors and their pods as the baseline operator created them, the pods only carry the monitor
+	// service label and are tied to a cluster through their CephMon
+	mon := &cephv1beta1.CephMon{}
+	mon.Name = "mon-a"
+	mon.Namespace = testNamespace
+	mon.UID = "mon-a-uid"
+	mon.Spec.ClusterName = testClusterName
+
+	otherMon := mon.DeepCopy()
+	otherMon.Name = "other-mon-a"
+	otherMon.UID = "other-mon-a-uid"
+	otherMon.Spec.ClusterName = "other"
+
+	monPod := &corev1.Pod{}
+	monPod.Name = "ceph-mon-a"
+	monPod.Namespace = testNamespace
+	monPod.Labels = map[string]string{cephv1beta1.MonitorServiceLabel: ""}
+	monPod.OwnerReferences = []metav1.OwnerReference{{APIVersion: "ceph.k8s.pgc.umn.edu/v1alpha1", Kind: "CephMon", Name: mon.Name, UID: mon.UID}}
+
+	otherPod := monPod.DeepCopy()
+	otherPod.Name = "ceph-other-mon-a"
+	otherPod.OwnerReferences = []metav1.OwnerReference{{APIVersion: "ceph.k8s.pgc.umn.edu/v1alpha1", Kind: "CephMon", Name: otherMon.Name, UID: otherMon.UID}}
+
+	r := newTestReconciler(&ceph.FakeRunner{}, cluster, existing, mon, otherMon, monPod, otherPod)
+
+	err := r.ensureMonitorService(cluster)
+	if err != nil {
+		t.Fatalf("unable to ensure monitor service: %v", err)
+	}
+
+	svc := &corev1.Service{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: existing.Name}, svc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal(svc.Spec.Selector, cluster.GetMonitorServiceSelector()); diff != nil {
+		t.Errorf("unexpected service selector: %v", diff)
+	}
+
+	pod := &corev1.Pod{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: monPod.Name}, pod)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal(pod.Labels, map[string]string{
+		cephv1beta1.MonitorServiceLabel: testClusterName,
+		cephv1beta1.ClusterNameLabel:    testClusterName,
+		cephv1beta1.DaemonTypeLabel:     cephv1beta1.CephDaemonTypeMon.String(),
+	}); diff != nil {
+		t.Errorf("expected the monitor pod to be relabelled before the selector changed: %v", diff)
+	}
+
+	pod = &corev1.Pod{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: otherPod.Name}, pod)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal(pod.Labels, otherPod.Labels); diff != nil {
+		t.Errorf("expected another cluster's monitor pod to be left alone, got %v", pod.Labels)
+	}
+}
@@ -0,0 +1,193 @@
+package cephcluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/ceph"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// osdDisruptionChecks are health checks that block the disruption of another failure domain
+var osdDisruptionChecks = []string{"OSD_DOWN", "PG_AVAILABILITY", "PG_DEGRADED"}
+
+// ensureDisruptionBudgets creates the PodDisruptionBudgets of the cluster's daemons and removes budgets that are
+// no longer needed.  Budgets are re-evaluated as osd pods change and as polled ceph health is recorded.
+func (r *ReconcileCephCluster) ensureDisruptionBudgets(instance *cephv1beta1.CephCluster) error {
+	desired := map[string]*policyv1beta1.PodDisruptionBudget{}
+
+	if !instance.Spec.DisableDisruptionBudgets {
+		monMap, err := r.getMonMap(instance)
+		if err != nil {
+			return err
+		}
+
+		budgets := []*policyv1beta1.PodDisruptionBudget{instance.GetMonDisruptionBudget(monMap)}
+
+		for _, daemonType := range []cephv1beta1.CephDaemonType{cephv1beta1.CephDaemonTypeMgr, cephv1beta1.CephDaemonTypeMds} {
+			daemonCluster := &cephv1beta1.CephDaemonCluster{}
+			err = r.client.Get(context.TODO(), types.NamespacedName{
+				Namespace: instance.GetNamespace(),
+				Name:      fmt.Sprintf("%s-%s", instance.GetName(), daemonType),
+			}, daemonCluster)
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+
+			if pdb := instance.GetDaemonDisruptionBudget(daemonType, daemonCluster.Spec.Replicas); pdb != nil {
+				budgets = append(budgets, pdb)
+			}
+		}
+
+		osdBudgets, err := r.getOsdDisruptionBudgets(instance)
+		if err != nil {
+			return err
+		}
+		budgets = append(budgets, osdBudgets...)
+
+		for _, pdb := range budgets {
+			desired[pdb.GetName()] = pdb
+		}
+	}
+
+	existing := &policyv1beta1.PodDisruptionBudgetList{}
+	listOptions := &client.ListOptions{Namespace: instance.GetNamespace()}
+	listOptions.MatchingLabels(map[string]string{cephv1beta1.ClusterNameLabel: instance.GetName()})
+	err := r.client.List(context.TODO(), listOptions, existing)
+	if err != nil {
+		return err
+	}
+
+	for i := range existing.Items {
+		pdb := &existing.Items[i]
+		if _, ok := desired[pdb.GetName()]; ok {
+			continue
+		}
+
+		log.Info("Removing disruption budget", "PodDisruptionBudget", pdb.GetName())
+		err = r.client.Delete(context.TODO(), pdb)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	for _, pdb := range desired {
+		err = r.ensureDisruptionBudget(instance, pdb)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ensureDisruptionBudget creates the budget, or updates the number of pods it allows to be evicted
+func (r *ReconcileCephCluster) ensureDisruptionBudget(instance *cephv1beta1.CephCluster, pdb *policyv1beta1.PodDisruptionBudget) error {
+	if err := controllerutil.SetControllerReference(instance, pdb, r.scheme); err != nil {
+		return err
+	}
+
+	existing := &policyv1beta1.PodDisruptionBudget{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: pdb.Namespace, Name: pdb.Name}, existing)
+	if errors.IsNotFound(err) {
+		return r.createIfNotFound(pdb)
+	}
+	if err != nil {
+		return err
+	}
+
+	if existing.Spec.MaxUnavailable != nil && *existing.Spec.MaxUnavailable == *pdb.Spec.MaxUnavailable {
+		return nil
+	}
+
+	log.Info("Updating disruption budget", "PodDisruptionBudget", pdb.GetName(), "MaxUnavailable", pdb.Spec.MaxUnavailable.String())
+	existing.Spec.MaxUnavailable = pdb.Spec.MaxUnavailable
+	return r.updateObject(existing)
+}
+
+// getOsdDisruptionBudgets returns the budgets of the cluster's osds.  A failure domain is disrupted while one of
+// its osds isn't ready.
+func (r *ReconcileCephCluster) getOsdDisruptionBudgets(instance *cephv1beta1.CephCluster) ([]*policyv1beta1.PodDisruptionBudget, error) {
+	osds := &cephv1beta1.CephOsdList{}
+	listOptions := &client.ListOptions{Namespace: instance.GetNamespace()}
+	listOptions.MatchingLabels(map[string]string{
+		cephv1beta1.ClusterNameLabel: instance.GetName(),
+		cephv1beta1.DaemonTypeLabel:  cephv1beta1.CephDaemonTypeOsd.String(),
+	})
+	err := r.client.List(context.TODO(), listOptions, osds)
+	if err != nil {
+		return nil, err
+	}
+
+	domains := map[string]int{}
+	disrupted := map[string]bool{}
+	for _, osd := range osds.Items {
+		domain := osd.Status.FailureDomain
+		if domain == "" || osd.GetDisabled() {
+			continue
+		}
+		domains[domain]++
+
+		pod := &corev1.Pod{}
+		err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: osd.GetNamespace(), Name: osd.GetPodName()}, pod)
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, err
+		}
+		if errors.IsNotFound(err) || pod.GetDeletionTimestamp() != nil || !podReady(pod) {
+			disrupted[domain] = true
+		}
+	}
+
+	return instance.GetOsdDisruptionBudgets(domains, disrupted, osdsHealthy(instance, time.Now())), nil
+}
+
+// osdsHealthy returns true if the cluster is running and the last ceph health poll, made within two poll
+// intervals, reported no down osds or degraded placement groups
+func osdsHealthy(instance *cephv1beta1.CephCluster, now time.Time) bool {
+	health := instance.Status.Health
+	if instance.GetState() != cephv1beta1.CephClusterRunning || health == nil || health.Error != "" ||
+		health.LastUpdated == nil {
+		return false
+	}
+
+	if now.Sub(health.LastUpdated.Time) > 2*instance.GetHealthCheckInterval() {
+		return false
+	}
+
+	return !health.HasCheck(osdDisruptionChecks...)
+}
+
+// getAdmin returns an admin interface running in the pod of an in quorum monitor
+func (r *ReconcileCephCluster) getAdmin(instance *cephv1beta1.CephCluster, monMap cephv1beta1.MonMap) (*ceph.Admin, error) {
+	for _, entry := range monMap {
+		if entry.State != cephv1beta1.MonInQuorum {
+			continue
+		}
+
+		mon := &cephv1beta1.CephMon{}
+		err := r.client.Get(context.TODO(), entry.NamespacedName, mon)
+		if err != nil {
+			return nil, err
+		}
+
+		return ceph.NewAdmin(r.runner, mon.GetNamespace(), mon.GetPodName(), instance.GetName()), nil
+	}
+
+	return nil, fmt.Errorf("no monitor in quorum to run admin commands")
+}
+
+func podReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package cephcluster
+
+import (
+	"testing"
+	"time"
+
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestOsdsHealthy(t *testing.T) {
+	now := time.Now()
+
+	cases := []struct {
+		Name     string
+		State    cephv1beta1.CephClusterState
+		Health   *cephv1beta1.CephClusterHealth
+		Expected bool
+	}{
+		{
+			Name:     "healthy",
+			State:    cephv1beta1.CephClusterRunning,
+			Health:   &cephv1beta1.CephClusterHealth{Status: "HEALTH_OK", LastUpdated: &metav1.Time{Time: now}},
+			Expected: true,
+		},
+		{
+			Name:  "degraded",
+			State: cephv1beta1.CephClusterRunning,
+			Health: &cephv1beta1.CephClusterHealth{
+				Status:      "HEALTH_WARN",
+				Checks:      []cephv1beta1.CephHealthCheck{{Name: "PG_DEGRADED"}},
+				LastUpdated: &metav1.Time{Time: now},
+			},
+		},
+		{
+			Name:  "unrelated-warning",
+			State: cephv1beta1.CephClusterRunning,
+			Health: &cephv1beta1.CephClusterHealth{
+				Status:      "HEALTH_WARN",
+				Checks:      []cephv1beta1.CephHealthCheck{{Name: "POOL_NO_REDUNDANCY"}},
+				LastUpdated: &metav1.Time{Time: now},
+			},
+			Expected: true,
+		},
+		{
+			Name:   "stale",
+			State:  cephv1beta1.CephClusterRunning,
+			Health: &cephv1beta1.CephClusterHealth{Status: "HEALTH_OK", LastUpdated: &metav1.Time{Time: now.Add(-time.Hour)}},
+		},
+		{
+			Name:   "poll-failed",
+			State:  cephv1beta1.CephClusterRunning,
+			Health: &cephv1beta1.CephClusterHealth{Error: "timed out", LastUpdated: &metav1.Time{Time: now}},
+		},
+		{
+			Name:  "not-polled",
+			State: cephv1beta1.CephClusterRunning,
+		},
+		{
+			Name:   "stopping",
+			State:  cephv1beta1.CephClusterStopOsds,
+			Health: &cephv1beta1.CephClusterHealth{Status: "HEALTH_OK", LastUpdated: &metav1.Time{Time: now}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			cluster := newTestCluster(c.State)
+			cluster.Status.Health = c.Health
+
+			if healthy := osdsHealthy(cluster, now); healthy != c.Expected {
+				t.Errorf("expected healthy to be %t, got %t", c.Expected, healthy)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package cephcluster
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// syncExternalKeyring copies the client keyring of an external cluster into a keyring secret labeled like the
+// ones generated for clusters run by the operator
+func (r *ReconcileCephCluster) syncExternalKeyring(instance *cephv1beta1.CephCluster) error {
+	external := instance.GetExternalCluster()
+
+	source := &corev1.Secret{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: instance.GetNamespace(), Name: external.ClientKeyringSecretName}, source)
+	if err != nil {
+		return fmt.Errorf("unable to read external client keyring: %v", err)
+	}
+
+	secret := &corev1.Secret{}
+	secret.Name = instance.GetExternalKeyringSecretName()
+	secret.Namespace = instance.GetNamespace()
+	secret.SetLabels(map[string]string{
+		cephv1beta1.ClusterNameLabel:   instance.GetName(),
+		cephv1beta1.KeyringEntityLabel: external.GetClientName(),
+		cephv1beta1.FsidLabel:          instance.Spec.Fsid,
+	})
+	secret.Data = map[string][]byte{"keyring": source.Data["keyring"]}
+
+	existing := &corev1.Secret{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}, existing)
+	if errors.IsNotFound(err) {
+		return r.createIfNotFound(secret)
+	}
+	if err != nil {
+		return err
+	}
+
+	if reflect.DeepEqual(existing.Data, secret.Data) && reflect.DeepEqual(existing.GetLabels(), secret.GetLabels()) {
+		return nil
+	}
+
+	existing.Data = secret.Data
+	existing.SetLabels(secret.GetLabels())
+	return r.updateObject(existing)
+}
@@ -0,0 +1,128 @@
+package cephcluster
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/ceph"
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/controller/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// clientPodRequeueInterval is how often the health of an external cluster is checked while its client pod starts
+const clientPodRequeueInterval = 10 * time.Second
+
+var errClientPodStarting = errors.New("the client pod isn't running yet")
+
+// pollHealth records the health reported by ceph in the cluster status once the health check interval has
+// passed.  Returns the time until the next poll.
+func (r *ReconcileCephCluster) pollHealth(instance *cephv1beta1.CephCluster) (time.Duration, error) {
+	if instance.GetState() == cephv1beta1.CephClusterIdle {
+		return 0, nil
+	}
+
+	now := time.Now()
+	if due := instance.HealthCheckDue(now); due > 0 {
+		return due, nil
+	}
+
+	health := &cephv1beta1.CephClusterHealth{}
+	if instance.Status.Health != nil {
+		health = instance.Status.Health.DeepCopy()
+	}
+
+	status, detail, err := r.getCephStatus(instance)
+	if err == errClientPodStarting {
+		return clientPodRequeueInterval, nil
+	}
+	if err != nil {
+		health.Error = err.Error()
+	} else {
+		health = summarizeHealth(status, detail)
+	}
+	health.LastUpdated = &metav1.Time{Time: now}
+
+	instance.Status.Health = health
+	return instance.GetHealthCheckInterval(), r.updateObject(instance)
+}
+
+// getCephStatus runs ceph status and ceph health detail in a monitor pod, or in the client pod of an external
+// cluster
+func (r *ReconcileCephCluster) getCephStatus(instance *cephv1beta1.CephCluster) (*ceph.Status, *ceph.Health, error) {
+	admin, err := r.getHealthAdmin(instance)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	status, err := admin.Status()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	detail, err := admin.Health()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return status, detail, nil
+}
+
+// getHealthAdmin returns an admin interface running in a monitor pod.  External clusters have no monitor pods,
+// their health is checked from a client pod with the external client keyring and monitor addresses.
+func (r *ReconcileCephCluster) getHealthAdmin(instance *cephv1beta1.CephCluster) (*ceph.Admin, error) {
+	if instance.Spec.External {
+		admin, err := common.GetClientAdmin(r.client, r.scheme, r.runner, instance, instance.GetExternalKeyringSecretName(),
+			instance.GetExternalCluster().GetClientName())
+		if err == nil && admin == nil {
+			return nil, errClientPodStarting
+		}
+		return admin, err
+	}
+
+	monMap, err := r.getMonMap(instance)
+	if err != nil {
+		return nil, err
+	}
+	return r.getAdmin(instance, monMap)
+}
+
+// summarizeHealth converts ceph status and ceph health detail to the health recorded in the cluster status
+func summarizeHealth(status *ceph.Status, detail *ceph.Health) *cephv1beta1.CephClusterHealth {
+	health := &cephv1beta1.CephClusterHealth{
+		Status: detail.Status,
+		Capacity: cephv1beta1.CephCapacity{
+			TotalBytes:     status.PgMap.BytesTotal,
+			UsedBytes:      status.PgMap.BytesUsed,
+			AvailableBytes: status.PgMap.BytesAvail,
+		},
+		Pgs:           status.PgMap.NumPgs,
+		QuorumMembers: status.QuorumNames,
+	}
+
+	for name, check := range detail.Checks {
+		health.Checks = append(health.Checks, cephv1beta1.CephHealthCheck{
+			Name:     name,
+			Severity: check.Severity,
+			Message:  check.Summary.Message,
+		})
+	}
+	sort.Slice(health.Checks, func(i, j int) bool { return health.Checks[i].Name < health.Checks[j].Name })
+
+	if len(status.PgMap.PgsByState) > 0 {
+		health.PgStates = make(map[string]int, len(status.PgMap.PgsByState))
+		for _, state := range status.PgMap.PgsByState {
+			health.PgStates[state.StateName] = state.Count
+		}
+	}
+
+	counts := status.GetOsdCounts()
+	health.Osds = cephv1beta1.CephOsdCounts{
+		Total: counts.NumOsds,
+		Up:    counts.NumUpOsds,
+		In:    counts.NumInOsds,
+	}
+
+	return health
+}
@@ -0,0 +1,198 @@
+package cephcluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/ceph"
+	"github.com/go-test/deep"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	nautilusStatus = `{
+		"health": {"status": "HEALTH_WARN"},
+		"quorum_names": ["a", "b", "c"],
+		"osdmap": {"osdmap": {"num_osds": 3, "num_up_osds": 2, "num_in_osds": 3}},
+		"pgmap": {
+			"pgs_by_state": [{"state_name": "active+clean", "count": 60}, {"state_name": "active+undersized+degraded", "count": 4}],
+			"num_pgs": 64,
+			"bytes_used": 300,
+			"bytes_avail": 700,
+			"bytes_total": 1000
+		}
+	}`
+	octopusStatus = `{
+		"health": {"status": "HEALTH_OK"},
+		"quorum_names": ["a"],
+		"osdmap": {"num_osds": 1, "num_up_osds": 1, "num_in_osds": 1},
+		"pgmap": {"num_pgs": 0}
+	}`
+	warnDetail = `{
+		"status": "HEALTH_WARN",
+		"checks": {
+			"PG_DEGRADED": {"severity": "HEALTH_WARN", "summary": {"message": "Degraded data redundancy: 4 pgs degraded"}},
+			"OSD_DOWN": {"severity": "HEALTH_WARN", "summary": {"message": "1 osds down"}, "detail": [{"message": "osd.2 is down"}]}
+		}
+	}`
+)
+
+func TestSummarizeHealth(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Status   string
+		Detail   string
+		Expected *cephv1beta1.CephClusterHealth
+	}{
+		{
+			Name:   "nautilus",
+			Status: nautilusStatus,
+			Detail: warnDetail,
+			Expected: &cephv1beta1.CephClusterHealth{
+				Status: "HEALTH_WARN",
+				Checks: []cephv1beta1.CephHealthCheck{
+					{Name: "OSD_DOWN", Severity: "HEALTH_WARN", Message: "1 osds down"},
+					{Name: "PG_DEGRADED", Severity: "HEALTH_WARN", Message: "Degraded data redundancy: 4 pgs degraded"},
+				},
+				Capacity:      cephv1beta1.CephCapacity{TotalBytes: 1000, UsedBytes: 300, AvailableBytes: 700},
+				Pgs:           64,
+				PgStates:      map[string]int{"active+clean": 60, "active+undersized+degraded": 4},
+				Osds:          cephv1beta1.CephOsdCounts{Total: 3, Up: 2, In: 3},
+				QuorumMembers: []string{"a", "b", "c"},
+			},
+		},
+		{
+			Name:   "octopus",
+			Status: octopusStatus,
+			Detail: `{"status": "HEALTH_OK", "checks": {}}`,
+			Expected: &cephv1beta1.CephClusterHealth{
+				Status:        "HEALTH_OK",
+				Osds:          cephv1beta1.CephOsdCounts{Total: 1, Up: 1, In: 1},
+				QuorumMembers: []string{"a"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			status := &ceph.Status{}
+			if err := json.Unmarshal([]byte(c.Status), status); err != nil {
+				t.Fatal(err)
+			}
+
+			detail := &ceph.Health{}
+			if err := json.Unmarshal([]byte(c.Detail), detail); err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(summarizeHealth(status, detail), c.Expected); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestRequeueWithin(t *testing.T) {
+	cases := []struct {
+		Result   reconcile.Result
+		Within   time.Duration
+		Expected reconcile.Result
+	}{
+		{Result: reconcile.Result{}, Within: time.Minute, Expected: reconcile.Result{RequeueAfter: time.Minute}},
+		{Result: reconcile.Result{RequeueAfter: time.Second}, Within: time.Minute, Expected: reconcile.Result{RequeueAfter: time.Second}},
+		{Result: reconcile.Result{RequeueAfter: time.Hour}, Within: time.Minute, Expected: reconcile.Result{RequeueAfter: time.Minute}},
+		{Result: reconcile.Result{Requeue: true}, Within: time.Minute, Expected: reconcile.Result{Requeue: true}},
+		{Result: reconcile.Result{}, Within: 0, Expected: reconcile.Result{}},
+	}
+
+	for _, c := range cases {
+		if diff := deep.Equal(requeueWithin(c.Result, c.Within), c.Expected); diff != nil {
+			t.Errorf("%v within %s: %v", c.Result, c.Within, diff)
+		}
+	}
+}
+
+func TestPollExternalHealth(t *testing.T) {
+	cluster := newTestCluster(cephv1beta1.CephClusterRunning)
+	cluster.Spec.External = true
+	cluster.Spec.MonImage = cephv1beta1.ImageSpec{Registry: "ceph/daemon", Tag: "latest-nautilus"}
+	cluster.Spec.ExternalCluster = &cephv1beta1.ExternalClusterSpec{
+		MonHosts:                []string{"192.168.1.10"},
+		ClientKeyringSecretName: "ceph-client-keyring",
+		ClientName:              "client.kubernetes",
+	}
+
+	runner := &ceph.FakeRunner{Outputs: map[string]string{
+		"status --format json":        octopusStatus,
+		"health detail --format json": `{"status": "HEALTH_OK"}`,
+	}}
+	r := newTestReconciler(runner, cluster)
+
+	next, err := r.pollHealth(cluster)
+	if err != nil {
+		t.Fatalf("unable to poll health: %v", err)
+	}
+	if next != clientPodRequeueInterval || cluster.Status.Health != nil {
+		t.Fatalf("expected the poll to wait for the client pod, next poll in %s, health %v", next, cluster.Status.Health)
+	}
+
+	pod := &corev1.Pod{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: cluster.GetClientPodName()}, pod)
+	if err != nil {
+		t.Fatalf("client pod wasn't created: %v", err)
+	}
+	if secret := pod.Spec.Volumes[1].Secret.SecretName; secret != cluster.GetExternalKeyringSecretName() {
+		t.Errorf("client pod mounts keyring %s", secret)
+	}
+
+	pod.Status.Phase = corev1.PodRunning
+	err = r.client.Update(context.TODO(), pod)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = r.pollHealth(cluster)
+	if err != nil {
+		t.Fatalf("unable to poll health: %v", err)
+	}
+	if cluster.Status.Health == nil || cluster.Status.Health.Status != "HEALTH_OK" {
+		t.Errorf("expected the external cluster's health to be recorded, got %v", cluster.Status.Health)
+	}
+	if !runner.Ran("--name client.kubernetes status --format json") {
+		t.Errorf("expected ceph to run as the external client, ran %s", runner)
+	}
+}
+
+func TestPollHealthTimesOut(t *testing.T) {
+	cluster := newTestCluster(cephv1beta1.CephClusterRunning)
+
+	runner := &ceph.FakeRunner{Errors: map[string]error{
+		"status --format json": fmt.Errorf("command timed out after %s", ceph.CommandTimeout),
+	}}
+	r := newTestReconciler(runner, cluster, newTestInQuorumMon("a"))
+
+	_, err := r.pollHealth(cluster)
+	if err != nil {
+		t.Fatalf("unable to poll health: %v", err)
+	}
+
+	if cluster.Status.Health == nil || cluster.Status.Health.Error == "" {
+		t.Errorf("expected the timed out poll to be recorded, got %v", cluster.Status.Health)
+	}
+
+	for i, command := range runner.Commands {
+		if !strings.Contains(command, fmt.Sprintf("--connect-timeout %d", int(ceph.ConnectTimeout.Seconds()))) {
+			t.Errorf("expected ceph to be run with a connect timeout, ran %s", command)
+		}
+		if runner.Timeouts[i] != ceph.CommandTimeout {
+			t.Errorf("expected %s to be run with a %s deadline, got %s", command, ceph.CommandTimeout, runner.Timeouts[i])
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package cephcluster
+
+import (
+	"fmt"
+	"time"
+
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/ceph"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// healthGateRecheckInterval is how often a blocked transition checks its health gate again
+const healthGateRecheckInterval = 30 * time.Second
+
+// healthGateBlocks returns true if an enabled health gate holds the transition to the next state.  The reason is
+// recorded in the cluster status until the gate is satisfied or times out.
+func (r *ReconcileCephCluster) healthGateBlocks(instance *cephv1beta1.CephCluster, nextState cephv1beta1.CephClusterState,
+	reqLogger logr.Logger) (bool, error) {
+
+	gate, ok := cephv1beta1.GetHealthGate(instance.GetState(), nextState)
+	if !ok || !instance.HealthGateEnabled(gate) {
+		return false, nil
+	}
+
+	var reason string
+	status, _, err := r.getCephStatus(instance)
+	if err != nil {
+		reason = fmt.Sprintf("unable to check ceph status: %v", err)
+	} else {
+		reason = healthGateReason(gate, status)
+	}
+	if reason == "" {
+		return false, nil
+	}
+
+	block := instance.Status.TransitionBlocked
+	if block != nil && block.Gate == gate && block.NextState == nextState {
+		if instance.HealthGateTimedOut(block, time.Now()) {
+			reqLogger.Info("health gate timed out, transitioning anyway", "Gate", gate, "Reason", reason)
+			return false, nil
+		}
+		if block.Reason == reason {
+			return true, nil
+		}
+		block = block.DeepCopy()
+	} else {
+		block = &cephv1beta1.CephClusterTransitionBlock{Gate: gate, NextState: nextState, Since: metav1.Now()}
+	}
+
+	reqLogger.Info(fmt.Sprintf("transition to %s blocked by health gate", nextState), "Gate", gate, "Reason", reason)
+	block.Reason = reason
+	instance.Status.TransitionBlocked = block
+	return true, r.updateObject(instance)
+}
+
+// healthGateReason returns why ceph's status doesn't satisfy the gate, or an empty string if it does
+func healthGateReason(gate cephv1beta1.HealthGate, status *ceph.Status) string {
+	switch gate {
+	case cephv1beta1.HealthGateMgrAvailable:
+		if !status.MgrMap.Available {
+			return "no mgr is available"
+		}
+	case cephv1beta1.HealthGatePgsActive:
+		if inactive := status.InactivePgs(); inactive > 0 {
+			return fmt.Sprintf("%d of %d pgs are not active", inactive, status.PgMap.NumPgs)
+		}
+	case cephv1beta1.HealthGateNoDegradedObjects:
+		if status.PgMap.DegradedObjects > 0 {
+			return fmt.Sprintf("%d objects are degraded", status.PgMap.DegradedObjects)
+		}
+	}
+	return ""
+}
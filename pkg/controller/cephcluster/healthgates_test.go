@@ -0,0 +1,162 @@
+package cephcluster
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/ceph"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const recoveringStatus = `{
+	"health": {"status": "HEALTH_WARN"},
+	"mgrmap": {"available": false},
+	"pgmap": {
+		"pgs_by_state": [{"state_name": "active+clean", "count": 50}, {"state_name": "peering", "count": 10}, {"state_name": "unknown", "count": 4}],
+		"num_pgs": 64,
+		"degraded_objects": 12
+	}
+}`
+
+func TestHealthGateReason(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Gate     cephv1beta1.HealthGate
+		Status   string
+		Expected string
+	}{
+		{Name: "mgr unavailable", Gate: cephv1beta1.HealthGateMgrAvailable, Status: recoveringStatus, Expected: "no mgr is available"},
+		{Name: "mgr available", Gate: cephv1beta1.HealthGateMgrAvailable, Status: `{"mgrmap": {"available": true}}`},
+		{Name: "pgs inactive", Gate: cephv1beta1.HealthGatePgsActive, Status: recoveringStatus, Expected: "14 of 64 pgs are not active"},
+		{Name: "degraded pgs active", Gate: cephv1beta1.HealthGatePgsActive, Status: nautilusStatus},
+		{Name: "no pgs", Gate: cephv1beta1.HealthGatePgsActive, Status: octopusStatus},
+		{Name: "degraded objects", Gate: cephv1beta1.HealthGateNoDegradedObjects, Status: recoveringStatus, Expected: "12 objects are degraded"},
+		{Name: "no degraded objects", Gate: cephv1beta1.HealthGateNoDegradedObjects, Status: nautilusStatus},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			status := &ceph.Status{}
+			if err := json.Unmarshal([]byte(c.Status), status); err != nil {
+				t.Fatalf("unable to parse status: %v", err)
+			}
+
+			if reason := healthGateReason(c.Gate, status); reason != c.Expected {
+				t.Errorf("expected %q, got %q", c.Expected, reason)
+			}
+		})
+	}
+}
+
+// newGatedShutdownTest returns a cluster shutting down with the no degraded objects gate enabled and a
+// reconciler whose monitor reports the status
+func newGatedShutdownTest(status string) (*cephv1beta1.CephCluster, *ReconcileCephCluster, *ceph.FakeRunner) {
+	cluster := newTestCluster(cephv1beta1.CephClusterShutdown)
+	cluster.Spec.HealthGates.NoDegradedObjects.Enabled = true
+
+	runner := &ceph.FakeRunner{Outputs: map[string]string{
+		"status --format json":        status,
+		"health detail --format json": `{"status": "HEALTH_WARN"}`,
+	}}
+	return cluster, newTestReconciler(runner, cluster, newTestInQuorumMon("a")), runner
+}
+
+func TestTransitionBlockedByHealthGate(t *testing.T) {
+	cluster, r, runner := newGatedShutdownTest(recoveringStatus)
+
+	result, err := r.transition(cluster, log)
+	if err != nil {
+		t.Fatalf("unable to transition: %v", err)
+	}
+	if result.RequeueAfter != healthGateRecheckInterval {
+		t.Errorf("expected the gate to be rechecked after %s, got %v", healthGateRecheckInterval, result)
+	}
+	if cluster.GetState() != cephv1beta1.CephClusterShutdown {
+		t.Errorf("expected the gate to hold the cluster in %s, got %s", cephv1beta1.CephClusterShutdown, cluster.GetState())
+	}
+
+	block := cluster.Status.TransitionBlocked
+	if block == nil || block.Gate != cephv1beta1.HealthGateNoDegradedObjects ||
+		block.NextState != cephv1beta1.CephClusterStopDaemons || block.Reason != "12 objects are degraded" {
+		t.Fatalf("unexpected transition block %v", block)
+	}
+	since := block.Since
+
+	// The block keeps the time it started as the reason changes
+	runner.Outputs["status --format json"] = strings.Replace(recoveringStatus, `"degraded_objects": 12`, `"degraded_objects": 3`, 1)
+	_, err = r.transition(cluster, log)
+	if err != nil {
+		t.Fatalf("unable to transition: %v", err)
+	}
+	block = cluster.Status.TransitionBlocked
+	if block == nil || block.Reason != "3 objects are degraded" || !block.Since.Equal(&since) {
+		t.Errorf("expected the reason to be updated since %v, got %v", since, block)
+	}
+
+	// Once ceph has recovered the transition proceeds and the block is cleared
+	runner.Outputs["status --format json"] = nautilusStatus
+	_, err = r.transition(cluster, log)
+	if err != nil {
+		t.Fatalf("unable to transition: %v", err)
+	}
+	if cluster.GetState() != cephv1beta1.CephClusterStopDaemons || cluster.Status.TransitionBlocked != nil {
+		t.Errorf("expected the cluster to stop daemons with no block, got %s and %v", cluster.GetState(), cluster.Status.TransitionBlocked)
+	}
+}
+
+func TestHealthGateTimeoutTransitions(t *testing.T) {
+	cluster, r, _ := newGatedShutdownTest(recoveringStatus)
+	cluster.Status.TransitionBlocked = &cephv1beta1.CephClusterTransitionBlock{
+		Gate:      cephv1beta1.HealthGateNoDegradedObjects,
+		NextState: cephv1beta1.CephClusterStopDaemons,
+		Reason:    "12 objects are degraded",
+		Since:     metav1.NewTime(time.Now().Add(-cephv1beta1.DefaultHealthGateTimeout - time.Minute)),
+	}
+
+	_, err := r.transition(cluster, log)
+	if err != nil {
+		t.Fatalf("unable to transition: %v", err)
+	}
+	if cluster.GetState() != cephv1beta1.CephClusterStopDaemons || cluster.Status.TransitionBlocked != nil {
+		t.Errorf("expected the timed out gate to let the cluster stop daemons, got %s and %v", cluster.GetState(),
+			cluster.Status.TransitionBlocked)
+	}
+}
+
+func TestSkipHealthGatesAnnotation(t *testing.T) {
+	cluster, r, runner := newGatedShutdownTest(recoveringStatus)
+	cluster.SetAnnotations(map[string]string{cephv1beta1.SkipHealthGatesAnnotation: "true"})
+
+	_, err := r.transition(cluster, log)
+	if err != nil {
+		t.Fatalf("unable to transition: %v", err)
+	}
+	if cluster.GetState() != cephv1beta1.CephClusterStopDaemons {
+		t.Errorf("expected the skipped gate to let the cluster stop daemons, got %s", cluster.GetState())
+	}
+	if len(runner.Commands) != 0 {
+		t.Errorf("expected skipped gates not to check ceph, ran %s", runner)
+	}
+}
+
+func TestTransitionClearsStaleBlock(t *testing.T) {
+	cluster := newTestCluster(cephv1beta1.CephClusterRunning)
+	cluster.Status.TransitionBlocked = &cephv1beta1.CephClusterTransitionBlock{
+		Gate:      cephv1beta1.HealthGatePgsActive,
+		NextState: cephv1beta1.CephClusterRunning,
+		Reason:    "4 of 64 pgs are not active",
+		Since:     metav1.Now(),
+	}
+	r := newTestReconciler(&ceph.FakeRunner{}, cluster)
+
+	_, err := r.transition(cluster, log)
+	if err != nil {
+		t.Fatalf("unable to transition: %v", err)
+	}
+	if cluster.GetState() != cephv1beta1.CephClusterRunning || cluster.Status.TransitionBlocked != nil {
+		t.Errorf("expected the block to be cleared while running, got %s and %v", cluster.GetState(), cluster.Status.TransitionBlocked)
+	}
+}
@@ -0,0 +1,247 @@
+package cephcluster
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/ceph"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// syncMaintenance puts nodes annotated for maintenance into maintenance and takes nodes out of it once their
+// annotation is removed.  Entering maintenance sets noout for the node's crush host and fails over the mgr and mds
+// daemons active on the node, recording the node in the status then stops its osds.  Leaving maintenance unsets
+// noout and the osds are started again.  If ceph can't be reached or a command fails the failure is recorded in the
+// status and the rest of the cluster is reconciled, maintenance is retried on the next reconcile.
+func (r *ReconcileCephCluster) syncMaintenance(instance *cephv1beta1.CephCluster) error {
+	osdNodes, daemonNodes, err := r.getDaemonNodes(instance)
+	if err != nil {
+		return err
+	}
+
+	annotated, err := r.getAnnotatedMaintenanceNodes(instance)
+	if err != nil {
+		return err
+	}
+
+	wanted := []string{}
+	for _, node := range annotated {
+		if osdNodes[node] || daemonNodes[node] != nil {
+			wanted = append(wanted, node)
+		}
+	}
+	sort.Strings(wanted)
+
+	if len(wanted) == len(instance.Status.MaintenanceNodes) &&
+		(len(wanted) == 0 || reflect.DeepEqual(wanted, instance.Status.MaintenanceNodes)) {
+		return r.setMaintenanceStatus(instance, instance.Status.MaintenanceNodes, nil)
+	}
+
+	monMap, err := r.getMonMap(instance)
+	if err != nil {
+		return err
+	}
+
+	admin, err := r.getAdmin(instance, monMap)
+	if err != nil {
+		return r.setMaintenanceStatus(instance, instance.Status.MaintenanceNodes,
+			[]string{fmt.Sprintf("unable to run admin commands: %v", err)})
+	}
+
+	// A node that fails to enter maintenance is left out of the status so its osds keep running, a node that
+	// fails to leave it stays in the status.  Both are retried on the next reconcile.
+	nodes := []string{}
+	failures := []string{}
+	for _, node := range wanted {
+		if instance.InMaintenance(node) {
+			nodes = append(nodes, node)
+			continue
+		}
+
+		log.Info("Putting node into maintenance", "Node", node)
+		err = enterMaintenance(admin, node, osdNodes[node], daemonNodes[node])
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("unable to put %s into maintenance: %v", node, err))
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+
+	inWanted := make(map[string]bool, len(wanted))
+	for _, node := range wanted {
+		inWanted[node] = true
+	}
+
+	for _, node := range instance.Status.MaintenanceNodes {
+		if inWanted[node] {
+			continue
+		}
+
+		log.Info("Taking node out of maintenance", "Node", node)
+		if osdNodes[node] {
+			err = admin.UnsetNoOut(node)
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("unable to take %s out of maintenance: %v", node, err))
+				nodes = append(nodes, node)
+			}
+		}
+	}
+	sort.Strings(nodes)
+
+	return r.setMaintenanceStatus(instance, nodes, failures)
+}
+
+// setMaintenanceStatus records the nodes in maintenance and the failures syncing them, the rest of the cluster is
+// reconciled either way
+func (r *ReconcileCephCluster) setMaintenanceStatus(instance *cephv1beta1.CephCluster, nodes []string, failures []string) error {
+	reason := strings.Join(failures, "; ")
+	if reason != "" {
+		log.Info("Unable to sync node maintenance", "Reason", reason)
+	}
+
+	if reason == instance.Status.MaintenanceError && len(nodes) == len(instance.Status.MaintenanceNodes) &&
+		(len(nodes) == 0 || reflect.DeepEqual(nodes, instance.Status.MaintenanceNodes)) {
+		return nil
+	}
+
+	instance.Status.MaintenanceNodes = nodes
+	instance.Status.MaintenanceError = reason
+	return r.updateObject(instance)
+}
+
+// enterMaintenance sets noout for the node's crush host if it runs osds and fails over its active daemons
+func enterMaintenance(admin *ceph.Admin, node string, osdNode bool, ids map[cephv1beta1.CephDaemonType]map[string]bool) error {
+	if osdNode {
+		err := admin.SetNoOut(node)
+		if err != nil {
+			return err
+		}
+	}
+
+	return failActiveDaemons(admin, ids)
+}
+
+// failActiveDaemons fails over the active mgr and mds daemons with the given daemon ids
+func failActiveDaemons(admin *ceph.Admin, ids map[cephv1beta1.CephDaemonType]map[string]bool) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	activeMgr, err := admin.ActiveMgr()
+	if err != nil {
+		return err
+	}
+	if ids[cephv1beta1.CephDaemonTypeMgr][activeMgr] {
+		log.Info("Failing over active mgr", "Mgr", activeMgr)
+		err = admin.FailMgr(activeMgr)
+		if err != nil {
+			return err
+		}
+	}
+
+	activeMds, err := admin.ActiveMds()
+	if err != nil {
+		return err
+	}
+	for _, mds := range activeMds {
+		if !ids[cephv1beta1.CephDaemonTypeMds][mds] {
+			continue
+		}
+
+		log.Info("Failing over active mds", "Mds", mds)
+		err = admin.FailMds(mds)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getAnnotatedMaintenanceNodes returns the nodes with the maintenance annotation and the nodes listed in the
+// cluster's maintenance annotation
+func (r *ReconcileCephCluster) getAnnotatedMaintenanceNodes(instance *cephv1beta1.CephCluster) ([]string, error) {
+	nodes := &corev1.NodeList{}
+	err := r.client.List(context.TODO(), &client.ListOptions{}, nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	annotated := instance.GetAnnotatedMaintenanceNodes()
+	for _, node := range nodes.Items {
+		if cephv1beta1.NodeInMaintenance(node.GetAnnotations()) {
+			annotated = append(annotated, node.GetName())
+		}
+	}
+	return annotated, nil
+}
+
+// getDaemonNodes returns the nodes running the cluster's osds, and the ids of the mgr and mds daemons running on
+// each node
+func (r *ReconcileCephCluster) getDaemonNodes(instance *cephv1beta1.CephCluster) (map[string]bool,
+	map[string]map[cephv1beta1.CephDaemonType]map[string]bool, error) {
+
+	osds := &cephv1beta1.CephOsdList{}
+	listOptions := &client.ListOptions{Namespace: instance.GetNamespace()}
+	listOptions.MatchingLabels(map[string]string{
+		cephv1beta1.ClusterNameLabel: instance.GetName(),
+		cephv1beta1.DaemonTypeLabel:  cephv1beta1.CephDaemonTypeOsd.String(),
+	})
+	err := r.client.List(context.TODO(), listOptions, osds)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	osdNodes := map[string]bool{}
+	for _, osd := range osds.Items {
+		if osd.Status.NodeName != "" {
+			osdNodes[osd.Status.NodeName] = true
+		}
+	}
+
+	daemonNodes := map[string]map[cephv1beta1.CephDaemonType]map[string]bool{}
+	for _, daemonType := range []cephv1beta1.CephDaemonType{cephv1beta1.CephDaemonTypeMgr, cephv1beta1.CephDaemonTypeMds} {
+		daemons := &cephv1beta1.CephDaemonList{}
+		listOptions := &client.ListOptions{Namespace: instance.GetNamespace()}
+		listOptions.MatchingLabels(map[string]string{
+			cephv1beta1.ClusterNameLabel: instance.GetName(),
+			cephv1beta1.DaemonTypeLabel:  daemonType.String(),
+		})
+		err = r.client.List(context.TODO(), listOptions, daemons)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, daemon := range daemons.Items {
+			pod := &corev1.Pod{}
+			err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: daemon.GetNamespace(), Name: daemon.GetPodName()}, pod)
+			if errors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+
+			node := pod.Spec.NodeName
+			if node == "" {
+				continue
+			}
+			if daemonNodes[node] == nil {
+				daemonNodes[node] = map[cephv1beta1.CephDaemonType]map[string]bool{}
+			}
+			if daemonNodes[node][daemonType] == nil {
+				daemonNodes[node][daemonType] = map[string]bool{}
+			}
+			daemonNodes[node][daemonType][daemon.Spec.ID] = true
+		}
+	}
+
+	return osdNodes, daemonNodes, nil
+}
@@ -0,0 +1,140 @@
+package cephcluster
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/ceph"
+	"github.com/go-test/deep"
+)
+
+func newTestMaintenanceOsd(name, nodeName string) *cephv1beta1.CephOsd {
+	osd := &cephv1beta1.CephOsd{}
+	osd.Name = name
+	osd.Namespace = testNamespace
+	osd.Spec.ClusterName = testClusterName
+	osd.Status.NodeName = nodeName
+	osd.SetLabels(map[string]string{
+		cephv1beta1.ClusterNameLabel: testClusterName,
+		cephv1beta1.DaemonTypeLabel:  cephv1beta1.CephDaemonTypeOsd.String(),
+	})
+	return osd
+}
+
+func newTestInQuorumMon(id string) *cephv1beta1.CephMon {
+	mon := &cephv1beta1.CephMon{}
+	mon.Name = testClusterName + "-mon-" + id
+	mon.Namespace = testNamespace
+	mon.Spec.ClusterName = testClusterName
+	mon.Spec.ID = id
+	mon.Status.State = cephv1beta1.MonInQuorum
+	mon.SetLabels(map[string]string{cephv1beta1.ClusterNameLabel: testClusterName})
+	return mon
+}
+
+func TestSyncMaintenanceNoOut(t *testing.T) {
+	cluster := newTestCluster(cephv1beta1.CephClusterRunning)
+	cluster.SetAnnotations(map[string]string{cephv1beta1.MaintenanceAnnotation: "node-a"})
+
+	runner := &ceph.FakeRunner{}
+	r := newTestReconciler(runner, cluster, newTestInQuorumMon("a"),
+		newTestMaintenanceOsd("test-osd-1", "node-a"), newTestMaintenanceOsd("test-osd-2", "node-b"))
+
+	err := r.syncMaintenance(cluster)
+	if err != nil {
+		t.Fatalf("unable to put node into maintenance: %v", err)
+	}
+
+	if !runner.Ran("osd set-group noout node-a") || runner.Ran("noout node-b") {
+		t.Errorf("expected noout to be set only for node-a, ran %s", runner)
+	}
+	if diff := deep.Equal(cluster.Status.MaintenanceNodes, []string{"node-a"}); diff != nil {
+		t.Errorf("unexpected maintenance nodes: %v", diff)
+	}
+	if !cluster.InMaintenance("node-a") || cluster.InMaintenance("node-b") {
+		t.Errorf("expected only node-a's osds to be stopped")
+	}
+
+	runner.Commands = nil
+	cluster.SetAnnotations(nil)
+
+	err = r.syncMaintenance(cluster)
+	if err != nil {
+		t.Fatalf("unable to take node out of maintenance: %v", err)
+	}
+
+	if !runner.Ran("osd unset-group noout node-a") {
+		t.Errorf("expected noout to be unset for node-a, ran %s", runner)
+	}
+	if len(cluster.Status.MaintenanceNodes) != 0 {
+		t.Errorf("expected no maintenance nodes, got %v", cluster.Status.MaintenanceNodes)
+	}
+}
+
+func TestSyncMaintenanceRecordsAdminFailure(t *testing.T) {
+	cluster := newTestCluster(cephv1beta1.CephClusterRunning)
+	cluster.SetAnnotations(map[string]string{cephv1beta1.MaintenanceAnnotation: "node-a"})
+
+	runner := &ceph.FakeRunner{}
+	r := newTestReconciler(runner, cluster, newTestMaintenanceOsd("test-osd-1", "node-a"))
+
+	err := r.syncMaintenance(cluster)
+	if err != nil {
+		t.Fatalf("expected the admin failure to be recorded, got %v", err)
+	}
+
+	if cluster.Status.MaintenanceError == "" {
+		t.Errorf("expected the maintenance error to be recorded")
+	}
+	if len(cluster.Status.MaintenanceNodes) != 0 || len(runner.Commands) != 0 {
+		t.Errorf("expected no node to be put into maintenance, got %v and ran %s", cluster.Status.MaintenanceNodes, runner)
+	}
+
+	cluster.SetAnnotations(nil)
+	err = r.syncMaintenance(cluster)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cluster.Status.MaintenanceError != "" {
+		t.Errorf("expected the maintenance error to be cleared, got %s", cluster.Status.MaintenanceError)
+	}
+}
+
+func TestSyncMaintenanceRecordsCommandFailure(t *testing.T) {
+	cluster := newTestCluster(cephv1beta1.CephClusterRunning)
+	cluster.SetAnnotations(map[string]string{cephv1beta1.MaintenanceAnnotation: "node-a,node-b"})
+
+	runner := &ceph.FakeRunner{Errors: map[string]error{"osd set-group noout node-a": fmt.Errorf("timed out")}}
+	r := newTestReconciler(runner, cluster, newTestInQuorumMon("a"),
+		newTestMaintenanceOsd("test-osd-1", "node-a"), newTestMaintenanceOsd("test-osd-2", "node-b"))
+
+	err := r.syncMaintenance(cluster)
+	if err != nil {
+		t.Fatalf("expected the noout failure to be recorded, got %v", err)
+	}
+
+	if !strings.Contains(cluster.Status.MaintenanceError, "node-a") {
+		t.Errorf("expected the maintenance error to name node-a, got %q", cluster.Status.MaintenanceError)
+	}
+	if diff := deep.Equal(cluster.Status.MaintenanceNodes, []string{"node-b"}); diff != nil {
+		t.Errorf("expected only node-b to enter maintenance: %v", diff)
+	}
+	if cluster.InMaintenance("node-a") {
+		t.Errorf("expected node-a's osds to keep running without noout")
+	}
+
+	runner.Errors = nil
+	err = r.syncMaintenance(cluster)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := deep.Equal(cluster.Status.MaintenanceNodes, []string{"node-a", "node-b"}); diff != nil {
+		t.Errorf("expected node-a to enter maintenance once noout is set: %v", diff)
+	}
+	if cluster.Status.MaintenanceError != "" {
+		t.Errorf("expected the maintenance error to be cleared, got %s", cluster.Status.MaintenanceError)
+	}
+}
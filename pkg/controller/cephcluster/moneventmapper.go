@@ -0,0 +1,28 @@
+package cephcluster
+
+import (
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// MonEventMapper maps monitor events to the ceph cluster so ceph.conf follows monitor membership
+type MonEventMapper struct{}
+
+func (m *MonEventMapper) Map(o handler.MapObject) []reconcile.Request {
+	req := make([]reconcile.Request, 0, 1)
+	switch obj := o.Object.(type) {
+
+	case *cephv1beta1.CephMon:
+		req = append(req, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      obj.Spec.ClusterName,
+				Namespace: obj.Namespace,
+			},
+		})
+	}
+
+	return req
+}
@@ -0,0 +1,53 @@
+package cephcluster
+
+import (
+	"context"
+
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/controller/common"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// NodeEventMapper maps node events to every watched ceph cluster, any of them may run daemons on the node
+type NodeEventMapper struct {
+	Client client.Client
+}
+
+func (m *NodeEventMapper) Map(o handler.MapObject) []reconcile.Request {
+	clusters := &cephv1beta1.CephClusterList{}
+	err := m.Client.List(context.TODO(), &client.ListOptions{}, clusters)
+	if err != nil {
+		log.Error(err, "unable to list ceph clusters for node event", "Node", o.Meta.GetName())
+		return []reconcile.Request{}
+	}
+
+	req := make([]reconcile.Request, 0, len(clusters.Items))
+	for _, cluster := range clusters.Items {
+		if !common.Watched(cluster.GetNamespace()) {
+			continue
+		}
+
+		req = append(req, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      cluster.GetName(),
+				Namespace: cluster.GetNamespace(),
+			},
+		})
+	}
+
+	return req
+}
+
+// maintenanceAnnotationChanged filters node updates to changes of the maintenance annotation, nodes update their
+// status too often to reconcile every cluster on each update
+var maintenanceAnnotationChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return cephv1beta1.NodeInMaintenance(e.MetaOld.GetAnnotations()) != cephv1beta1.NodeInMaintenance(e.MetaNew.GetAnnotations())
+	},
+}
@@ -0,0 +1,28 @@
+package cephcluster
+
+import (
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// PodEventMapper maps daemon pod events to the ceph cluster so disruption budgets follow daemon readiness
+type PodEventMapper struct{}
+
+func (m *PodEventMapper) Map(o handler.MapObject) []reconcile.Request {
+	clusterName, ok := o.Meta.GetLabels()[cephv1beta1.ClusterNameLabel]
+	if !ok {
+		return []reconcile.Request{}
+	}
+
+	return []reconcile.Request{
+		reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      clusterName,
+				Namespace: o.Meta.GetNamespace(),
+			},
+		},
+	}
+}
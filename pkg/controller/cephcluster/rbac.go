@@ -0,0 +1,77 @@
+package cephcluster
+
+import (
+	"context"
+	"reflect"
+
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// ensureRBAC creates the ServiceAccount, Role and RoleBinding of each daemon type and binds the osd ServiceAccount
+// to the osd ClusterRole
+func (r *ReconcileCephCluster) ensureRBAC(instance *cephv1beta1.CephCluster) error {
+	for _, daemonType := range cephv1beta1.ServiceAccountDaemonTypes {
+		sa := instance.GetServiceAccount(daemonType)
+		if err := controllerutil.SetControllerReference(instance, sa, r.scheme); err != nil {
+			return err
+		}
+		err := r.createIfNotFound(sa)
+		if err != nil {
+			return err
+		}
+
+		err = r.ensureRole(instance, instance.GetRole(daemonType))
+		if err != nil {
+			return err
+		}
+
+		binding := instance.GetRoleBinding(daemonType)
+		if err := controllerutil.SetControllerReference(instance, binding, r.scheme); err != nil {
+			return err
+		}
+		err = r.createIfNotFound(binding)
+		if err != nil {
+			return err
+		}
+	}
+
+	return r.createIfNotFound(instance.GetOsdClusterRoleBinding())
+}
+
+// ensureRole creates the role, or updates its rules if they've changed since it was created
+func (r *ReconcileCephCluster) ensureRole(instance *cephv1beta1.CephCluster, role *rbacv1.Role) error {
+	if err := controllerutil.SetControllerReference(instance, role, r.scheme); err != nil {
+		return err
+	}
+
+	existing := &rbacv1.Role{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: role.Namespace, Name: role.Name}, existing)
+	if errors.IsNotFound(err) {
+		return r.createIfNotFound(role)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(existing.Rules) == 0 && len(role.Rules) == 0 || reflect.DeepEqual(existing.Rules, role.Rules) {
+		return nil
+	}
+
+	log.Info("Updating role rules", "Role", role.Name)
+	existing.Rules = role.Rules
+	return r.updateObject(existing)
+}
+
+// deleteOsdRBAC removes the osd ClusterRoleBinding, it's cluster scoped so it can't be garbage collected
+// with the cluster
+func (r *ReconcileCephCluster) deleteOsdRBAC(instance *cephv1beta1.CephCluster) error {
+	err := r.client.Delete(context.TODO(), instance.GetOsdClusterRoleBinding())
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package cephcluster
+
+import (
+	"context"
+	"fmt"
+
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/controller/common"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// teardown shuts a deleted cluster down through the state machine, holding the deletion with a finalizer
+// until the cluster is idle.  Owned objects are garbage collected once the finalizer is removed.  A cluster that
+// can't reach idle is released by the force teardown annotation, its data is still retained or deleted.
+func (r *ReconcileCephCluster) teardown(instance *cephv1beta1.CephCluster, reqLogger logr.Logger) (reconcile.Result, error) {
+	if !common.HasFinalizer(instance, cephv1beta1.CephClusterFinalizer) {
+		return reconcile.Result{}, nil
+	}
+
+	if instance.GetState() != cephv1beta1.CephClusterIdle {
+		if !instance.ForceTeardown() {
+			return r.transition(instance, reqLogger)
+		}
+		reqLogger.Info("Forcing teardown before the cluster is idle", "State", instance.GetState())
+	}
+
+	err := r.deleteClientConfig(instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	err = r.deleteOsdRBAC(instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	err = r.orphanVolumeClaims(instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Keyring secrets outlived their cluster before the reclaim policy existed, only an explicit Delete removes them
+	if instance.Spec.ReclaimPolicy == cephv1beta1.ReclaimPolicyDelete {
+		err = r.deleteKeyringSecrets(instance)
+	} else {
+		err = r.retainKeyringSecrets(instance)
+	}
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	reqLogger.Info("Removing finalizer", "State", instance.GetState(), "ReclaimPolicy", instance.GetReclaimPolicy())
+	common.RemoveFinalizer(instance, cephv1beta1.CephClusterFinalizer)
+	return reconcile.Result{}, r.updateObject(instance)
+}
+
+// deleteClientConfig removes the copies of ceph.conf published to client namespaces
+func (r *ReconcileCephCluster) deleteClientConfig(instance *cephv1beta1.CephCluster) error {
+	for _, namespace := range instance.Status.ClientConfigNamespaces {
+		clientConfigMap := &corev1.ConfigMap{}
+		clientConfigMap.Name = instance.GetCephConfigMapName()
+		clientConfigMap.Namespace = namespace
+		err := r.client.Delete(context.TODO(), clientConfigMap)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteKeyringSecrets removes the cluster's keyring secrets, they aren't owned by the cluster
+func (r *ReconcileCephCluster) deleteKeyringSecrets(instance *cephv1beta1.CephCluster) error {
+	secrets, err := r.listKeyringSecrets(instance)
+	if err != nil {
+		return err
+	}
+
+	for i := range secrets.Items {
+		err = r.client.Delete(context.TODO(), &secrets.Items[i])
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// retainKeyringSecrets labels the cluster's keyring secrets so a re-created cluster adopts them
+func (r *ReconcileCephCluster) retainKeyringSecrets(instance *cephv1beta1.CephCluster) error {
+	secrets, err := r.listKeyringSecrets(instance)
+	if err != nil {
+		return err
+	}
+
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if !common.ApplyReclaimPolicy(secret, secret, instance, cephv1beta1.ReclaimPolicyRetain, instance) {
+			continue
+		}
+		err = r.updateObject(secret)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ReconcileCephCluster) listKeyringSecrets(instance *cephv1beta1.CephCluster) (*corev1.SecretList, error) {
+	secrets := &corev1.SecretList{}
+	listOptions := &client.ListOptions{Namespace: instance.GetNamespace()}
+	err := listOptions.SetLabelSelector(fmt.Sprintf("%s=%s,%s", cephv1beta1.ClusterNameLabel, instance.GetName(), cephv1beta1.KeyringEntityLabel))
+	if err != nil {
+		return nil, err
+	}
+
+	return secrets, r.client.List(context.TODO(), listOptions, secrets)
+}
+
+// orphanVolumeClaims removes the owner references from the PVCs of monitors and osds with a Retain
+// reclaim policy so they survive the garbage collection of their daemons
+func (r *ReconcileCephCluster) orphanVolumeClaims(instance *cephv1beta1.CephCluster) error {
+	owners := map[metav1.Object]cephv1beta1.ReclaimPolicy{}
+
+	monitors := &cephv1beta1.CephMonList{}
+	err := r.client.List(context.TODO(), &client.ListOptions{Namespace: instance.GetNamespace()}, monitors)
+	if err != nil {
+		return err
+	}
+	for i := range monitors.Items {
+		if monitors.Items[i].Spec.ClusterName == instance.GetName() {
+			owners[&monitors.Items[i]] = monitors.Items[i].Spec.ReclaimPolicy.Or(instance.GetReclaimPolicy())
+		}
+	}
+
+	osds := &cephv1beta1.CephOsdList{}
+	err = r.client.List(context.TODO(), &client.ListOptions{Namespace: instance.GetNamespace()}, osds)
+	if err != nil {
+		return err
+	}
+	for i := range osds.Items {
+		if osds.Items[i].Spec.ClusterName == instance.GetName() {
+			owners[&osds.Items[i]] = osds.Items[i].Spec.ReclaimPolicy.Or(instance.GetReclaimPolicy())
+		}
+	}
+
+	for owner, policy := range owners {
+		if policy != cephv1beta1.ReclaimPolicyRetain {
+			continue
+		}
+		err = r.orphanVolumeClaim(instance, owner)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// orphanVolumeClaim removes the owner's reference from the PVC named after it
+func (r *ReconcileCephCluster) orphanVolumeClaim(instance *cephv1beta1.CephCluster, owner metav1.Object) error {
+	pvc := &corev1.PersistentVolumeClaim{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: owner.GetNamespace(), Name: owner.GetName()}, pvc)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !common.ApplyReclaimPolicy(pvc, pvc, owner, cephv1beta1.ReclaimPolicyRetain, instance) {
+		return nil
+	}
+
+	log.Info("Retaining volume claim", "PersistentVolumeClaim", pvc.GetName())
+	return r.updateObject(pvc)
+}
@@ -0,0 +1,130 @@
+package cephcluster
+
+import (
+	"context"
+	"testing"
+
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/ceph"
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/controller/common"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func newDeletedCluster(state cephv1beta1.CephClusterState) *cephv1beta1.CephCluster {
+	cluster := newTestCluster(state)
+	cluster.UID = "cluster-uid"
+	deleted := metav1.Now()
+	cluster.SetDeletionTimestamp(&deleted)
+	common.AddFinalizer(cluster, cephv1beta1.CephClusterFinalizer)
+	return cluster
+}
+
+func newKeyringSecret(cluster *cephv1beta1.CephCluster) *corev1.Secret {
+	secret := &corev1.Secret{}
+	secret.Name = "ceph-test-admin-keyring"
+	secret.Namespace = testNamespace
+	secret.Labels = map[string]string{
+		cephv1beta1.ClusterNameLabel:   cluster.GetName(),
+		cephv1beta1.KeyringEntityLabel: "client.admin",
+	}
+	return secret
+}
+
+func newOwnedVolumeClaim(owner *cephv1beta1.CephMon) *corev1.PersistentVolumeClaim {
+	pvc := &corev1.PersistentVolumeClaim{}
+	pvc.Name = owner.GetName()
+	pvc.Namespace = testNamespace
+	pvc.OwnerReferences = []metav1.OwnerReference{{APIVersion: "ceph.k8s.pgc.umn.edu/v1beta1", Kind: "CephMon", Name: owner.GetName(), UID: owner.GetUID()}}
+	return pvc
+}
+
+func TestTeardownWaitsForIdle(t *testing.T) {
+	cluster := newDeletedCluster(cephv1beta1.CephClusterRunning)
+	r := newTestReconciler(&ceph.FakeRunner{}, cluster, newKeyringSecret(cluster))
+
+	_, err := r.teardown(cluster, logf.Log)
+	if err != nil {
+		t.Fatalf("teardown failed: %v", err)
+	}
+
+	if !common.HasFinalizer(cluster, cephv1beta1.CephClusterFinalizer) {
+		t.Errorf("finalizer removed from a running cluster")
+	}
+	if cluster.GetState() != cephv1beta1.CephClusterShutdown {
+		t.Errorf("expected the cluster to shut down, got %s", cluster.GetState())
+	}
+
+	err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: "ceph-test-admin-keyring"}, &corev1.Secret{})
+	if err != nil {
+		t.Errorf("keyring secret removed before the cluster was idle: %v", err)
+	}
+}
+
+func TestTeardown(t *testing.T) {
+	tests := map[string]struct {
+		State                 cephv1beta1.CephClusterState
+		ReclaimPolicy         cephv1beta1.ReclaimPolicy
+		Force                 bool
+		ExpectKeyringRetained bool
+		ExpectClaimOrphaned   bool
+	}{
+		"idle-default":  {State: cephv1beta1.CephClusterIdle, ExpectKeyringRetained: true},
+		"idle-delete":   {State: cephv1beta1.CephClusterIdle, ReclaimPolicy: cephv1beta1.ReclaimPolicyDelete},
+		"idle-retain":   {State: cephv1beta1.CephClusterIdle, ReclaimPolicy: cephv1beta1.ReclaimPolicyRetain, ExpectKeyringRetained: true, ExpectClaimOrphaned: true},
+		"forced-delete": {State: cephv1beta1.CephClusterStartMons, ReclaimPolicy: cephv1beta1.ReclaimPolicyDelete, Force: true},
+		"forced-retain": {State: cephv1beta1.CephClusterStartMons, ReclaimPolicy: cephv1beta1.ReclaimPolicyRetain, Force: true, ExpectKeyringRetained: true, ExpectClaimOrphaned: true},
+		"forced-idle":   {State: cephv1beta1.CephClusterIdle, ReclaimPolicy: cephv1beta1.ReclaimPolicyDelete, Force: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cluster := newDeletedCluster(test.State)
+			cluster.Spec.ReclaimPolicy = test.ReclaimPolicy
+			if test.Force {
+				cluster.SetAnnotations(map[string]string{cephv1beta1.ForceTeardownAnnotation: "true"})
+			}
+
+			mon := &cephv1beta1.CephMon{}
+			mon.Name = "test-mon-a"
+			mon.Namespace = testNamespace
+			mon.UID = "mon-uid"
+			mon.Spec.ClusterName = testClusterName
+
+			r := newTestReconciler(&ceph.FakeRunner{}, cluster, mon, newKeyringSecret(cluster), newOwnedVolumeClaim(mon))
+
+			_, err := r.teardown(cluster, logf.Log)
+			if err != nil {
+				t.Fatalf("teardown failed: %v", err)
+			}
+
+			if common.HasFinalizer(cluster, cephv1beta1.CephClusterFinalizer) {
+				t.Errorf("finalizer wasn't removed")
+			}
+
+			secret := &corev1.Secret{}
+			err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: "ceph-test-admin-keyring"}, secret)
+			if test.ExpectKeyringRetained {
+				if err != nil {
+					t.Errorf("retained keyring secret was removed: %v", err)
+				} else if secret.Labels[cephv1beta1.FsidLabel] != cluster.Spec.Fsid {
+					t.Errorf("retained keyring secret isn't labeled with the fsid: %v", secret.Labels)
+				}
+			} else if !errors.IsNotFound(err) {
+				t.Errorf("expected the keyring secret to be deleted, got %v", err)
+			}
+
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: mon.GetName()}, pvc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if orphaned := len(pvc.OwnerReferences) == 0; orphaned != test.ExpectClaimOrphaned {
+				t.Errorf("expected the volume claim to be orphaned %t, owners %v", test.ExpectClaimOrphaned, pvc.OwnerReferences)
+			}
+		})
+	}
+}
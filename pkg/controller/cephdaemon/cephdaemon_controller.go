@@ -4,7 +4,8 @@ import (
 	"context"
 	"fmt"
 
-	cephv1alpha1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1alpha1"
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/controller/common"
 	corev1 "k8s.io/api/core/v1"
 
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -45,14 +46,14 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	}
 
 	// Watch for changes to primary resource CephDaemon
-	err = c.Watch(&source.Kind{Type: &cephv1alpha1.CephDaemon{}}, &handler.EnqueueRequestForObject{})
+	err = c.Watch(&source.Kind{Type: &cephv1beta1.CephDaemon{}}, &handler.EnqueueRequestForObject{}, common.WatchedNamespace)
 	if err != nil {
 		return err
 	}
 
-	err = c.Watch(&source.Kind{Type: &cephv1alpha1.CephDaemonCluster{}}, &handler.EnqueueRequestsFromMapFunc{
+	err = c.Watch(&source.Kind{Type: &cephv1beta1.CephDaemonCluster{}}, &handler.EnqueueRequestsFromMapFunc{
 		ToRequests: &DaemonClusterEventMapper{client: mgr.GetClient(), scheme: mgr.GetScheme()},
-	})
+	}, common.WatchedNamespace)
 	if err != nil {
 		return err
 	}
@@ -61,8 +62,8 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	// Watch for changes to secondary resource Pods and requeue the owner CephDaemon
 	err = c.Watch(&source.Kind{Type: &corev1.Pod{}}, &handler.EnqueueRequestForOwner{
 		IsController: true,
-		OwnerType:    &cephv1alpha1.CephDaemon{},
-	})
+		OwnerType:    &cephv1beta1.CephDaemon{},
+	}, common.WatchedNamespace)
 	if err != nil {
 		return err
 	}
@@ -92,7 +93,7 @@ func (r *ReconcileCephDaemon) Reconcile(request reconcile.Request) (reconcile.Re
 	reqLogger.Info("Reconciling CephDaemon")
 
 	// Fetch the CephDaemon instance
-	instance := &cephv1alpha1.CephDaemon{}
+	instance := &cephv1beta1.CephDaemon{}
 	err := r.client.Get(context.TODO(), request.NamespacedName, instance)
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -148,19 +149,19 @@ func (r *ReconcileCephDaemon) Reconcile(request reconcile.Request) (reconcile.Re
 
 }
 
-func updateLabels(d *cephv1alpha1.CephDaemon) bool {
+func updateLabels(d *cephv1beta1.CephDaemon) bool {
 	var updated bool
 	labels := d.GetLabels()
 	if labels == nil {
 		labels = make(map[string]string)
 	}
-	if val, ok := labels[cephv1alpha1.ClusterNameLabel]; !ok || val != d.Spec.ClusterName {
-		labels[cephv1alpha1.ClusterNameLabel] = d.Spec.ClusterName
+	if val, ok := labels[cephv1beta1.ClusterNameLabel]; !ok || val != d.Spec.ClusterName {
+		labels[cephv1beta1.ClusterNameLabel] = d.Spec.ClusterName
 		d.SetLabels(labels)
 		updated = true
 	}
-	if val, ok := labels[cephv1alpha1.DaemonTypeLabel]; !ok || val != d.Spec.DaemonType.String() {
-		labels[cephv1alpha1.DaemonTypeLabel] = d.Spec.DaemonType.String()
+	if val, ok := labels[cephv1beta1.DaemonTypeLabel]; !ok || val != d.Spec.DaemonType.String() {
+		labels[cephv1beta1.DaemonTypeLabel] = d.Spec.DaemonType.String()
 		d.SetLabels(labels)
 		updated = true
 	}
@@ -172,12 +173,12 @@ func (r *ReconcileCephDaemon) updateObject(object runtime.Object) error {
 	return r.client.Update(context.TODO(), object)
 }
 
-func (r *ReconcileCephDaemon) getDaemonCluster(d *cephv1alpha1.CephDaemon) (*cephv1alpha1.CephDaemonCluster, error) {
-	daemonClusterList := &cephv1alpha1.CephDaemonClusterList{}
-	daemonClusterListOptions := &client.ListOptions{}
+func (r *ReconcileCephDaemon) getDaemonCluster(d *cephv1beta1.CephDaemon) (*cephv1beta1.CephDaemonCluster, error) {
+	daemonClusterList := &cephv1beta1.CephDaemonClusterList{}
+	daemonClusterListOptions := &client.ListOptions{Namespace: d.GetNamespace()}
 	daemonClusterListOptions.MatchingLabels(map[string]string{
-		cephv1alpha1.ClusterNameLabel: d.Spec.ClusterName,
-		cephv1alpha1.DaemonTypeLabel:  d.Spec.DaemonType.String(),
+		cephv1beta1.ClusterNameLabel: d.Spec.ClusterName,
+		cephv1beta1.DaemonTypeLabel:  d.Spec.DaemonType.String(),
 	})
 	err := r.client.List(context.TODO(), daemonClusterListOptions, daemonClusterList)
 	if err != nil {
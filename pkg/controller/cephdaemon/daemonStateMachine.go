@@ -6,7 +6,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/api/errors"
 
-	cephv1alpha1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1alpha1"
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -19,8 +19,8 @@ type TransitionFunc func(client.Client, *runtime.Scheme) error
 type podCheckFunc func(*corev1.Pod) bool
 
 type CephDaemonStateMachine interface {
-	State() cephv1alpha1.CephDaemonState
-	GetTransition(ReadOnlyClient) (TransitionFunc, cephv1alpha1.CephDaemonState)
+	State() cephv1beta1.CephDaemonState
+	GetTransition(ReadOnlyClient) (TransitionFunc, cephv1beta1.CephDaemonState)
 }
 
 type ReadOnlyClient interface {
@@ -28,35 +28,35 @@ type ReadOnlyClient interface {
 	List(context.Context, *client.ListOptions, runtime.Object) error
 }
 
-func NewCephDaemonStateMachine(daemon *cephv1alpha1.CephDaemon,
-	daemonCluster *cephv1alpha1.CephDaemonCluster, logger logr.Logger) CephDaemonStateMachine {
+func NewCephDaemonStateMachine(daemon *cephv1beta1.CephDaemon,
+	daemonCluster *cephv1beta1.CephDaemonCluster, logger logr.Logger) CephDaemonStateMachine {
 
 	switch daemon.Spec.DaemonType {
-	case cephv1alpha1.CephDaemonTypeMgr:
+	case cephv1beta1.CephDaemonTypeMgr:
 		return &MgrStateMachine{BaseStateMachine: newBaseStateMachine(daemon, daemonCluster, logger)}
-	case cephv1alpha1.CephDaemonTypeMds:
+	case cephv1beta1.CephDaemonTypeMds:
 		return &MdsStateMachine{BaseStateMachine: newBaseStateMachine(daemon, daemonCluster, logger)}
 	default:
 		return nil
 	}
 }
 
-func newBaseStateMachine(daemon *cephv1alpha1.CephDaemon,
-	daemonCluster *cephv1alpha1.CephDaemonCluster, logger logr.Logger) *BaseStateMachine {
+func newBaseStateMachine(daemon *cephv1beta1.CephDaemon,
+	daemonCluster *cephv1beta1.CephDaemonCluster, logger logr.Logger) *BaseStateMachine {
 	return &BaseStateMachine{daemon: daemon, daemonCluster: daemonCluster, logger: logger}
 }
 
 type BaseStateMachine struct {
-	daemon        *cephv1alpha1.CephDaemon
-	daemonCluster *cephv1alpha1.CephDaemonCluster
+	daemon        *cephv1beta1.CephDaemon
+	daemonCluster *cephv1beta1.CephDaemonCluster
 	logger        logr.Logger
 }
 
 func (s *BaseStateMachine) daemonEnabled() bool {
-	return !s.daemon.Spec.Disabled && s.daemonCluster.GetState() != cephv1alpha1.CephDaemonClusterStateIdle
+	return !s.daemon.Spec.Disabled && s.daemonCluster.GetState() != cephv1beta1.CephDaemonClusterStateIdle
 }
 
-func (s *BaseStateMachine) State() cephv1alpha1.CephDaemonState {
+func (s *BaseStateMachine) State() cephv1beta1.CephDaemonState {
 	return s.daemon.GetState()
 }
 
@@ -130,63 +130,74 @@ func (s *BaseStateMachine) launchPod(client client.Client, scheme *runtime.Schem
 	pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts, volumeMounts...)
 	pod.Spec.Volumes = append(pod.Spec.Volumes, volumes...)
 
+	cluster := &cephv1beta1.CephCluster{}
+	err := client.Get(context.TODO(), types.NamespacedName{Namespace: s.daemon.GetNamespace(), Name: s.daemon.Spec.ClusterName}, cluster)
+	if err != nil {
+		return err
+	}
+
+	err = cluster.ApplyPodOverrides(daemonType, &pod.Spec)
+	if err != nil {
+		return err
+	}
+
 	if err := controllerutil.SetControllerReference(s.daemon, pod, scheme); err != nil {
 		return err
 	}
 
-	err := client.Create(context.TODO(), pod)
+	err = client.Create(context.TODO(), pod)
 	if !errors.IsAlreadyExists(err) {
 		return err
 	}
 	return nil
 }
 
-func (s *BaseStateMachine) GetTransition(client ReadOnlyClient) (TransitionFunc, cephv1alpha1.CephDaemonState) {
+func (s *BaseStateMachine) GetTransition(client ReadOnlyClient) (TransitionFunc, cephv1beta1.CephDaemonState) {
 
-	if !s.daemonEnabled() && s.State() != cephv1alpha1.CephDaemonStateCleanup && s.State() != cephv1alpha1.CephDaemonStateIdle {
-		return nil, cephv1alpha1.CephDaemonStateCleanup
+	if !s.daemonEnabled() && s.State() != cephv1beta1.CephDaemonStateCleanup && s.State() != cephv1beta1.CephDaemonStateIdle {
+		return nil, cephv1beta1.CephDaemonStateCleanup
 	}
 
 	switch s.State() {
-	case cephv1alpha1.CephDaemonStateIdle:
+	case cephv1beta1.CephDaemonStateIdle:
 		if s.daemonEnabled() {
-			return nil, cephv1alpha1.CephDaemonStateLaunching
+			return nil, cephv1beta1.CephDaemonStateLaunching
 		}
 
-	case cephv1alpha1.CephDaemonStateLaunching:
-		return s.launchPod, cephv1alpha1.CephDaemonStateWaitForRun
+	case cephv1beta1.CephDaemonStateLaunching:
+		return s.launchPod, cephv1beta1.CephDaemonStateWaitForRun
 
-	case cephv1alpha1.CephDaemonStateWaitForRun:
+	case cephv1beta1.CephDaemonStateWaitForRun:
 		running, err := s.checkPod(client, podRunning)
 		if err != nil {
-			return nil, cephv1alpha1.CephDaemonStateError
+			return nil, cephv1beta1.CephDaemonStateError
 		}
 		if running {
-			return nil, cephv1alpha1.CephDaemonStateWaitForReady
+			return nil, cephv1beta1.CephDaemonStateWaitForReady
 		}
 
-	case cephv1alpha1.CephDaemonStateWaitForReady:
+	case cephv1beta1.CephDaemonStateWaitForReady:
 		ready, err := s.checkPod(client, podReady)
 		if err != nil {
-			return nil, cephv1alpha1.CephDaemonStateError
+			return nil, cephv1beta1.CephDaemonStateError
 		}
 		if ready {
-			return nil, cephv1alpha1.CephDaemonStateReady
+			return nil, cephv1beta1.CephDaemonStateReady
 		}
 
-	case cephv1alpha1.CephDaemonStateReady:
+	case cephv1beta1.CephDaemonStateReady:
 		if _, err := s.checkPod(client, func(*corev1.Pod) bool { return true }); err != nil {
-			return nil, cephv1alpha1.CephDaemonStateError
+			return nil, cephv1beta1.CephDaemonStateError
 		}
 
-	case cephv1alpha1.CephDaemonStateError:
-		return s.logError, cephv1alpha1.CephDaemonStateCleanup
+	case cephv1beta1.CephDaemonStateError:
+		return s.logError, cephv1beta1.CephDaemonStateCleanup
 
-	case cephv1alpha1.CephDaemonStateCleanup:
-		return s.deletePod, cephv1alpha1.CephDaemonStateIdle
+	case cephv1beta1.CephDaemonStateCleanup:
+		return s.deletePod, cephv1beta1.CephDaemonStateIdle
 
 	default:
-		return nil, cephv1alpha1.CephDaemonStateCleanup
+		return nil, cephv1beta1.CephDaemonStateCleanup
 	}
 
 	return nil, s.State()
@@ -196,7 +207,7 @@ type MgrStateMachine struct {
 	*BaseStateMachine
 }
 
-func (s *MgrStateMachine) GetTransition(client ReadOnlyClient) (TransitionFunc, cephv1alpha1.CephDaemonState) {
+func (s *MgrStateMachine) GetTransition(client ReadOnlyClient) (TransitionFunc, cephv1beta1.CephDaemonState) {
 
 	switch s.State() {
 	default:
@@ -208,7 +219,7 @@ type MdsStateMachine struct {
 	*BaseStateMachine
 }
 
-func (s *MdsStateMachine) GetTransition(client ReadOnlyClient) (TransitionFunc, cephv1alpha1.CephDaemonState) {
+func (s *MdsStateMachine) GetTransition(client ReadOnlyClient) (TransitionFunc, cephv1beta1.CephDaemonState) {
 
 	switch s.State() {
 	default:
@@ -3,7 +3,7 @@ package cephdaemon
 import (
 	"context"
 
-	cephv1alpha1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1alpha1"
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -25,12 +25,12 @@ func (m *DaemonClusterEventMapper) Map(o handler.MapObject) []reconcile.Request
 	req := make([]reconcile.Request, 0, 1)
 	switch obj := o.Object.(type) {
 
-	case *cephv1alpha1.CephDaemonCluster:
+	case *cephv1beta1.CephDaemonCluster:
 
-		daemons := &cephv1alpha1.CephDaemonList{}
-		listOptions := &client.ListOptions{}
-		listOptions.MatchingLabels(map[string]string{cephv1alpha1.ClusterNameLabel: obj.Spec.ClusterName,
-			cephv1alpha1.DaemonTypeLabel: obj.Spec.DaemonType.String()})
+		daemons := &cephv1beta1.CephDaemonList{}
+		listOptions := &client.ListOptions{Namespace: obj.GetNamespace()}
+		listOptions.MatchingLabels(map[string]string{cephv1beta1.ClusterNameLabel: obj.Spec.ClusterName,
+			cephv1beta1.DaemonTypeLabel: obj.Spec.DaemonType.String()})
 
 		err := m.client.List(context.TODO(), listOptions, daemons)
 		if err != nil {
@@ -4,7 +4,7 @@ import (
 	"context"
 	"fmt"
 
-	cephv1alpha1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1alpha1"
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
 	"github.com/PolarGeospatialCenter/ceph-operator/pkg/controller/common"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -46,23 +46,23 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	}
 
 	// Watch for changes to primary resource CephDaemonCluster
-	err = c.Watch(&source.Kind{Type: &cephv1alpha1.CephDaemonCluster{}}, &handler.EnqueueRequestForObject{})
+	err = c.Watch(&source.Kind{Type: &cephv1beta1.CephDaemonCluster{}}, &handler.EnqueueRequestForObject{}, common.WatchedNamespace)
 	if err != nil {
 		return err
 	}
 
-	err = c.Watch(&source.Kind{Type: &cephv1alpha1.CephDaemon{}}, &handler.EnqueueRequestForOwner{
+	err = c.Watch(&source.Kind{Type: &cephv1beta1.CephDaemon{}}, &handler.EnqueueRequestForOwner{
 		IsController: true,
-		OwnerType:    &cephv1alpha1.CephDaemonCluster{},
-	})
+		OwnerType:    &cephv1beta1.CephDaemonCluster{},
+	}, common.WatchedNamespace)
 	if err != nil {
 		return err
 	}
 
-	err = c.Watch(&source.Kind{Type: &cephv1alpha1.CephCluster{}}, &handler.EnqueueRequestsFromMapFunc{
+	err = c.Watch(&source.Kind{Type: &cephv1beta1.CephCluster{}}, &handler.EnqueueRequestsFromMapFunc{
 		ToRequests: &common.CephClusterEventMapper{Client: mgr.GetClient(), Scheme: mgr.GetScheme(),
-			ApiVersion: cephv1alpha1.SchemeGroupVersion.String(), Kind: "CephDaemonCluster"},
-	})
+			ApiVersion: cephv1beta1.SchemeGroupVersion.String(), Kind: "CephDaemonCluster"},
+	}, common.WatchedNamespace)
 	if err != nil {
 		return err
 	}
@@ -92,7 +92,7 @@ func (r *ReconcileCephDaemonCluster) Reconcile(request reconcile.Request) (recon
 	reqLogger.Info("Reconciling CephDaemonCluster")
 
 	// Fetch the CephDaemonCluster instance
-	instance := &cephv1alpha1.CephDaemonCluster{}
+	instance := &cephv1beta1.CephDaemonCluster{}
 	err := r.client.Get(context.TODO(), request.NamespacedName, instance)
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -138,19 +138,19 @@ func (r *ReconcileCephDaemonCluster) Reconcile(request reconcile.Request) (recon
 	return reconcile.Result{}, r.updateObject(instance)
 }
 
-func updateLabels(d *cephv1alpha1.CephDaemonCluster) bool {
+func updateLabels(d *cephv1beta1.CephDaemonCluster) bool {
 	var updated bool
 	labels := d.GetLabels()
 	if labels == nil {
 		labels = make(map[string]string)
 	}
-	if val, ok := labels[cephv1alpha1.ClusterNameLabel]; !ok || val != d.Spec.ClusterName {
-		labels[cephv1alpha1.ClusterNameLabel] = d.Spec.ClusterName
+	if val, ok := labels[cephv1beta1.ClusterNameLabel]; !ok || val != d.Spec.ClusterName {
+		labels[cephv1beta1.ClusterNameLabel] = d.Spec.ClusterName
 		d.SetLabels(labels)
 		updated = true
 	}
-	if val, ok := labels[cephv1alpha1.DaemonTypeLabel]; !ok || val != d.Spec.DaemonType.String() {
-		labels[cephv1alpha1.DaemonTypeLabel] = d.Spec.DaemonType.String()
+	if val, ok := labels[cephv1beta1.DaemonTypeLabel]; !ok || val != d.Spec.DaemonType.String() {
+		labels[cephv1beta1.DaemonTypeLabel] = d.Spec.DaemonType.String()
 		d.SetLabels(labels)
 		updated = true
 	}
@@ -162,8 +162,8 @@ func (r *ReconcileCephDaemonCluster) updateObject(object runtime.Object) error {
 	return r.client.Update(context.TODO(), object)
 }
 
-func (r *ReconcileCephDaemonCluster) getCephCluster(d *cephv1alpha1.CephDaemonCluster) (*cephv1alpha1.CephCluster, error) {
-	cephCluster := &cephv1alpha1.CephCluster{}
+func (r *ReconcileCephDaemonCluster) getCephCluster(d *cephv1beta1.CephDaemonCluster) (*cephv1beta1.CephCluster, error) {
+	cephCluster := &cephv1beta1.CephCluster{}
 	cephClusterNamespacedName := types.NamespacedName{
 		Name:      d.GetCephClusterName(),
 		Namespace: d.GetNamespace(),
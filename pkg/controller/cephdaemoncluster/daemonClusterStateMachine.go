@@ -6,7 +6,7 @@ import (
 	"math/rand"
 	"time"
 
-	cephv1alpha1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1alpha1"
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -20,8 +20,8 @@ type TransitionFunc func(client.Client, *runtime.Scheme) error
 //type podCheckFunc func(*corev1.Pod) bool
 
 type CephDaemonClusterStateMachine interface {
-	State() cephv1alpha1.CephDaemonClusterState
-	GetTransition(ReadOnlyClient) (TransitionFunc, cephv1alpha1.CephDaemonClusterState)
+	State() cephv1beta1.CephDaemonClusterState
+	GetTransition(ReadOnlyClient) (TransitionFunc, cephv1beta1.CephDaemonClusterState)
 }
 
 type ReadOnlyClient interface {
@@ -29,27 +29,27 @@ type ReadOnlyClient interface {
 	List(context.Context, *client.ListOptions, runtime.Object) error
 }
 
-func NewCephDaemonClusterStateMachine(daemonCluster *cephv1alpha1.CephDaemonCluster,
-	cluster *cephv1alpha1.CephCluster, logger logr.Logger) CephDaemonClusterStateMachine {
+func NewCephDaemonClusterStateMachine(daemonCluster *cephv1beta1.CephDaemonCluster,
+	cluster *cephv1beta1.CephCluster, logger logr.Logger) CephDaemonClusterStateMachine {
 
 	switch daemonCluster.Spec.DaemonType {
-	case cephv1alpha1.CephDaemonTypeMgr:
+	case cephv1beta1.CephDaemonTypeMgr:
 		return &MgrStateMachine{BaseStateMachine: newBaseStateMachine(daemonCluster, cluster, logger)}
-	case cephv1alpha1.CephDaemonTypeMds:
+	case cephv1beta1.CephDaemonTypeMds:
 		return &MdsStateMachine{BaseStateMachine: newBaseStateMachine(daemonCluster, cluster, logger)}
 	default:
 		return nil
 	}
 }
 
-func newBaseStateMachine(daemonCluster *cephv1alpha1.CephDaemonCluster,
-	cluster *cephv1alpha1.CephCluster, logger logr.Logger) *BaseStateMachine {
+func newBaseStateMachine(daemonCluster *cephv1beta1.CephDaemonCluster,
+	cluster *cephv1beta1.CephCluster, logger logr.Logger) *BaseStateMachine {
 	return &BaseStateMachine{cluster: cluster, daemonCluster: daemonCluster, logger: logger}
 }
 
 type BaseStateMachine struct {
-	cluster       *cephv1alpha1.CephCluster
-	daemonCluster *cephv1alpha1.CephDaemonCluster
+	cluster       *cephv1beta1.CephCluster
+	daemonCluster *cephv1beta1.CephDaemonCluster
 	logger        logr.Logger
 }
 
@@ -57,7 +57,7 @@ func (s *BaseStateMachine) daemonClusterEnabled() bool {
 	return !s.daemonCluster.Spec.Disabled && s.cluster.GetDaemonEnabled(s.daemonCluster.GetDaemonType())
 }
 
-func (s *BaseStateMachine) State() cephv1alpha1.CephDaemonClusterState {
+func (s *BaseStateMachine) State() cephv1beta1.CephDaemonClusterState {
 	return s.daemonCluster.GetState()
 }
 
@@ -84,7 +84,7 @@ func (s *BaseStateMachine) scaleDown(client client.Client, scheme *runtime.Schem
 		return fmt.Errorf("unabled to delete daemon, no daemons found")
 	}
 
-	var toDelete cephv1alpha1.CephDaemon
+	var toDelete cephv1beta1.CephDaemon
 
 	rand.Seed(time.Now().UnixNano())
 	toDelete = daemons.Items[rand.Int()%len(daemons.Items)]
@@ -93,10 +93,11 @@ func (s *BaseStateMachine) scaleDown(client client.Client, scheme *runtime.Schem
 }
 
 func (s *BaseStateMachine) scaleUp(client client.Client, scheme *runtime.Scheme) error {
-	daemon := cephv1alpha1.NewCephDaemon(s.daemonCluster.GetDaemonType(), s.daemonCluster.GetCephClusterName())
+	daemon := cephv1beta1.NewCephDaemon(s.daemonCluster.GetDaemonType(), s.daemonCluster.GetCephClusterName())
 
 	daemon.Spec.Image = s.daemonCluster.GetImage()
 	daemon.Spec.CephConfConfigMapName = s.daemonCluster.GetCephConfConfigMapName()
+	daemon.Spec.Network = s.daemonCluster.GetNetwork()
 	daemon.Namespace = s.daemonCluster.GetNamespace()
 
 	if err := controllerutil.SetControllerReference(s.daemonCluster, daemon, scheme); err != nil {
@@ -111,12 +112,12 @@ func (s *BaseStateMachine) scaleUp(client client.Client, scheme *runtime.Scheme)
 	return nil
 }
 
-func (s *BaseStateMachine) listDaemons(readClient ReadOnlyClient) (*cephv1alpha1.CephDaemonList, error) {
-	daemonList := &cephv1alpha1.CephDaemonList{}
-	daemonListOptions := &client.ListOptions{}
+func (s *BaseStateMachine) listDaemons(readClient ReadOnlyClient) (*cephv1beta1.CephDaemonList, error) {
+	daemonList := &cephv1beta1.CephDaemonList{}
+	daemonListOptions := &client.ListOptions{Namespace: s.daemonCluster.GetNamespace()}
 	daemonListOptions.MatchingLabels(map[string]string{
-		cephv1alpha1.ClusterNameLabel: s.cluster.GetName(),
-		cephv1alpha1.DaemonTypeLabel:  s.daemonCluster.GetDaemonType().String(),
+		cephv1beta1.ClusterNameLabel: s.cluster.GetName(),
+		cephv1beta1.DaemonTypeLabel:  s.daemonCluster.GetDaemonType().String(),
 	})
 
 	return daemonList, readClient.List(context.TODO(), daemonListOptions, daemonList)
@@ -148,43 +149,43 @@ func (s *BaseStateMachine) scale(client ReadOnlyClient) (TransitionFunc, error)
 	return nil, nil
 }
 
-func (s *BaseStateMachine) GetTransition(client ReadOnlyClient) (TransitionFunc, cephv1alpha1.CephDaemonClusterState) {
+func (s *BaseStateMachine) GetTransition(client ReadOnlyClient) (TransitionFunc, cephv1beta1.CephDaemonClusterState) {
 
 	if !s.daemonClusterEnabled() {
-		return nil, cephv1alpha1.CephDaemonClusterStateIdle
+		return nil, cephv1beta1.CephDaemonClusterStateIdle
 	}
 
 	switch s.State() {
-	case cephv1alpha1.CephDaemonClusterStateIdle:
+	case cephv1beta1.CephDaemonClusterStateIdle:
 		if s.daemonClusterEnabled() {
-			return nil, cephv1alpha1.CephDaemonClusterStateRunning
+			return nil, cephv1beta1.CephDaemonClusterStateRunning
 		}
-	case cephv1alpha1.CephDaemonClusterStateRunning:
+	case cephv1beta1.CephDaemonClusterStateRunning:
 		correctCount, err := s.correctReplicaCount(client)
 		if err != nil {
-			return s.emitError(err), cephv1alpha1.CephDaemonClusterStateError
+			return s.emitError(err), cephv1beta1.CephDaemonClusterStateError
 		}
 
 		if !correctCount {
-			return nil, cephv1alpha1.CephDaemonClusterStateScaling
+			return nil, cephv1beta1.CephDaemonClusterStateScaling
 		}
 
-	case cephv1alpha1.CephDaemonClusterStateScaling:
+	case cephv1beta1.CephDaemonClusterStateScaling:
 		scaleFunc, err := s.scale(client)
 		if err != nil {
-			return s.emitError(err), cephv1alpha1.CephDaemonClusterStateError
+			return s.emitError(err), cephv1beta1.CephDaemonClusterStateError
 		}
 
 		if scaleFunc != nil {
-			return scaleFunc, cephv1alpha1.CephDaemonClusterStateScaling
+			return scaleFunc, cephv1beta1.CephDaemonClusterStateScaling
 		}
-		return nil, cephv1alpha1.CephDaemonClusterStateRunning
+		return nil, cephv1beta1.CephDaemonClusterStateRunning
 
-	case cephv1alpha1.CephDaemonClusterStateError:
-		return nil, cephv1alpha1.CephDaemonClusterStateRunning
+	case cephv1beta1.CephDaemonClusterStateError:
+		return nil, cephv1beta1.CephDaemonClusterStateRunning
 
 	default:
-		return nil, cephv1alpha1.CephDaemonClusterStateIdle
+		return nil, cephv1beta1.CephDaemonClusterStateIdle
 	}
 
 	return nil, s.State()
@@ -194,7 +195,7 @@ type MgrStateMachine struct {
 	*BaseStateMachine
 }
 
-func (s *MgrStateMachine) GetTransition(client ReadOnlyClient) (TransitionFunc, cephv1alpha1.CephDaemonClusterState) {
+func (s *MgrStateMachine) GetTransition(client ReadOnlyClient) (TransitionFunc, cephv1beta1.CephDaemonClusterState) {
 
 	switch s.State() {
 	default:
@@ -206,7 +207,7 @@ type MdsStateMachine struct {
 	*BaseStateMachine
 }
 
-func (s *MdsStateMachine) GetTransition(client ReadOnlyClient) (TransitionFunc, cephv1alpha1.CephDaemonClusterState) {
+func (s *MdsStateMachine) GetTransition(client ReadOnlyClient) (TransitionFunc, cephv1beta1.CephDaemonClusterState) {
 
 	switch s.State() {
 	default:
@@ -5,10 +5,11 @@ import (
 	"fmt"
 	"net"
 
-	cephv1alpha1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1alpha1"
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
 	"github.com/PolarGeospatialCenter/ceph-operator/pkg/controller/common"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -42,7 +43,7 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	}
 
 	// Watch for changes to primary resource CephMon
-	err = c.Watch(&source.Kind{Type: &cephv1alpha1.CephMon{}}, &handler.EnqueueRequestForObject{})
+	err = c.Watch(&source.Kind{Type: &cephv1beta1.CephMon{}}, &handler.EnqueueRequestForObject{}, common.WatchedNamespace)
 	if err != nil {
 		return err
 	}
@@ -51,15 +52,15 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	// Watch for changes to secondary resource Pods and requeue the owner CephMon
 	err = c.Watch(&source.Kind{Type: &corev1.Pod{}}, &handler.EnqueueRequestForOwner{
 		IsController: false,
-		OwnerType:    &cephv1alpha1.CephMon{},
-	})
+		OwnerType:    &cephv1beta1.CephMon{},
+	}, common.WatchedNamespace)
 	if err != nil {
 		return err
 	}
 
-	err = c.Watch(&source.Kind{Type: &cephv1alpha1.CephMonCluster{}}, &handler.EnqueueRequestsFromMapFunc{
+	err = c.Watch(&source.Kind{Type: &cephv1beta1.CephMonCluster{}}, &handler.EnqueueRequestsFromMapFunc{
 		ToRequests: &MonClusterEventMapper{client: mgr.GetClient(), scheme: mgr.GetScheme()},
-	})
+	}, common.WatchedNamespace)
 	if err != nil {
 		return err
 	}
@@ -89,7 +90,7 @@ func (r *ReconcileCephMon) Reconcile(request reconcile.Request) (reconcile.Resul
 	reqLogger.Info("Reconciling CephMon")
 
 	// Fetch the CephMon instance
-	instance := &cephv1alpha1.CephMon{}
+	instance := &cephv1beta1.CephMon{}
 	err := r.client.Get(context.TODO(), request.NamespacedName, instance)
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -107,17 +108,17 @@ func (r *ReconcileCephMon) Reconcile(request reconcile.Request) (reconcile.Resul
 	if monLabels == nil {
 		monLabels = make(map[string]string)
 	}
-	if val, ok := monLabels[cephv1alpha1.ClusterNameLabel]; !ok || val != instance.Spec.ClusterName {
-		monLabels[cephv1alpha1.ClusterNameLabel] = instance.Spec.ClusterName
+	if val, ok := monLabels[cephv1beta1.ClusterNameLabel]; !ok || val != instance.Spec.ClusterName {
+		monLabels[cephv1beta1.ClusterNameLabel] = instance.Spec.ClusterName
 		instance.SetLabels(monLabels)
 		return r.updateAndRequeue(instance)
 	}
 
 	// Lookup monCluster
-	monClusterList := &cephv1alpha1.CephMonClusterList{}
-	monClusterListOptions := &client.ListOptions{}
+	monClusterList := &cephv1beta1.CephMonClusterList{}
+	monClusterListOptions := &client.ListOptions{Namespace: instance.GetNamespace()}
 	monClusterListOptions.MatchingLabels(map[string]string{
-		cephv1alpha1.ClusterNameLabel: instance.Spec.ClusterName,
+		cephv1beta1.ClusterNameLabel: instance.Spec.ClusterName,
 	})
 	err = r.client.List(context.TODO(), monClusterListOptions, monClusterList)
 	if err != nil {
@@ -136,11 +137,25 @@ func (r *ReconcileCephMon) Reconcile(request reconcile.Request) (reconcile.Resul
 
 	monCluster := &monClusterList.Items[0]
 
+	adopted, err := r.adoptLegacyPod(instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if adopted {
+		return r.updateAndRequeue(instance)
+	}
+
+	// Track how long we have been out of quorum while the rest of the cluster has quorum
+	if updateOutOfQuorumSince(instance, monCluster) {
+		return r.updateAndRequeue(instance)
+	}
+
 	// Check for disabled or lost quorum states
-	if (instance.GetDisabled() || monCluster.CheckMonClusterState(cephv1alpha1.MonClusterLostQuorum, cephv1alpha1.MonClusterIdle)) &&
-		!instance.CheckMonState(cephv1alpha1.MonCleanup, cephv1alpha1.MonIdle) {
+	if (instance.GetDisabled() || monCluster.CheckMonClusterState(cephv1beta1.MonClusterLostQuorum, cephv1beta1.MonClusterIdle,
+		cephv1beta1.MonClusterRecovering)) &&
+		!instance.CheckMonState(cephv1beta1.MonCleanup, cephv1beta1.MonIdle) {
 
-		instance.Status.State = cephv1alpha1.MonCleanup
+		instance.Status.State = cephv1beta1.MonCleanup
 		_, err = r.updateAndRequeue(instance)
 		if err != nil {
 			return reconcile.Result{}, err
@@ -148,12 +163,12 @@ func (r *ReconcileCephMon) Reconcile(request reconcile.Request) (reconcile.Resul
 	}
 
 	switch instance.GetMonState() {
-	case cephv1alpha1.MonError:
+	case cephv1beta1.MonError:
 		log.Info("Monitor is in error state, cleaning up", "MonitorID", instance.Spec.ID)
-		instance.Status.State = cephv1alpha1.MonCleanup
+		instance.Status.State = cephv1beta1.MonCleanup
 		return r.updateAndRequeue(instance)
 
-	case cephv1alpha1.MonCleanup:
+	case cephv1beta1.MonCleanup:
 		pod := &corev1.Pod{}
 		pod.Namespace = request.Namespace
 		pod.Name = instance.GetPodName()
@@ -162,20 +177,32 @@ func (r *ReconcileCephMon) Reconcile(request reconcile.Request) (reconcile.Resul
 			return reconcile.Result{}, err
 		}
 
-		instance.Status.State = cephv1alpha1.MonIdle
+		// The relaunched pod mounts the same store, wait for an adopted pod to be gone before it's launched
+		if instance.Status.LegacyPodName != "" {
+			err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}, pod)
+			if err == nil {
+				return reconcile.Result{Requeue: true}, nil
+			}
+			if !errors.IsNotFound(err) {
+				return reconcile.Result{}, err
+			}
+			instance.Status.LegacyPodName = ""
+		}
+
+		instance.Status.State = cephv1beta1.MonIdle
 		return r.updateAndRequeue(instance)
 
-	case cephv1alpha1.MonIdle:
+	case cephv1beta1.MonIdle:
 		if instance.GetDisabled() {
 			return reconcile.Result{}, nil
 		}
 		switch monCluster.GetMonClusterState() {
-		case cephv1alpha1.MonClusterInQuorum:
-			instance.Status.State = cephv1alpha1.MonLaunchPod
+		case cephv1beta1.MonClusterInQuorum:
+			instance.Status.State = cephv1beta1.MonLaunchPod
 
-		case cephv1alpha1.MonClusterLaunching:
-			if instance.Status.InitalMember {
-				instance.Status.State = cephv1alpha1.MonLaunchPod
+		case cephv1beta1.MonClusterLaunching:
+			if instance.Status.InitialMember {
+				instance.Status.State = cephv1beta1.MonLaunchPod
 			}
 
 		default:
@@ -189,14 +216,22 @@ func (r *ReconcileCephMon) Reconcile(request reconcile.Request) (reconcile.Resul
 
 		return reconcile.Result{Requeue: true}, nil
 
-	case cephv1alpha1.MonLaunchPod:
-		if !monCluster.CheckMonClusterState(cephv1alpha1.MonClusterInQuorum,
-			cephv1alpha1.MonClusterEstablishingQuorum,
-			cephv1alpha1.MonClusterLaunching) {
+	case cephv1beta1.MonLaunchPod:
+		if !monCluster.CheckMonClusterState(cephv1beta1.MonClusterInQuorum,
+			cephv1beta1.MonClusterEstablishingQuorum,
+			cephv1beta1.MonClusterLaunching) {
 			log.Info("Refusing to launch monitor while cluster is unexpected state",
 				"ClusterState", monCluster.GetMonClusterState(), "MonitorId", instance.Spec.ID)
 			return reconcile.Result{}, nil
 		}
+		// Create Service
+		if monCluster.Spec.Network.UsesMonServices() {
+			ready, err := r.ensureService(instance)
+			if !ready || err != nil {
+				return reconcile.Result{Requeue: !ready}, err
+			}
+		}
+
 		// Create PVC
 		pvc, err := instance.GetVolumeClaimTemplate()
 		if err != nil {
@@ -205,18 +240,22 @@ func (r *ReconcileCephMon) Reconcile(request reconcile.Request) (reconcile.Resul
 		pvc.Namespace = request.Namespace
 		common.UpdateOwnerReferences(instance, pvc)
 
-		err = r.client.Create(context.TODO(), pvc)
+		cluster, err := r.getCephCluster(instance)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
 
-		if err != nil && !errors.IsAlreadyExists(err) {
+		err = common.EnsureVolumeClaim(r.client, pvc, instance, instance.Spec.ReclaimPolicy.Or(cluster.GetReclaimPolicy()), cluster)
+		if err != nil {
 			return reconcile.Result{}, err
 		}
 
 		// Create Pod
 		adminKeyringSecretList := &corev1.SecretList{}
-		listOptions := &client.ListOptions{}
+		listOptions := &client.ListOptions{Namespace: instance.GetNamespace()}
 		listOptions.MatchingLabels(map[string]string{
-			cephv1alpha1.ClusterNameLabel:   instance.Spec.ClusterName,
-			cephv1alpha1.KeyringEntityLabel: "client.admin",
+			cephv1beta1.ClusterNameLabel:   instance.Spec.ClusterName,
+			cephv1beta1.KeyringEntityLabel: "client.admin",
 		})
 
 		err = r.client.List(context.TODO(), listOptions, adminKeyringSecretList)
@@ -229,6 +268,10 @@ func (r *ReconcileCephMon) Reconcile(request reconcile.Request) (reconcile.Resul
 
 		pod := instance.GetPod(monCluster, adminKeyringSecretList.Items[0].GetName())
 		pod.Namespace = request.Namespace
+		err = cluster.ApplyPodOverrides(cephv1beta1.CephDaemonTypeMon, &pod.Spec)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
 		common.UpdateOwnerReferences(instance, pod)
 
 		err = r.client.Create(context.TODO(), pod)
@@ -237,68 +280,160 @@ func (r *ReconcileCephMon) Reconcile(request reconcile.Request) (reconcile.Resul
 		}
 
 		switch monCluster.GetMonClusterState() {
-		case cephv1alpha1.MonClusterInQuorum:
-			instance.Status.State = cephv1alpha1.MonWaitForPodReady
-		case cephv1alpha1.MonClusterLaunching:
-			instance.Status.State = cephv1alpha1.MonWaitForPodRun
-		case cephv1alpha1.MonClusterEstablishingQuorum:
-			instance.Status.State = cephv1alpha1.MonWaitForPodRun
+		case cephv1beta1.MonClusterInQuorum:
+			instance.Status.State = cephv1beta1.MonWaitForPodReady
+		case cephv1beta1.MonClusterLaunching:
+			instance.Status.State = cephv1beta1.MonWaitForPodRun
+		case cephv1beta1.MonClusterEstablishingQuorum:
+			instance.Status.State = cephv1beta1.MonWaitForPodRun
 		}
 		return r.updateAndRequeue(instance)
 
-	case cephv1alpha1.MonWaitForPodRun:
-		running, podIP, err := r.checkPod(instance.GetPodName(), instance.GetNamespace(), podRunning)
+	case cephv1beta1.MonWaitForPodRun:
+		running, podIP, err := r.checkPod(instance.GetPodName(), instance.GetNamespace(), monCluster.Spec.Network, podRunning)
 		if errors.IsNotFound(err) {
-			instance.Status.State = cephv1alpha1.MonError
+			instance.Status.State = cephv1beta1.MonError
 			return r.updateAndRequeue(instance)
 		}
 		if !running || err != nil {
 			return reconcile.Result{}, err
 		}
 
-		instance.Status.State = cephv1alpha1.MonWaitForPodReady
-		instance.Status.PodIP = podIP
+		instance.Status.State = cephv1beta1.MonWaitForPodReady
+		instance.Status.PodIP = podIP.String()
 		instance.Status.StartEpoch = monCluster.Status.StartEpoch
 		return r.updateAndRequeue(instance)
 
-	case cephv1alpha1.MonWaitForPodReady:
-		quorum, podIP, err := r.checkPod(instance.GetPodName(), instance.GetNamespace(), podInQuorum)
+	case cephv1beta1.MonWaitForPodReady:
+		quorum, podIP, err := r.checkPod(instance.GetPodName(), instance.GetNamespace(), monCluster.Spec.Network, podInQuorum)
 		if errors.IsNotFound(err) {
-			instance.Status.State = cephv1alpha1.MonError
+			instance.Status.State = cephv1beta1.MonError
 			return r.updateAndRequeue(instance)
 		}
 		if !quorum || err != nil {
 			return reconcile.Result{}, err
 		}
 
-		instance.Status.State = cephv1alpha1.MonInQuorum
-		instance.Status.PodIP = podIP
+		instance.Status.State = cephv1beta1.MonInQuorum
+		instance.Status.PodIP = podIP.String()
 		instance.Status.StartEpoch = monCluster.Status.StartEpoch
-		instance.Status.InitalMember = true
+		instance.Status.InitialMember = true
 		return r.updateAndRequeue(instance)
 
-	case cephv1alpha1.MonInQuorum:
-		quorum, _, err := r.checkPod(instance.GetPodName(), instance.Namespace, podInQuorum)
+	case cephv1beta1.MonInQuorum:
+		quorum, _, err := r.checkPod(instance.GetPodName(), instance.Namespace, monCluster.Spec.Network, podInQuorum)
 		if errors.IsNotFound(err) {
-			instance.Status.State = cephv1alpha1.MonError
+			instance.Status.State = cephv1beta1.MonError
 			return r.updateAndRequeue(instance)
 		}
 		if quorum || err != nil {
 			return reconcile.Result{}, err
 		}
 		// out of quorum with no error
-		instance.Status.State = cephv1alpha1.MonCleanup
+		instance.Status.State = cephv1beta1.MonCleanup
 		return r.updateAndRequeue(instance)
 
 	default:
-		instance.Status.State = cephv1alpha1.MonCleanup
+		instance.Status.State = cephv1beta1.MonCleanup
 		return r.updateAndRequeue(instance)
 	}
 }
 
+// updateOutOfQuorumSince starts or clears the out of quorum timer, returning true if the status changed
+func updateOutOfQuorumSince(mon *cephv1beta1.CephMon, monCluster *cephv1beta1.CephMonCluster) bool {
+	outOfQuorum := monCluster.CheckMonClusterState(cephv1beta1.MonClusterInQuorum) &&
+		!mon.CheckMonState(cephv1beta1.MonInQuorum) && !mon.GetDisabled()
+
+	if outOfQuorum && mon.Status.OutOfQuorumSince == nil {
+		now := metav1.Now()
+		mon.Status.OutOfQuorumSince = &now
+		return true
+	}
+
+	if !outOfQuorum && mon.Status.OutOfQuorumSince != nil {
+		mon.Status.OutOfQuorumSince = nil
+		return true
+	}
+
+	return false
+}
+
+// adoptLegacyPod records a pod of this monitor launched under the name used before pod names included the
+// cluster name, so it's checked and cleaned up instead of a second pod being launched on the same store.
+// Returns true if the status changed.
+func (r *ReconcileCephMon) adoptLegacyPod(instance *cephv1beta1.CephMon) (bool, error) {
+	if instance.Status.LegacyPodName != "" || instance.GetLegacyPodName() == instance.GetPodName() {
+		return false, nil
+	}
+
+	pod := &corev1.Pod{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: instance.GetNamespace(), Name: instance.GetLegacyPodName()}, pod)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	for _, ref := range pod.GetOwnerReferences() {
+		if ref.UID == instance.GetUID() {
+			log.Info("Adopting monitor pod with a legacy name", "MonitorID", instance.Spec.ID, "Pod", pod.GetName())
+			instance.Status.LegacyPodName = pod.GetName()
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ensureService creates the monitor's service and records its address.  It returns true once the
+// recorded address matches the service.
+func (r *ReconcileCephMon) ensureService(instance *cephv1beta1.CephMon) (bool, error) {
+	svc := instance.GetService()
+	svc.Namespace = instance.GetNamespace()
+	common.UpdateOwnerReferences(instance, svc)
+
+	err := r.client.Create(context.TODO(), svc)
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return false, err
+	}
+
+	err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: svc.Namespace, Name: svc.Name}, svc)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	serviceIP := net.ParseIP(svc.Spec.ClusterIP)
+	if serviceIP == nil {
+		return false, nil
+	}
+
+	if !serviceIP.Equal(net.ParseIP(instance.Status.ServiceIP)) {
+		if migratesAddress(instance, serviceIP) {
+			log.Info("Migrating monitor address to its service, the monitor's monmap entry changes to the new address",
+				"MonitorID", instance.Spec.ID, "OldAddress", instance.GetAddress(), "NewAddress", serviceIP)
+		} else {
+			log.Info("Monitor service address assigned", "MonitorID", instance.Spec.ID, "ServiceIP", serviceIP)
+		}
+		instance.Status.ServiceIP = serviceIP.String()
+		_, err = r.updateAndRequeue(instance)
+		return false, err
+	}
+
+	return true, nil
+}
+
+// migratesAddress returns true if the monitor is already known by a different address than the service address
+func migratesAddress(mon *cephv1beta1.CephMon, serviceIP net.IP) bool {
+	address := mon.GetAddress()
+	return address != nil && !address.Equal(serviceIP)
+}
+
 type podCheckFunc func(*corev1.Pod) bool
 
-func (r *ReconcileCephMon) checkPod(podName, namespace string, checkFunc podCheckFunc) (bool, net.IP, error) {
+func (r *ReconcileCephMon) checkPod(podName, namespace string, network cephv1beta1.NetworkSpec, checkFunc podCheckFunc) (bool, net.IP, error) {
 	pod := &corev1.Pod{}
 	err := r.client.Get(context.TODO(), types.NamespacedName{
 		Name:      podName,
@@ -307,14 +442,22 @@ func (r *ReconcileCephMon) checkPod(podName, namespace string, checkFunc podChec
 	if err != nil {
 		return false, net.IP{}, err
 	}
-	podIP := net.ParseIP(pod.Status.PodIP)
 
-	return checkFunc(pod), podIP, nil
+	if !checkFunc(pod) {
+		return false, net.IP{}, nil
+	}
+
+	podIP, err := network.GetPodIP(pod)
+	if err != nil {
+		return false, net.IP{}, err
+	}
+
+	return true, podIP, nil
 }
 
 func podInQuorum(pod *corev1.Pod) bool {
 	for _, status := range pod.Status.Conditions {
-		if status.Type == cephv1alpha1.MonQuorumPodCondition {
+		if status.Type == cephv1beta1.MonQuorumPodCondition {
 			return status.Status == corev1.ConditionTrue
 		}
 	}
@@ -346,3 +489,13 @@ func (r *ReconcileCephMon) createOrUpdate(object runtime.Object) (reconcile.Resu
 
 	return reconcile.Result{}, nil
 }
+
+func (r *ReconcileCephMon) getCephCluster(m *cephv1beta1.CephMon) (*cephv1beta1.CephCluster, error) {
+	cephCluster := &cephv1beta1.CephCluster{}
+	cephClusterNamespacedName := types.NamespacedName{
+		Name:      m.Spec.ClusterName,
+		Namespace: m.GetNamespace(),
+	}
+
+	return cephCluster, r.client.Get(context.TODO(), cephClusterNamespacedName, cephCluster)
+}
@@ -0,0 +1,158 @@
+package cephmon
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/apis"
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/controller/common"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func init() {
+	if err := apis.AddToScheme(scheme.Scheme); err != nil {
+		panic(err)
+	}
+}
+
+func newTestMon(podIP, serviceIP string) *cephv1beta1.CephMon {
+	mon := cephv1beta1.NewCephMon("test", nil)
+	mon.Spec.ID = "a"
+	mon.Name = "test-mon-a"
+	mon.Namespace = "ceph"
+	mon.Status.PodIP = podIP
+	mon.Status.ServiceIP = serviceIP
+	return mon
+}
+
+func TestMigratesAddress(t *testing.T) {
+	tests := map[string]struct {
+		PodIP     string
+		ServiceIP string
+		Expected  bool
+	}{
+		"new-monitor":      {Expected: false},
+		"pod-address":      {PodIP: "10.0.0.1", Expected: true},
+		"service-address":  {PodIP: "10.0.0.1", ServiceIP: "10.96.0.10", Expected: false},
+		"replaced-service": {PodIP: "10.0.0.1", ServiceIP: "10.96.0.11", Expected: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mon := newTestMon(test.PodIP, test.ServiceIP)
+			if migrates := migratesAddress(mon, net.ParseIP("10.96.0.10")); migrates != test.Expected {
+				t.Errorf("expected %t, got %t", test.Expected, migrates)
+			}
+		})
+	}
+}
+
+func TestEnsureServiceMigratesAddress(t *testing.T) {
+	mon := newTestMon("10.0.0.1", "")
+
+	svc := mon.GetService()
+	svc.Namespace = mon.GetNamespace()
+	svc.Spec.ClusterIP = "10.96.0.10"
+
+	r := &ReconcileCephMon{client: fake.NewFakeClient(mon, svc), scheme: scheme.Scheme}
+
+	ready, err := r.ensureService(mon)
+	if err != nil {
+		t.Fatalf("unable to ensure service: %v", err)
+	}
+	if ready {
+		t.Errorf("expected the service not to be ready until its address is recorded")
+	}
+
+	if entry := mon.GetMonMapEntry(); !entry.IP.Equal(net.ParseIP("10.96.0.10")) {
+		t.Errorf("expected the monmap entry to use the service address, got %s", entry.IP)
+	}
+
+	ready, err = r.ensureService(mon)
+	if err != nil || !ready {
+		t.Errorf("expected the service to be ready once its address is recorded: %v", err)
+	}
+}
+
+func TestLegacyPodAdopted(t *testing.T) {
+	// A monitor created before the operator named them, with the pod launched by the baseline operator
+	mon := newTestMon("10.0.0.1", "")
+	mon.Name = "mon-a"
+	mon.UID = "mon-uid"
+	mon.APIVersion = cephv1beta1.SchemeGroupVersion.String()
+	mon.Kind = "CephMon"
+	mon.Labels = map[string]string{cephv1beta1.ClusterNameLabel: "test"}
+	mon.Status.State = cephv1beta1.MonInQuorum
+
+	monCluster := &cephv1beta1.CephMonCluster{}
+	monCluster.Name = "test"
+	monCluster.Namespace = "ceph"
+	monCluster.Labels = map[string]string{cephv1beta1.ClusterNameLabel: "test"}
+	monCluster.Spec.ClusterName = "test"
+	monCluster.Status.State = cephv1beta1.MonClusterInQuorum
+
+	pod := &corev1.Pod{}
+	pod.Name = "ceph-mon-a"
+	pod.Namespace = "ceph"
+	pod.Labels = map[string]string{cephv1beta1.MonitorServiceLabel: ""}
+	pod.Status.Phase = corev1.PodRunning
+	pod.Status.PodIP = "10.0.0.1"
+	pod.Status.Conditions = []corev1.PodCondition{{Type: cephv1beta1.MonQuorumPodCondition, Status: corev1.ConditionTrue}}
+	common.UpdateOwnerReferences(mon, pod)
+
+	r := &ReconcileCephMon{client: fake.NewFakeClient(mon, monCluster, pod), scheme: scheme.Scheme}
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ceph", Name: "mon-a"}}
+	getMon := func() *cephv1beta1.CephMon {
+		mon := &cephv1beta1.CephMon{}
+		if err := r.client.Get(context.TODO(), request.NamespacedName, mon); err != nil {
+			t.Fatal(err)
+		}
+		return mon
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := r.Reconcile(request); err != nil {
+			t.Fatalf("reconcile failed: %v", err)
+		}
+	}
+
+	mon = getMon()
+	if mon.GetPodName() != "ceph-mon-a" {
+		t.Errorf("expected the legacy pod to be adopted, pod name is %s", mon.GetPodName())
+	}
+	if mon.Status.State != cephv1beta1.MonInQuorum {
+		t.Errorf("expected the monitor to stay in quorum, got %s", mon.Status.State)
+	}
+
+	// Once cleaned up, the monitor is relaunched under its current name
+	mon.Status.State = cephv1beta1.MonCleanup
+	if err := r.client.Update(context.TODO(), mon); err != nil {
+		t.Fatal(err)
+	}
+	// The first reconcile starts the out of quorum timer
+	for i := 0; i < 2; i++ {
+		if _, err := r.Reconcile(request); err != nil {
+			t.Fatalf("reconcile failed: %v", err)
+		}
+	}
+
+	err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: "ceph", Name: "ceph-mon-a"}, &corev1.Pod{})
+	if !errors.IsNotFound(err) {
+		t.Errorf("expected the legacy pod to be deleted, got %v", err)
+	}
+
+	mon = getMon()
+	if mon.Status.State != cephv1beta1.MonIdle {
+		t.Errorf("expected the monitor to be idle, got %s", mon.Status.State)
+	}
+	if mon.GetPodName() != "ceph-test-mon-a" {
+		t.Errorf("expected the monitor to be relaunched as ceph-test-mon-a, got %s", mon.GetPodName())
+	}
+}
@@ -3,7 +3,7 @@ package cephmon
 import (
 	"context"
 
-	cephv1alpha1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1alpha1"
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -25,11 +25,11 @@ func (m *MonClusterEventMapper) Map(o handler.MapObject) []reconcile.Request {
 	req := make([]reconcile.Request, 0, 1)
 	switch obj := o.Object.(type) {
 
-	case *cephv1alpha1.CephMonCluster:
+	case *cephv1beta1.CephMonCluster:
 
-		monitors := &cephv1alpha1.CephMonList{}
-		listOptions := &client.ListOptions{}
-		listOptions.MatchingLabels(map[string]string{cephv1alpha1.ClusterNameLabel: obj.Spec.ClusterName})
+		monitors := &cephv1beta1.CephMonList{}
+		listOptions := &client.ListOptions{Namespace: obj.GetNamespace()}
+		listOptions.MatchingLabels(map[string]string{cephv1beta1.ClusterNameLabel: obj.Spec.ClusterName})
 
 		err := m.client.List(context.TODO(), listOptions, monitors)
 		if err != nil {
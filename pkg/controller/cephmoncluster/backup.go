@@ -0,0 +1,381 @@
+package cephmoncluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/controller/common"
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/s3"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// backup starts a backup of an in quorum monitor's store when one is due.  The monitor is stopped while its
+// store is copied, so a backup is only started if the remaining monitors keep quorum.
+func (r *ReconcileCephMonCluster) backup(instance *cephv1beta1.CephMonCluster, monMap cephv1beta1.MonMap) (reconcile.Result, error) {
+	spec := instance.Spec.Backup
+	active := instance.Status.ActiveBackup
+	if spec == nil && active == nil {
+		return reconcile.Result{}, nil
+	}
+
+	jobs := &batchv1.JobList{}
+	listOptions := &client.ListOptions{Namespace: instance.GetNamespace()}
+	listOptions.MatchingLabels(map[string]string{cephv1beta1.ClusterNameLabel: instance.GetCephClusterName()})
+	err := r.client.List(context.TODO(), listOptions, jobs)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	var activeJob *batchv1.Job
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		if name, ok := job.GetLabels()[cephv1beta1.MonBackupPruneLabel]; ok {
+			err = r.cleanupPruneJob(job, name)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+			continue
+		}
+
+		name, ok := job.GetLabels()[cephv1beta1.MonBackupLabel]
+		if !ok {
+			continue
+		}
+
+		if active != nil && name == active.Name {
+			activeJob = job
+			continue
+		}
+
+		// A backup job that is no longer tracked, left behind by an earlier failure
+		if common.JobHasCondition(job, batchv1.JobComplete) && spec != nil {
+			err = r.recordBackup(instance, job, name)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		err = r.deleteJob(job)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	if active != nil {
+		return r.continueBackup(instance, monMap, activeJob)
+	}
+
+	now := time.Now()
+	if last := instance.Status.LastBackupTime; last != nil {
+		next := last.Add(spec.GetInterval())
+		if now.Before(next) {
+			return reconcile.Result{RequeueAfter: next.Sub(now)}, nil
+		}
+	}
+
+	mon, nodeName, err := r.getBackupSource(monMap)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if !monMap.CanRemove(mon.Spec.ID) {
+		log.Info("Skipping monitor backup, the remaining monitors would not have quorum while it is stopped",
+			"MonitorID", mon.Spec.ID)
+		return reconcile.Result{RequeueAfter: spec.GetInterval()}, nil
+	}
+
+	backupName := fmt.Sprintf("%s-mon-backup-%d", instance.GetCephClusterName(), now.Unix())
+	log.Info("Starting monitor backup", "Backup", backupName, "MonitorID", mon.Spec.ID)
+	instance.Status.ActiveBackup = &cephv1beta1.MonActiveBackup{Name: backupName, MonID: mon.Spec.ID, NodeName: nodeName}
+	instance.Status.LastBackupTime = &metav1.Time{Time: now}
+	_, err = r.updateAndRequeue(instance)
+	return reconcile.Result{RequeueAfter: spec.GetInterval()}, err
+}
+
+// continueBackup stops the source monitor, copies its store once it's stopped and starts it again when the
+// copy is finished
+func (r *ReconcileCephMonCluster) continueBackup(instance *cephv1beta1.CephMonCluster, monMap cephv1beta1.MonMap,
+	job *batchv1.Job) (reconcile.Result, error) {
+
+	active := instance.Status.ActiveBackup
+
+	if job != nil {
+		switch {
+		case common.JobHasCondition(job, batchv1.JobComplete):
+			if instance.Spec.Backup != nil {
+				err := r.recordBackup(instance, job, active.Name)
+				if err != nil {
+					return reconcile.Result{}, err
+				}
+			}
+
+		case common.JobHasCondition(job, batchv1.JobFailed):
+			log.Info("Monitor backup failed, retrying at the next interval", "Backup", active.Name, "Job", job.GetName())
+
+		default:
+			// Only one backup runs at a time
+			return reconcile.Result{}, nil
+		}
+
+		err := r.finishBackup(instance, monMap)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, r.deleteJob(job)
+	}
+
+	if _, recorded := instance.GetBackup(active.Name); recorded || instance.Spec.Backup == nil {
+		return reconcile.Result{}, r.finishBackup(instance, monMap)
+	}
+
+	entry, ok := monMap[active.MonID]
+	if !ok {
+		log.Info("Monitor backup source no longer exists, abandoning backup", "Backup", active.Name, "MonitorID", active.MonID)
+		return reconcile.Result{}, r.finishBackup(instance, monMap)
+	}
+
+	mon := &cephv1beta1.CephMon{}
+	err := r.client.Get(context.TODO(), entry.NamespacedName, mon)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if !mon.GetDisabled() {
+		log.Info("Stopping monitor for backup", "Backup", active.Name, "MonitorID", active.MonID)
+		mon.Spec.Disabled = true
+		return r.updateAndRequeue(mon)
+	}
+
+	// Wait for the monitor's pod to be removed so the store isn't changing while it's copied
+	if !mon.CheckMonState(cephv1beta1.MonIdle) {
+		return reconcile.Result{}, nil
+	}
+
+	job = mon.GetBackupJob(instance, active.Name, active.NodeName)
+	job.Namespace = instance.GetNamespace()
+	if err := r.applyPodOverrides(instance, job); err != nil {
+		return reconcile.Result{}, err
+	}
+	if err := controllerutil.SetControllerReference(instance, job, r.scheme); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	log.Info("Copying monitor store", "Backup", active.Name, "MonitorID", active.MonID)
+	err = r.client.Create(context.TODO(), job)
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
+
+// finishBackup starts the source monitor again and clears the active backup
+func (r *ReconcileCephMonCluster) finishBackup(instance *cephv1beta1.CephMonCluster, monMap cephv1beta1.MonMap) error {
+	active := instance.Status.ActiveBackup
+
+	if entry, ok := monMap[active.MonID]; ok {
+		mon := &cephv1beta1.CephMon{}
+		err := r.client.Get(context.TODO(), entry.NamespacedName, mon)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+
+		if err == nil && mon.GetDisabled() {
+			log.Info("Starting monitor after backup", "Backup", active.Name, "MonitorID", active.MonID)
+			mon.Spec.Disabled = false
+			_, err = r.updateAndRequeue(mon)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	instance.Status.ActiveBackup = nil
+	_, err := r.updateAndRequeue(instance)
+	return err
+}
+
+// abandonBackup deletes the job of the backup in progress and starts its source monitor again
+func (r *ReconcileCephMonCluster) abandonBackup(instance *cephv1beta1.CephMonCluster, monMap cephv1beta1.MonMap) error {
+	jobs := &batchv1.JobList{}
+	listOptions := &client.ListOptions{Namespace: instance.GetNamespace()}
+	listOptions.MatchingLabels(map[string]string{cephv1beta1.MonBackupLabel: instance.Status.ActiveBackup.Name})
+	err := r.client.List(context.TODO(), listOptions, jobs)
+	if err != nil {
+		return err
+	}
+
+	for i := range jobs.Items {
+		if jobs.Items[i].GetLabels()[cephv1beta1.MonBackupLabel] != instance.Status.ActiveBackup.Name {
+			continue
+		}
+		err = r.deleteJob(&jobs.Items[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return r.finishBackup(instance, monMap)
+}
+
+// recordBackup adds a completed backup to the status and deletes the backups that are no longer retained from
+// the destination
+func (r *ReconcileCephMonCluster) recordBackup(instance *cephv1beta1.CephMonCluster, job *batchv1.Job, name string) error {
+	if _, ok := instance.GetBackup(name); ok {
+		return nil
+	}
+
+	completionTime := metav1.Now()
+	if job.Status.CompletionTime != nil {
+		completionTime = *job.Status.CompletionTime
+	}
+
+	record := cephv1beta1.MonBackupRecord{
+		Name:           name,
+		MonID:          getJobEnv(job, "MON_ID"),
+		CompletionTime: completionTime,
+		Location:       instance.Spec.Backup.Destination.GetLocation(name),
+	}
+
+	log.Info("Monitor backup complete", "Backup", name, "Location", record.Location)
+	pruned := instance.AddBackup(record, instance.Spec.Backup.GetRetain())
+	err := r.pruneBackups(instance, pruned)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.updateAndRequeue(instance)
+	return err
+}
+
+// pruneBackups deletes backups from the destination.  Objects in S3 are deleted directly, backups on a volume
+// claim are deleted by a job that mounts it.
+func (r *ReconcileCephMonCluster) pruneBackups(instance *cephv1beta1.CephMonCluster, pruned []cephv1beta1.MonBackupRecord) error {
+	if len(pruned) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(pruned))
+	for _, record := range pruned {
+		log.Info("Pruning monitor backup", "Backup", record.Name, "Location", record.Location)
+		names = append(names, record.Name)
+	}
+
+	destination := instance.Spec.Backup.Destination
+	switch {
+	case destination.S3 != nil:
+		s3Client, err := r.getS3Client(instance.GetNamespace(), destination.S3)
+		if err != nil {
+			return err
+		}
+
+		for _, name := range names {
+			err = s3Client.DeleteTree(destination.S3.Bucket, destination.S3.GetS3Key(name))
+			if err != nil {
+				return fmt.Errorf("unable to prune monitor backup %s: %v", name, err)
+			}
+		}
+
+	case destination.PersistentVolumeClaim != nil:
+		job := instance.GetBackupPruneJob(names)
+		job.Namespace = instance.GetNamespace()
+		if err := controllerutil.SetControllerReference(instance, job, r.scheme); err != nil {
+			return err
+		}
+
+		err := r.client.Create(context.TODO(), job)
+		if err != nil && !errors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getS3Client returns a client for the destination using the credentials from its secret
+func (r *ReconcileCephMonCluster) getS3Client(namespace string, destination *cephv1beta1.S3BackupDestination) (*s3.Client, error) {
+	secret := &corev1.Secret{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: destination.CredentialsSecretName}, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return s3.NewClient(destination.Endpoint, destination.Region,
+		string(secret.Data["AWS_ACCESS_KEY_ID"]), string(secret.Data["AWS_SECRET_ACCESS_KEY"]))
+}
+
+// cleanupPruneJob deletes a finished prune job
+func (r *ReconcileCephMonCluster) cleanupPruneJob(job *batchv1.Job, name string) error {
+	switch {
+	case common.JobHasCondition(job, batchv1.JobComplete):
+		return r.deleteJob(job)
+
+	case common.JobHasCondition(job, batchv1.JobFailed):
+		log.Info("Pruning monitor backups failed, they must be removed from the destination by hand", "Backup", name,
+			"Job", job.GetName())
+		return r.deleteJob(job)
+	}
+	return nil
+}
+
+// getBackupSource returns an in quorum monitor and the node its pod is running on
+func (r *ReconcileCephMonCluster) getBackupSource(monMap cephv1beta1.MonMap) (*cephv1beta1.CephMon, string, error) {
+	ids := make([]string, 0, len(monMap))
+	for id, entry := range monMap {
+		if entry.State == cephv1beta1.MonInQuorum {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		mon := &cephv1beta1.CephMon{}
+		err := r.client.Get(context.TODO(), monMap[id].NamespacedName, mon)
+		if err != nil {
+			return nil, "", err
+		}
+
+		pod := &corev1.Pod{}
+		err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: mon.GetNamespace(), Name: mon.GetPodName()}, pod)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, "", err
+		}
+
+		if pod.Spec.NodeName != "" {
+			return mon, pod.Spec.NodeName, nil
+		}
+	}
+
+	return nil, "", fmt.Errorf("no scheduled monitor in quorum to back up")
+}
+
+func (r *ReconcileCephMonCluster) deleteJob(job *batchv1.Job) error {
+	err := r.client.Delete(context.TODO(), job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func getJobEnv(job *batchv1.Job, name string) string {
+	for _, container := range job.Spec.Template.Spec.Containers {
+		for _, env := range container.Env {
+			if env.Name == name {
+				return env.Value
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,209 @@
+package cephmoncluster
+
+import (
+	"context"
+	"testing"
+
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/ceph"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newTestBackupMonCluster(count int) *cephv1beta1.CephMonCluster {
+	monCluster := newTestMonCluster(count, cephv1beta1.MonClusterInQuorum)
+	monCluster.Spec.Backup = &cephv1beta1.MonBackupSpec{
+		Retain: 1,
+		Destination: cephv1beta1.MonBackupDestination{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "backups"},
+		},
+	}
+	return monCluster
+}
+
+// newTestMonWithPod returns an in quorum monitor and its pod running on node-<id>
+func newTestMonWithPod(id string) []runtime.Object {
+	mon := newTestMon(id, cephv1beta1.MonInQuorum)
+
+	pod := &corev1.Pod{}
+	pod.Name = mon.GetPodName()
+	pod.Namespace = testNamespace
+	pod.Spec.NodeName = "node-" + id
+
+	return []runtime.Object{mon, pod}
+}
+
+func getTestMon(t *testing.T, c client.Client, id string) *cephv1beta1.CephMon {
+	mon := &cephv1beta1.CephMon{}
+	err := c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: testClusterName + "-mon-" + id}, mon)
+	if err != nil {
+		t.Fatalf("unable to get monitor %s: %v", id, err)
+	}
+	return mon
+}
+
+func listTestJobs(t *testing.T, c client.Client) map[string]*batchv1.Job {
+	jobs := &batchv1.JobList{}
+	err := c.List(context.TODO(), &client.ListOptions{Namespace: testNamespace}, jobs)
+	if err != nil {
+		t.Fatalf("unable to list jobs: %v", err)
+	}
+
+	out := make(map[string]*batchv1.Job, len(jobs.Items))
+	for i := range jobs.Items {
+		out[jobs.Items[i].GetName()] = &jobs.Items[i]
+	}
+	return out
+}
+
+func TestBackupStopsSourceMon(t *testing.T) {
+	objects := []runtime.Object{newTestCluster(cephv1beta1.CephClusterRunning), newTestBackupMonCluster(3)}
+	for _, id := range []string{"a", "b", "c"} {
+		objects = append(objects, newTestMonWithPod(id)...)
+	}
+	r := newTestReconciler(&ceph.FakeRunner{}, objects...)
+
+	reconcileMonCluster(t, r)
+
+	active := getTestMonCluster(t, r).Status.ActiveBackup
+	if active == nil || active.MonID != "a" || active.NodeName != "node-a" {
+		t.Fatalf("expected a backup of a to be started, got %v", active)
+	}
+
+	reconcileMonCluster(t, r)
+
+	mon := getTestMon(t, r.client, "a")
+	if !mon.GetDisabled() {
+		t.Fatalf("expected the source monitor to be stopped")
+	}
+	if jobs := listTestJobs(t, r.client); len(jobs) != 0 {
+		t.Fatalf("expected no copy to start while the monitor is running, got %d jobs", len(jobs))
+	}
+
+	mon.Status.State = cephv1beta1.MonIdle
+	if err := r.client.Update(context.TODO(), mon); err != nil {
+		t.Fatal(err)
+	}
+
+	reconcileMonCluster(t, r)
+
+	job, ok := listTestJobs(t, r.client)[mon.GetBackupJobName(active.Name)]
+	if !ok {
+		t.Fatalf("expected the store to be copied once the monitor is stopped")
+	}
+	if job.Spec.Template.Spec.NodeName != "node-a" {
+		t.Errorf("expected the copy to run on the monitor's node, got %s", job.Spec.Template.Spec.NodeName)
+	}
+}
+
+func TestBackupSkippedWithoutSpareMon(t *testing.T) {
+	objects := append([]runtime.Object{newTestCluster(cephv1beta1.CephClusterRunning), newTestBackupMonCluster(1)},
+		newTestMonWithPod("a")...)
+	r := newTestReconciler(&ceph.FakeRunner{}, objects...)
+
+	reconcileMonCluster(t, r)
+
+	if active := getTestMonCluster(t, r).Status.ActiveBackup; active != nil {
+		t.Errorf("expected no backup while stopping the monitor would lose quorum, got %v", active)
+	}
+	if getTestMon(t, r.client, "a").GetDisabled() {
+		t.Errorf("expected the only monitor to keep running")
+	}
+}
+
+func TestBackupFinished(t *testing.T) {
+	tests := map[string]struct {
+		Condition       batchv1.JobConditionType
+		ExpectedBackups []string
+		ExpectPrune     bool
+	}{
+		"complete": {Condition: batchv1.JobComplete, ExpectedBackups: []string{"backup-2"}, ExpectPrune: true},
+		"failed":   {Condition: batchv1.JobFailed, ExpectedBackups: []string{"backup-1"}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			monCluster := newTestBackupMonCluster(3)
+			monCluster.Status.ActiveBackup = &cephv1beta1.MonActiveBackup{Name: "backup-2", MonID: "a", NodeName: "node-a"}
+			monCluster.Status.Backups = []cephv1beta1.MonBackupRecord{{Name: "backup-1", CompletionTime: metav1.Now()}}
+
+			mon := newTestMon("a", cephv1beta1.MonIdle)
+			mon.Status.InitialMember = true
+			mon.Spec.Disabled = true
+
+			job := mon.GetBackupJob(monCluster, "backup-2", "node-a")
+			job.Namespace = testNamespace
+			job.Status.Conditions = []batchv1.JobCondition{{Type: test.Condition, Status: corev1.ConditionTrue}}
+
+			r := newTestReconciler(&ceph.FakeRunner{},
+				newTestCluster(cephv1beta1.CephClusterRunning), monCluster, mon, job,
+				newTestMon("b", cephv1beta1.MonInQuorum), newTestMon("c", cephv1beta1.MonInQuorum),
+			)
+
+			reconcileMonCluster(t, r)
+
+			monCluster = getTestMonCluster(t, r)
+			if monCluster.Status.ActiveBackup != nil {
+				t.Errorf("expected the active backup to be cleared")
+			}
+
+			backups := []string{}
+			for _, record := range monCluster.Status.Backups {
+				backups = append(backups, record.Name)
+			}
+			if len(backups) != len(test.ExpectedBackups) || backups[0] != test.ExpectedBackups[0] {
+				t.Errorf("expected backups %v, got %v", test.ExpectedBackups, backups)
+			}
+
+			if getTestMon(t, r.client, "a").GetDisabled() {
+				t.Errorf("expected the source monitor to be started again")
+			}
+
+			jobs := listTestJobs(t, r.client)
+			if _, ok := jobs[job.GetName()]; ok {
+				t.Errorf("expected the backup job to be deleted")
+			}
+
+			pruneJob, pruned := jobs["ceph-backup-1-prune"]
+			if pruned != test.ExpectPrune {
+				t.Fatalf("expected prune job %t, got %t", test.ExpectPrune, pruned)
+			}
+			if pruned && pruneJob.Spec.Template.Spec.Containers[0].Command[2] != "/backup/backup-1" {
+				t.Errorf("expected the pruned backup to be removed, got %v", pruneJob.Spec.Template.Spec.Containers[0].Command)
+			}
+		})
+	}
+}
+
+func TestBackupAbandonedOnLostQuorum(t *testing.T) {
+	monCluster := newTestBackupMonCluster(3)
+	monCluster.Status.State = cephv1beta1.MonClusterLostQuorum
+	monCluster.Status.ActiveBackup = &cephv1beta1.MonActiveBackup{Name: "backup-1", MonID: "a", NodeName: "node-a"}
+
+	mon := newTestMon("a", cephv1beta1.MonIdle)
+	mon.Spec.Disabled = true
+
+	job := mon.GetBackupJob(monCluster, "backup-1", "node-a")
+	job.Namespace = testNamespace
+
+	r := newTestReconciler(&ceph.FakeRunner{}, newTestCluster(cephv1beta1.CephClusterRunning), monCluster, mon, job)
+
+	reconcileMonCluster(t, r)
+
+	if getTestMonCluster(t, r).Status.ActiveBackup != nil {
+		t.Errorf("expected the active backup to be cleared")
+	}
+	if getTestMon(t, r.client, "a").GetDisabled() {
+		t.Errorf("expected the source monitor to be started again")
+	}
+
+	err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: job.GetName()}, &batchv1.Job{})
+	if !errors.IsNotFound(err) {
+		t.Errorf("expected the backup job to be deleted: %v", err)
+	}
+}
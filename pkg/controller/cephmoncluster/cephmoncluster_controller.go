@@ -2,10 +2,13 @@ package cephmoncluster
 
 import (
 	"context"
+	"fmt"
 
-	cephv1alpha1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1alpha1"
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/ceph"
 	"github.com/PolarGeospatialCenter/ceph-operator/pkg/controller/common"
 
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -24,12 +27,20 @@ var log = logf.Log.WithName("controller_cephmoncluster")
 // Add creates a new CephMonCluster Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager) error {
-	return add(mgr, newReconciler(mgr))
+	r, err := newReconciler(mgr)
+	if err != nil {
+		return err
+	}
+	return add(mgr, r)
 }
 
 // newReconciler returns a new reconcile.Reconciler
-func newReconciler(mgr manager.Manager) reconcile.Reconciler {
-	return &ReconcileCephMonCluster{client: mgr.GetClient(), scheme: mgr.GetScheme()}
+func newReconciler(mgr manager.Manager) (reconcile.Reconciler, error) {
+	runner, err := ceph.NewPodExecRunner(mgr.GetConfig())
+	if err != nil {
+		return nil, err
+	}
+	return &ReconcileCephMonCluster{client: mgr.GetClient(), scheme: mgr.GetScheme(), runner: runner}, nil
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
@@ -41,24 +52,32 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	}
 
 	// Watch for changes to primary resource CephMonCluster
-	err = c.Watch(&source.Kind{Type: &cephv1alpha1.CephMonCluster{}}, &handler.EnqueueRequestForObject{})
+	err = c.Watch(&source.Kind{Type: &cephv1beta1.CephMonCluster{}}, &handler.EnqueueRequestForObject{}, common.WatchedNamespace)
 	if err != nil {
 		return err
 	}
 
 	// TODO(user): Modify this to be the types you create that are owned by the primary resource
 	// Watch for changes to secondary resource Pods and requeue the owner CephMonCluster
-	err = c.Watch(&source.Kind{Type: &cephv1alpha1.CephMon{}}, &handler.EnqueueRequestsFromMapFunc{
+	err = c.Watch(&source.Kind{Type: &cephv1beta1.CephMon{}}, &handler.EnqueueRequestsFromMapFunc{
 		ToRequests: &MonEventMapper{},
-	})
+	}, common.WatchedNamespace)
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(&source.Kind{Type: &batchv1.Job{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &cephv1beta1.CephMonCluster{},
+	}, common.WatchedNamespace)
 	if err != nil {
 		return err
 	}
 
-	err = c.Watch(&source.Kind{Type: &cephv1alpha1.CephCluster{}}, &handler.EnqueueRequestsFromMapFunc{
+	err = c.Watch(&source.Kind{Type: &cephv1beta1.CephCluster{}}, &handler.EnqueueRequestsFromMapFunc{
 		ToRequests: &common.CephClusterEventMapper{Client: mgr.GetClient(), Scheme: mgr.GetScheme(),
-			ApiVersion: cephv1alpha1.SchemeGroupVersion.String(), Kind: "CephMonCluster"},
-	})
+			ApiVersion: cephv1beta1.SchemeGroupVersion.String(), Kind: "CephMonCluster"},
+	}, common.WatchedNamespace)
 	if err != nil {
 		return err
 	}
@@ -74,6 +93,7 @@ type ReconcileCephMonCluster struct {
 	// that reads objects from the cache and writes to the apiserver
 	client client.Client
 	scheme *runtime.Scheme
+	runner ceph.CommandRunner
 }
 
 // Reconcile reads that state of the cluster for a CephMonCluster object and makes changes based on the state read
@@ -86,7 +106,7 @@ func (r *ReconcileCephMonCluster) Reconcile(request reconcile.Request) (reconcil
 	reqLogger.Info("Reconciling CephMonCluster")
 
 	// Fetch the CephMonCluster instance
-	instance := &cephv1alpha1.CephMonCluster{}
+	instance := &cephv1beta1.CephMonCluster{}
 	err := r.client.Get(context.TODO(), request.NamespacedName, instance)
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -104,15 +124,15 @@ func (r *ReconcileCephMonCluster) Reconcile(request reconcile.Request) (reconcil
 		return reconcile.Result{}, err
 	}
 
-	if !cephCluster.GetDaemonEnabled(cephv1alpha1.CephDaemonTypeMon) &&
-		!instance.CheckMonClusterState(cephv1alpha1.MonClusterIdle, cephv1alpha1.MonClusterLostQuorum) {
+	if !cephCluster.GetDaemonEnabled(cephv1beta1.CephDaemonTypeMon) &&
+		!instance.CheckMonClusterState(cephv1beta1.MonClusterIdle, cephv1beta1.MonClusterLostQuorum, cephv1beta1.MonClusterRecovering) {
 
-		instance.SetMonClusterState(cephv1alpha1.MonClusterLostQuorum)
+		instance.SetMonClusterState(cephv1beta1.MonClusterLostQuorum)
 		return r.updateAndRequeue(instance)
 	}
 
-	if !cephCluster.GetDaemonEnabled(cephv1alpha1.CephDaemonTypeMon) &&
-		instance.CheckMonClusterState(cephv1alpha1.MonClusterIdle) {
+	if !cephCluster.GetDaemonEnabled(cephv1beta1.CephDaemonTypeMon) &&
+		instance.CheckMonClusterState(cephv1beta1.MonClusterIdle) {
 
 		return reconcile.Result{}, nil
 	}
@@ -124,18 +144,42 @@ func (r *ReconcileCephMonCluster) Reconcile(request reconcile.Request) (reconcil
 
 	monMap := fullMonMap.GetInitalMonMap()
 
+	if inQuorum := fullMonMap.CountInState(cephv1beta1.MonInQuorum); instance.Status.InQuorum != inQuorum {
+		instance.Status.InQuorum = inQuorum
+		err = r.client.Update(context.TODO(), instance)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	if instance.RecoveryRequested() &&
+		!instance.CheckMonClusterState(cephv1beta1.MonClusterInQuorum, cephv1beta1.MonClusterRecovering) {
+
+		reqLogger.Info("Starting quorum recovery", "SurvivorID", instance.Spec.Recovery.SurvivorID, "Nonce", instance.Spec.Recovery.Nonce)
+		instance.SetMonClusterState(cephv1beta1.MonClusterRecovering)
+		return r.updateAndRequeue(instance)
+	}
+
 	switch instance.GetMonClusterState() {
 
-	case cephv1alpha1.MonClusterIdle:
+	case cephv1beta1.MonClusterIdle:
 		for _, k := range []Keyring{MON_KEYRING, CLIENT_ADMIN_KEYRING} {
-			err := r.generateKeyringSecret(k, instance.GetNamespace(), instance.Spec.ClusterName)
+			err := r.generateKeyringSecret(k, instance.GetNamespace(), cephCluster)
 			if err != nil {
 				return reconcile.Result{}, err
 			}
 		}
 
+		if instance.Spec.ExternalQuorum {
+			return r.joinExternalQuorum(instance, cephCluster, fullMonMap)
+		}
+
 		if fullMonMap.Empty() {
-			return reconcile.Result{}, nil
+			if instance.Spec.Count == 0 {
+				return reconcile.Result{}, nil
+			}
+			// Only the first monitor is created from idle, scale adds the rest once it's in quorum
+			return reconcile.Result{}, r.addMon(instance, instance.Spec.PvSelector)
 		}
 
 		cm, err := instance.GetMonMapConfigMap(monMap)
@@ -148,7 +192,7 @@ func (r *ReconcileCephMonCluster) Reconcile(request reconcile.Request) (reconcil
 			return reconcile.Result{}, err
 		}
 
-		instance.SetMonClusterState(cephv1alpha1.MonClusterLaunching)
+		instance.SetMonClusterState(cephv1beta1.MonClusterLaunching)
 		instance.Status.StartEpoch++
 
 		_, err = r.updateAndRequeue(instance)
@@ -156,22 +200,22 @@ func (r *ReconcileCephMonCluster) Reconcile(request reconcile.Request) (reconcil
 			return reconcile.Result{}, err
 		}
 
-		if fullMonMap.CountInitalMembers() > 0 {
+		if fullMonMap.CountInitialMembers() > 0 {
 			return reconcile.Result{}, nil
 		}
 
 		initialMonNamespacedName := fullMonMap.GetRandomEntry().NamespacedName
-		initialMon := &cephv1alpha1.CephMon{}
+		initialMon := &cephv1beta1.CephMon{}
 
 		err = r.client.Get(context.TODO(), initialMonNamespacedName, initialMon)
 		if err != nil {
 			return reconcile.Result{}, err
 		}
 
-		initialMon.Status.InitalMember = true
+		initialMon.Status.InitialMember = true
 		return r.updateAndRequeue(initialMon)
 
-	case cephv1alpha1.MonClusterLaunching:
+	case cephv1beta1.MonClusterLaunching:
 		if monMap.QuorumAtEpoch(instance.Status.StartEpoch) {
 			cm, err := instance.GetMonMapConfigMap(monMap)
 			cm.Namespace = instance.Namespace
@@ -183,63 +227,101 @@ func (r *ReconcileCephMonCluster) Reconcile(request reconcile.Request) (reconcil
 				return reconcile.Result{}, err
 			}
 
-			instance.SetMonClusterState(cephv1alpha1.MonClusterEstablishingQuorum)
+			instance.SetMonClusterState(cephv1beta1.MonClusterEstablishingQuorum)
 			return r.updateAndRequeue(instance)
 		}
 
 		return reconcile.Result{}, nil
 
-	case cephv1alpha1.MonClusterEstablishingQuorum:
+	case cephv1beta1.MonClusterEstablishingQuorum:
 
-		totalInQuorum := monMap.CountInState(cephv1alpha1.MonInQuorum)
+		totalInQuorum := monMap.CountInState(cephv1beta1.MonInQuorum)
 		if totalInQuorum >= monMap.QuorumCount() {
-			instance.SetMonClusterState(cephv1alpha1.MonClusterInQuorum)
+			safe, err := r.placementSafe(instance, monMap)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+
+			placement := instance.GetPlacement()
+			if !safe && placement.GetAntiAffinity() == cephv1beta1.AntiAffinityRequired && !placement.AllowUnsafe {
+				reqLogger.Info("Refusing to declare quorum while a single failure domain can break it",
+					"TopologyKey", placement.GetTopologyKey())
+				return reconcile.Result{RequeueAfter: placementRecheckInterval}, nil
+			}
+			if !safe {
+				reqLogger.Info("Declaring quorum while a single failure domain can break it",
+					"TopologyKey", placement.GetTopologyKey())
+			}
+
+			instance.SetMonClusterState(cephv1beta1.MonClusterInQuorum)
 			return r.updateAndRequeue(instance)
 		}
-		totalInIdle := monMap.CountInState(cephv1alpha1.MonIdle)
+		totalInIdle := monMap.CountInState(cephv1beta1.MonIdle)
 		if totalInIdle >= monMap.QuorumCount() {
-			instance.SetMonClusterState(cephv1alpha1.MonClusterLostQuorum)
+			instance.SetMonClusterState(cephv1beta1.MonClusterLostQuorum)
 			return r.updateAndRequeue(instance)
 		}
 
 		return reconcile.Result{}, nil
 
-	case cephv1alpha1.MonClusterInQuorum:
+	case cephv1beta1.MonClusterInQuorum:
 
-		totalInQuorum := monMap.CountInState(cephv1alpha1.MonInQuorum)
-		if totalInQuorum < monMap.QuorumCount() {
-			instance.SetMonClusterState(cephv1alpha1.MonClusterLostQuorum)
+		totalInQuorum := monMap.CountInState(cephv1beta1.MonInQuorum)
+		if totalInQuorum < monMap.QuorumCount() && !instance.Spec.ExternalQuorum {
+			instance.SetMonClusterState(cephv1beta1.MonClusterLostQuorum)
 			return r.updateAndRequeue(instance)
 		}
 
-		return reconcile.Result{}, nil
+		replaced, result, err := r.replaceFailed(instance, fullMonMap)
+		if replaced || err != nil {
+			return result, err
+		}
+
+		err = r.scale(instance, cephCluster, fullMonMap)
+		if err != nil {
+			return result, err
+		}
 
-	case cephv1alpha1.MonClusterLostQuorum:
+		backupResult, err := r.backup(instance, fullMonMap)
+		if backupResult.RequeueAfter > 0 && (result.RequeueAfter == 0 || backupResult.RequeueAfter < result.RequeueAfter) {
+			result = backupResult
+		}
+		return result, err
+
+	case cephv1beta1.MonClusterLostQuorum:
+
+		if instance.Status.ActiveBackup != nil {
+			reqLogger.Info("Abandoning monitor backup after losing quorum", "Backup", instance.Status.ActiveBackup.Name)
+			return reconcile.Result{}, r.abandonBackup(instance, fullMonMap)
+		}
 
-		if monMap.AllInState(cephv1alpha1.MonIdle) {
-			instance.SetMonClusterState(cephv1alpha1.MonClusterIdle)
+		if monMap.AllInState(cephv1beta1.MonIdle) {
+			instance.SetMonClusterState(cephv1beta1.MonClusterIdle)
 			return r.updateAndRequeue(instance)
 		}
 
 		return reconcile.Result{}, nil
 
+	case cephv1beta1.MonClusterRecovering:
+		return r.recover(instance, fullMonMap)
+
 	default:
-		instance.SetMonClusterState(cephv1alpha1.MonClusterLostQuorum)
+		instance.SetMonClusterState(cephv1beta1.MonClusterLostQuorum)
 		return r.updateAndRequeue(instance)
 	}
 }
 
-func (r *ReconcileCephMonCluster) getMonMap(instance *cephv1alpha1.CephMonCluster) (cephv1alpha1.MonMap, error) {
-	monitors := &cephv1alpha1.CephMonList{}
-	listOptions := &client.ListOptions{}
-	listOptions.MatchingLabels(map[string]string{cephv1alpha1.ClusterNameLabel: instance.Spec.ClusterName})
+func (r *ReconcileCephMonCluster) getMonMap(instance *cephv1beta1.CephMonCluster) (cephv1beta1.MonMap, error) {
+	monitors := &cephv1beta1.CephMonList{}
+	listOptions := &client.ListOptions{Namespace: instance.GetNamespace()}
+	listOptions.MatchingLabels(map[string]string{cephv1beta1.ClusterNameLabel: instance.Spec.ClusterName})
 
 	err := r.client.List(context.TODO(), listOptions, monitors)
 	if err != nil {
 		return nil, err
 	}
 
-	monMap := make(cephv1alpha1.MonMap, len(monitors.Items))
+	monMap := make(cephv1beta1.MonMap, len(monitors.Items))
 
 	for _, mon := range monitors.Items {
 		monMap[mon.Spec.ID] = mon.GetMonMapEntry()
@@ -270,11 +352,14 @@ func (r *ReconcileCephMonCluster) createOrUpdate(object runtime.Object) (reconci
 	return reconcile.Result{}, nil
 }
 
-func (r *ReconcileCephMonCluster) generateKeyringSecret(keyring Keyring, namespace, clusterName string) error {
+// generateKeyringSecret creates the keyring secret for the cluster, adopting a secret retained from an
+// earlier cluster with the same name and fsid.  The key of an imported cluster is read from the secret
+// named in the import spec.
+func (r *ReconcileCephMonCluster) generateKeyringSecret(keyring Keyring, namespace string, cluster *cephv1beta1.CephCluster) error {
 	secret := &corev1.Secret{}
 	secretNamespacedName := &types.NamespacedName{
 		Namespace: namespace,
-		Name:      keyring.GetSecretName(clusterName),
+		Name:      keyring.GetSecretName(cluster.GetName()),
 	}
 	err := r.client.Get(context.TODO(), *secretNamespacedName, secret)
 	if err != nil && !errors.IsNotFound(err) {
@@ -282,24 +367,53 @@ func (r *ReconcileCephMonCluster) generateKeyringSecret(keyring Keyring, namespa
 	}
 
 	if errors.IsNotFound(err) {
-		err = keyring.GenerateKey()
+		if cluster.Spec.Import != nil {
+			err = r.importKey(&keyring, namespace, cluster.Spec.Import.GetKeyringSecretName(keyring.Entity))
+		} else {
+			err = keyring.GenerateKey()
+		}
 		if err != nil {
 			return err
 		}
 
-		secret = keyring.GetSecret(clusterName)
+		secret = keyring.GetSecret(cluster.GetName())
 		secret.Namespace = namespace
-		err = r.client.Create(context.TODO(), secret)
-		if err != nil {
-			return err
+		for k, v := range cluster.GetRetainLabels() {
+			secret.Labels[k] = v
 		}
+		return r.client.Create(context.TODO(), secret)
 	}
 
-	return nil
+	if !cluster.CheckRetainLabels(secret) {
+		return fmt.Errorf("refusing to adopt keyring secret %s, it was retained from another cluster", secret.GetName())
+	}
+
+	if secret.Labels[cephv1beta1.FsidLabel] == cluster.Spec.Fsid {
+		return nil
+	}
+
+	if secret.Labels == nil {
+		secret.Labels = map[string]string{}
+	}
+	for k, v := range cluster.GetRetainLabels() {
+		secret.Labels[k] = v
+	}
+	return r.client.Update(context.TODO(), secret)
 }
 
-func (r *ReconcileCephMonCluster) getCephCluster(d *cephv1alpha1.CephMonCluster) (*cephv1alpha1.CephCluster, error) {
-	cephCluster := &cephv1alpha1.CephCluster{}
+// importKey reads the existing key for the keyring from the keyring file in the secret
+func (r *ReconcileCephMonCluster) importKey(keyring *Keyring, namespace, secretName string) error {
+	secret := &corev1.Secret{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: secretName}, secret)
+	if err != nil {
+		return fmt.Errorf("unable to read imported %s keyring: %v", keyring.Entity, err)
+	}
+
+	return keyring.ParseKey(string(secret.Data["keyring"]))
+}
+
+func (r *ReconcileCephMonCluster) getCephCluster(d *cephv1beta1.CephMonCluster) (*cephv1beta1.CephCluster, error) {
+	cephCluster := &cephv1beta1.CephCluster{}
 	cephClusterNamespacedName := types.NamespacedName{
 		Name:      d.GetCephClusterName(),
 		Namespace: d.GetNamespace(),
@@ -307,3 +421,13 @@ func (r *ReconcileCephMonCluster) getCephCluster(d *cephv1alpha1.CephMonCluster)
 
 	return cephCluster, r.client.Get(context.TODO(), cephClusterNamespacedName, cephCluster)
 }
+
+// applyPodOverrides merges the ceph cluster's monitor pod overrides over a job run against a monitor's store
+func (r *ReconcileCephMonCluster) applyPodOverrides(instance *cephv1beta1.CephMonCluster, job *batchv1.Job) error {
+	cluster, err := r.getCephCluster(instance)
+	if err != nil {
+		return err
+	}
+
+	return cluster.ApplyPodOverrides(cephv1beta1.CephDaemonTypeMon, &job.Spec.Template.Spec)
+}
@@ -0,0 +1,200 @@
+package cephmoncluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/apis"
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/ceph"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	testNamespace   = "ceph"
+	testClusterName = "test"
+)
+
+func init() {
+	if err := apis.AddToScheme(scheme.Scheme); err != nil {
+		panic(err)
+	}
+}
+
+func newTestReconciler(runner ceph.CommandRunner, objects ...runtime.Object) *ReconcileCephMonCluster {
+	return &ReconcileCephMonCluster{client: fake.NewFakeClient(objects...), scheme: scheme.Scheme, runner: runner}
+}
+
+func newTestCluster(state cephv1beta1.CephClusterState) *cephv1beta1.CephCluster {
+	cluster := &cephv1beta1.CephCluster{}
+	cluster.Name = testClusterName
+	cluster.Namespace = testNamespace
+	cluster.Spec.Fsid = "3f6d3ee2-9cbb-4e3b-9a3c-0b9cd0a8b5e1"
+	cluster.Status.State = state
+	return cluster
+}
+
+func newTestMonCluster(count int, state cephv1beta1.MonClusterState) *cephv1beta1.CephMonCluster {
+	monCluster := &cephv1beta1.CephMonCluster{}
+	monCluster.Name = testClusterName
+	monCluster.Namespace = testNamespace
+	monCluster.Spec.ClusterName = testClusterName
+	monCluster.Spec.Count = count
+	monCluster.Status.State = state
+	return monCluster
+}
+
+func newTestMon(id string, state cephv1beta1.MonState) *cephv1beta1.CephMon {
+	mon := cephv1beta1.NewCephMon(testClusterName, nil)
+	mon.Spec.ID = id
+	mon.Name = testClusterName + "-mon-" + id
+	mon.Namespace = testNamespace
+	mon.SetLabels(map[string]string{cephv1beta1.ClusterNameLabel: testClusterName})
+	mon.Status.State = state
+	mon.Status.InitialMember = state == cephv1beta1.MonInQuorum
+	mon.Status.PodIP = "10.0.0.1"
+	return mon
+}
+
+func reconcileMonCluster(t *testing.T, r *ReconcileCephMonCluster) {
+	_, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: testClusterName}})
+	if err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+}
+
+func listMonIDs(t *testing.T, c client.Client) map[string]bool {
+	mons := &cephv1beta1.CephMonList{}
+	err := c.List(context.TODO(), &client.ListOptions{Namespace: testNamespace}, mons)
+	if err != nil {
+		t.Fatalf("unable to list monitors: %v", err)
+	}
+
+	ids := make(map[string]bool, len(mons.Items))
+	for _, mon := range mons.Items {
+		ids[mon.Spec.ID] = true
+	}
+	return ids
+}
+
+func TestScaleUpFromIdle(t *testing.T) {
+	r := newTestReconciler(&ceph.FakeRunner{},
+		newTestCluster(cephv1beta1.CephClusterStartMons),
+		newTestMonCluster(3, cephv1beta1.MonClusterIdle),
+	)
+
+	reconcileMonCluster(t, r)
+
+	if ids := listMonIDs(t, r.client); len(ids) != 1 {
+		t.Errorf("expected the first monitor to be created from idle, got %d monitors", len(ids))
+	}
+}
+
+func TestScaleUpInQuorum(t *testing.T) {
+	r := newTestReconciler(&ceph.FakeRunner{},
+		newTestCluster(cephv1beta1.CephClusterRunning),
+		newTestMonCluster(3, cephv1beta1.MonClusterInQuorum),
+		newTestMon("a", cephv1beta1.MonInQuorum),
+	)
+
+	reconcileMonCluster(t, r)
+
+	ids := listMonIDs(t, r.client)
+	if len(ids) != 2 || !ids["a"] {
+		t.Errorf("expected one monitor to be added to a, got %v", ids)
+	}
+}
+
+func TestScaleUpWaitsForQuorum(t *testing.T) {
+	r := newTestReconciler(&ceph.FakeRunner{},
+		newTestCluster(cephv1beta1.CephClusterRunning),
+		newTestMonCluster(5, cephv1beta1.MonClusterInQuorum),
+		newTestMon("a", cephv1beta1.MonInQuorum),
+		newTestMon("b", cephv1beta1.MonInQuorum),
+		newTestMon("c", cephv1beta1.MonWaitForPodReady),
+	)
+
+	reconcileMonCluster(t, r)
+
+	if ids := listMonIDs(t, r.client); len(ids) != 3 {
+		t.Errorf("expected no monitor to be added while c is out of quorum, got %v", ids)
+	}
+}
+
+func TestScaleDown(t *testing.T) {
+	runner := &ceph.FakeRunner{}
+	r := newTestReconciler(runner,
+		newTestCluster(cephv1beta1.CephClusterRunning),
+		newTestMonCluster(1, cephv1beta1.MonClusterInQuorum),
+		newTestMon("a", cephv1beta1.MonInQuorum),
+		newTestMon("b", cephv1beta1.MonInQuorum),
+		newTestMon("c", cephv1beta1.MonInQuorum),
+	)
+
+	reconcileMonCluster(t, r)
+
+	ids := listMonIDs(t, r.client)
+	if len(ids) != 2 {
+		t.Fatalf("expected one monitor to be removed, got %v", ids)
+	}
+
+	for _, id := range []string{"a", "b", "c"} {
+		if !ids[id] && !runner.Ran("mon remove "+id) {
+			t.Errorf("monitor %s was deleted without being removed from the monmap, ran %s", id, runner)
+		}
+	}
+}
+
+func TestEstablishingQuorumChecksPlacement(t *testing.T) {
+	cases := []struct {
+		Name         string
+		AntiAffinity cephv1beta1.AntiAffinityType
+		AllowUnsafe  bool
+		Expected     cephv1beta1.MonClusterState
+	}{
+		{Name: "preferred", Expected: cephv1beta1.MonClusterInQuorum},
+		{Name: "required", AntiAffinity: cephv1beta1.AntiAffinityRequired, Expected: cephv1beta1.MonClusterEstablishingQuorum},
+		{Name: "required-unsafe", AntiAffinity: cephv1beta1.AntiAffinityRequired, AllowUnsafe: true, Expected: cephv1beta1.MonClusterInQuorum},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			monCluster := newTestMonCluster(3, cephv1beta1.MonClusterEstablishingQuorum)
+			monCluster.Spec.Placement.AntiAffinity = c.AntiAffinity
+			monCluster.Spec.Placement.AllowUnsafe = c.AllowUnsafe
+
+			node := &corev1.Node{}
+			node.Name = "node-a"
+			node.SetLabels(map[string]string{cephv1beta1.DefaultTopologyKey: "node-a"})
+
+			objects := []runtime.Object{newTestCluster(cephv1beta1.CephClusterStartMons), monCluster, node}
+			for _, id := range []string{"a", "b", "c"} {
+				mon := newTestMon(id, cephv1beta1.MonInQuorum)
+				pod := &corev1.Pod{}
+				pod.Name = mon.GetPodName()
+				pod.Namespace = testNamespace
+				pod.Spec.NodeName = node.Name
+				objects = append(objects, mon, pod)
+			}
+			r := newTestReconciler(&ceph.FakeRunner{}, objects...)
+
+			result, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: testClusterName}})
+			if err != nil {
+				t.Fatalf("reconcile failed: %v", err)
+			}
+
+			if state := getTestMonCluster(t, r).Status.State; state != c.Expected {
+				t.Errorf("expected %s, got %s", c.Expected, state)
+			}
+			if c.Expected == cephv1beta1.MonClusterEstablishingQuorum && result.RequeueAfter != placementRecheckInterval {
+				t.Errorf("expected placement to be checked again after %s, got %v", placementRecheckInterval, result)
+			}
+		})
+	}
+}
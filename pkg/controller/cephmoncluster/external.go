@@ -0,0 +1,134 @@
+package cephmoncluster
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/ceph"
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/controller/common"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// clientPodRequeueInterval is how often joining an external quorum checks whether the client pod is running
+const clientPodRequeueInterval = 10 * time.Second
+
+// joinExternalQuorum starts an imported mon cluster.  The existing monitors already hold quorum, so monitors
+// join it without forming a new one once the monmap ConfigMap lists the existing monitors.
+func (r *ReconcileCephMonCluster) joinExternalQuorum(instance *cephv1beta1.CephMonCluster, cephCluster *cephv1beta1.CephCluster,
+	monMap cephv1beta1.MonMap) (reconcile.Result, error) {
+
+	synced, err := r.syncExternalMonMap(instance, cephCluster, monMap)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if !synced {
+		log.Info("Waiting for the client pod to fetch the existing monmap", "MonCluster", instance.GetName())
+		return reconcile.Result{RequeueAfter: clientPodRequeueInterval}, nil
+	}
+
+	log.Info("Joining external monitor quorum", "MonCluster", instance.GetName())
+	instance.SetMonClusterState(cephv1beta1.MonClusterInQuorum)
+	return r.updateAndRequeue(instance)
+}
+
+// syncExternalMonMap writes the monmap ConfigMap from the monmap of the existing quorum.  A new monitor builds
+// its store from the ConfigMap, it only finds the quorum it joins if the existing monitors are listed.  Returns
+// false until the monmap could be fetched.
+func (r *ReconcileCephMonCluster) syncExternalMonMap(instance *cephv1beta1.CephMonCluster, cephCluster *cephv1beta1.CephCluster,
+	monMap cephv1beta1.MonMap) (bool, error) {
+
+	admin, err := r.getExternalAdmin(instance, cephCluster, monMap)
+	if err != nil || admin == nil {
+		return false, err
+	}
+
+	dump, err := admin.MonDump()
+	if err != nil {
+		return false, err
+	}
+	if dump.Fsid != cephCluster.Spec.Fsid {
+		return false, fmt.Errorf("existing monitors belong to cluster %s, expected %s", dump.Fsid, cephCluster.Spec.Fsid)
+	}
+
+	joined, err := externalMonMap(dump, monMap)
+	if err != nil {
+		return false, err
+	}
+	for id, entry := range monMap.GetInitalMonMap() {
+		joined[id] = entry
+	}
+
+	cm, err := instance.GetMonMapConfigMap(joined)
+	if err != nil {
+		return false, err
+	}
+	cm.Namespace = instance.Namespace
+	_, err = r.createOrUpdate(cm)
+	return err == nil, err
+}
+
+// getExternalAdmin returns an admin interface to the quorum of an imported cluster.  Commands run in one of the
+// operator's monitors once one is in quorum, and in the cluster's client pod until then.  Returns nil while the
+// client pod is starting.
+func (r *ReconcileCephMonCluster) getExternalAdmin(instance *cephv1beta1.CephMonCluster, cephCluster *cephv1beta1.CephCluster,
+	monMap cephv1beta1.MonMap) (*ceph.Admin, error) {
+
+	if monMap.CountInState(cephv1beta1.MonInQuorum) > 0 {
+		return r.getAdmin(instance, monMap, "")
+	}
+
+	keyringSecretName := CLIENT_ADMIN_KEYRING.GetSecretName(cephCluster.GetName())
+	return common.GetClientAdmin(r.client, r.scheme, r.runner, cephCluster, keyringSecretName, "")
+}
+
+// externalMonMap returns the monitors in the dumped monmap that aren't in monMap, as initial members
+func externalMonMap(dump *ceph.MonDump, monMap cephv1beta1.MonMap) (cephv1beta1.MonMap, error) {
+	external := make(cephv1beta1.MonMap)
+	for _, mon := range dump.Mons {
+		if _, ok := monMap[mon.Name]; ok {
+			continue
+		}
+
+		addrs, err := parseMonDumpAddrs(mon)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse the address of monitor %s: %v", mon.Name, err)
+		}
+
+		entry := cephv1beta1.MonMapEntry{Addrs: addrs, State: cephv1beta1.MonInQuorum, InitialMember: true}
+		for _, addr := range addrs {
+			if entry.IP == nil || addr.Type == cephv1beta1.MonAddrV1 {
+				entry.IP = net.ParseIP(addr.Host)
+				entry.Port = addr.Port
+			}
+		}
+		external[mon.Name] = entry
+	}
+	return external, nil
+}
+
+// parseMonDumpAddrs returns the addresses of a dumped monitor, releases before nautilus only report a v1 address
+func parseMonDumpAddrs(mon ceph.MonDumpEntry) (cephv1beta1.MonAddrVec, error) {
+	dumped := mon.PublicAddrs.AddrVec
+	if len(dumped) == 0 {
+		dumped = []ceph.MonDumpAddr{{Type: string(cephv1beta1.MonAddrV1), Addr: mon.Addr}}
+	}
+
+	addrs := cephv1beta1.MonAddrVec{}
+	for _, addr := range dumped {
+		// Addresses carry a nonce, ip:port/nonce
+		host, portString, err := net.SplitHostPort(strings.SplitN(addr.Addr, "/", 2)[0])
+		if err != nil {
+			return nil, err
+		}
+		port, err := strconv.Atoi(portString)
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, cephv1beta1.MonAddr{Type: cephv1beta1.MonAddrType(addr.Type), Host: host, Port: port})
+	}
+	return addrs, nil
+}
@@ -0,0 +1,158 @@
+package cephmoncluster
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/ceph"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const testMonDump = `{
+	"epoch": 3,
+	"fsid": "3f6d3ee2-9cbb-4e3b-9a3c-0b9cd0a8b5e1",
+	"mons": [
+		{"rank": 0, "name": "host1", "public_addrs": {"addrvec": [
+			{"type": "v2", "addr": "192.168.1.10:3300", "nonce": 0},
+			{"type": "v1", "addr": "192.168.1.10:6789", "nonce": 0}
+		]}, "addr": "192.168.1.10:6789/0"},
+		{"rank": 1, "name": "host2", "addr": "192.168.1.11:6789/0"}
+	]
+}`
+
+func newTestImportedCluster() *cephv1beta1.CephCluster {
+	cluster := newTestCluster(cephv1beta1.CephClusterStartMons)
+	cluster.Spec.MonImage = cephv1beta1.ImageSpec{Registry: "ceph/daemon", Tag: "latest-nautilus"}
+	cluster.Spec.Import = &cephv1beta1.ImportSpec{
+		MonHosts:               []string{"192.168.1.10", "192.168.1.11"},
+		MonKeyringSecretName:   "existing-mon-keyring",
+		AdminKeyringSecretName: "existing-admin-keyring",
+	}
+	return cluster
+}
+
+func newTestExternalMonCluster() *cephv1beta1.CephMonCluster {
+	monCluster := newTestMonCluster(3, cephv1beta1.MonClusterIdle)
+	monCluster.Spec.ExternalQuorum = true
+	return monCluster
+}
+
+func TestJoinExternalQuorumStartsClientPod(t *testing.T) {
+	cluster := newTestImportedCluster()
+	monCluster := newTestExternalMonCluster()
+	r := newTestReconciler(&ceph.FakeRunner{}, cluster, monCluster)
+
+	result, err := r.joinExternalQuorum(monCluster, cluster, cephv1beta1.MonMap{})
+	if err != nil {
+		t.Fatalf("unable to join external quorum: %v", err)
+	}
+	if result.RequeueAfter == 0 {
+		t.Errorf("expected a requeue while the client pod starts")
+	}
+	if monCluster.GetMonClusterState() != cephv1beta1.MonClusterIdle {
+		t.Errorf("joined the external quorum without its monmap, state %s", monCluster.GetMonClusterState())
+	}
+
+	pod := &corev1.Pod{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: cluster.GetClientPodName()}, pod)
+	if err != nil {
+		t.Fatalf("client pod wasn't created: %v", err)
+	}
+	if secret := pod.Spec.Volumes[1].Secret.SecretName; secret != CLIENT_ADMIN_KEYRING.GetSecretName(testClusterName) {
+		t.Errorf("client pod mounts keyring %s", secret)
+	}
+}
+
+func TestJoinExternalQuorum(t *testing.T) {
+	cluster := newTestImportedCluster()
+	monCluster := newTestExternalMonCluster()
+
+	pod := cluster.GetClientPod(CLIENT_ADMIN_KEYRING.GetSecretName(testClusterName))
+	pod.Status.Phase = corev1.PodRunning
+
+	runner := &ceph.FakeRunner{Outputs: map[string]string{"mon dump --format json": testMonDump}}
+	r := newTestReconciler(runner, cluster, monCluster, pod)
+
+	_, err := r.joinExternalQuorum(monCluster, cluster, cephv1beta1.MonMap{})
+	if err != nil {
+		t.Fatalf("unable to join external quorum: %v", err)
+	}
+	if monCluster.GetMonClusterState() != cephv1beta1.MonClusterInQuorum {
+		t.Errorf("expected the external quorum to be joined, state %s", monCluster.GetMonClusterState())
+	}
+
+	cm := &corev1.ConfigMap{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: monCluster.GetConfigMapName()}, cm)
+	if err != nil {
+		t.Fatalf("monmap ConfigMap wasn't written: %v", err)
+	}
+
+	data := struct {
+		MonMap []map[string]string `json:"monMap"`
+	}{}
+	err = json.Unmarshal([]byte(cm.Data["jsonMonMap"]), &data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"host1": "[v2:192.168.1.10:3300,v1:192.168.1.10:6789]",
+		"host2": "[v1:192.168.1.11:6789]",
+	}
+	if len(data.MonMap) != len(expected) {
+		t.Fatalf("expected the existing monitors in the monmap, got %v", data.MonMap)
+	}
+	for _, mon := range data.MonMap {
+		if mon["addrs"] != expected[mon["id"]] {
+			t.Errorf("monitor %s has addrs %s, expected %s", mon["id"], mon["addrs"], expected[mon["id"]])
+		}
+	}
+}
+
+func TestJoinExternalQuorumRejectsFsid(t *testing.T) {
+	cluster := newTestImportedCluster()
+	cluster.Spec.Fsid = "00000000-0000-0000-0000-000000000000"
+	monCluster := newTestExternalMonCluster()
+
+	pod := cluster.GetClientPod(CLIENT_ADMIN_KEYRING.GetSecretName(testClusterName))
+	pod.Status.Phase = corev1.PodRunning
+
+	runner := &ceph.FakeRunner{Outputs: map[string]string{"mon dump --format json": testMonDump}}
+	r := newTestReconciler(runner, cluster, monCluster, pod)
+
+	_, err := r.joinExternalQuorum(monCluster, cluster, cephv1beta1.MonMap{})
+	if err == nil {
+		t.Errorf("joined the quorum of a cluster with a different fsid")
+	}
+}
+
+func TestScaleRefreshesExternalMonMap(t *testing.T) {
+	cluster := newTestImportedCluster()
+	monCluster := newTestExternalMonCluster()
+	monCluster.Status.State = cephv1beta1.MonClusterInQuorum
+	mon := newTestMon("a", cephv1beta1.MonInQuorum)
+
+	runner := &ceph.FakeRunner{Outputs: map[string]string{"mon dump --format json": testMonDump}}
+	r := newTestReconciler(runner, cluster, monCluster, mon)
+
+	err := r.scale(monCluster, cluster, cephv1beta1.MonMap{"a": mon.GetMonMapEntry()})
+	if err != nil {
+		t.Fatalf("unable to scale: %v", err)
+	}
+
+	if !runner.Ran("mon dump --format json") {
+		t.Errorf("monmap wasn't fetched before adding a monitor, ran %s", runner)
+	}
+	if ids := listMonIDs(t, r.client); len(ids) != 2 {
+		t.Errorf("expected a monitor to be added, got %v", ids)
+	}
+
+	cm := &corev1.ConfigMap{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: monCluster.GetConfigMapName()}, cm)
+	if err != nil {
+		t.Fatalf("monmap ConfigMap wasn't written: %v", err)
+	}
+}
@@ -9,7 +9,7 @@ import (
 	"strings"
 	"time"
 
-	cephv1alpha1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1alpha1"
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
 
 	corev1 "k8s.io/api/core/v1"
 )
@@ -51,8 +51,8 @@ func (k *Keyring) GetSecret(cluster string) *corev1.Secret {
 	secret.StringData[keyringFilename] = k.CreateKeyring()
 
 	secret.SetLabels(map[string]string{
-		cephv1alpha1.ClusterNameLabel:   cluster,
-		cephv1alpha1.KeyringEntityLabel: strings.Trim(k.Entity, "."),
+		cephv1beta1.ClusterNameLabel:   cluster,
+		cephv1beta1.KeyringEntityLabel: strings.Trim(k.Entity, "."),
 	})
 
 	return secret
@@ -87,6 +87,29 @@ func (k *Keyring) GenerateKey() error {
 	return nil
 }
 
+// ParseKey reads the key for the keyring's entity from an existing keyring file
+func (k *Keyring) ParseKey(keyring string) error {
+	inSection := false
+	for _, line := range strings.Split(keyring, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			inSection = strings.Trim(line, "[]") == k.Entity
+			continue
+		}
+		if !inSection {
+			continue
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) == 2 && strings.TrimSpace(parts[0]) == "key" {
+			k.Key = strings.TrimSpace(parts[1])
+			return nil
+		}
+	}
+
+	return fmt.Errorf("no key found for %s", k.Entity)
+}
+
 func EncodeKey(key []byte, t time.Time) (string, error) {
 
 	if len(key) != 16 {
@@ -16,3 +16,26 @@ func TestEncodeKey(t *testing.T) {
 		t.Errorf("Encoded secret got '%s' expected '%s'", s, expected)
 	}
 }
+
+func TestParseKey(t *testing.T) {
+	existing := "[client.admin]\n" +
+		"\tkey = AQDnNilcaOydMBAAZaaXchaqWXkPzu0H7zC0Lg==\n" +
+		"[mon.]\n" +
+		"\tkey = AQCthi5cZicBABAAIo+6fedJ7TSzOKoAw6Ivmg==\n" +
+		"\tcaps mon = \"allow *\"\n"
+
+	keyring := MON_KEYRING
+	err := keyring.ParseKey(existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "AQCthi5cZicBABAAIo+6fedJ7TSzOKoAw6Ivmg=="
+	if keyring.Key != expected {
+		t.Errorf("Parsed key got '%s' expected '%s'", keyring.Key, expected)
+	}
+
+	missing := Keyring{Entity: "client.bootstrap-osd"}
+	if err := missing.ParseKey(existing); err == nil {
+		t.Errorf("Expected an error parsing a keyring without the entity")
+	}
+}
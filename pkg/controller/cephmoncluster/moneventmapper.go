@@ -1,7 +1,7 @@
 package cephmoncluster
 
 import (
-	cephv1alpha1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1alpha1"
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
 
 	"k8s.io/apimachinery/pkg/types"
 
@@ -15,7 +15,7 @@ func (m *MonEventMapper) Map(o handler.MapObject) []reconcile.Request {
 	req := make([]reconcile.Request, 0, 1)
 	switch obj := o.Object.(type) {
 
-	case *cephv1alpha1.CephMon:
+	case *cephv1beta1.CephMon:
 		req = append(req, reconcile.Request{
 			NamespacedName: types.NamespacedName{
 				Name:      obj.Spec.ClusterName,
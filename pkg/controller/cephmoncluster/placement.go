@@ -0,0 +1,68 @@
+package cephmoncluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// placementRecheckInterval is how often monitor placement is checked again while it holds back quorum
+const placementRecheckInterval = 30 * time.Second
+
+// placementSafe returns true if no single failure domain holds enough monitors to break quorum
+func (r *ReconcileCephMonCluster) placementSafe(instance *cephv1beta1.CephMonCluster, monMap cephv1beta1.MonMap) (bool, error) {
+	domains, err := r.getFailureDomains(instance.GetPlacement().GetTopologyKey(), monMap)
+	if err != nil {
+		return false, err
+	}
+
+	return monMap.FailureDomainSafe(domains), nil
+}
+
+// getFailureDomains maps monitor ids to the value of the topology key on the node running the monitor.
+// Monitors without a scheduled pod are each placed in their own failure domain.
+func (r *ReconcileCephMonCluster) getFailureDomains(topologyKey string, monMap cephv1beta1.MonMap) (map[string]string, error) {
+	domains := make(map[string]string, len(monMap))
+
+	for id, entry := range monMap {
+		domains[id] = fmt.Sprintf("unscheduled/%s", id)
+
+		mon := &cephv1beta1.CephMon{}
+		err := r.client.Get(context.TODO(), entry.NamespacedName, mon)
+		if err != nil {
+			return nil, err
+		}
+
+		pod := &corev1.Pod{}
+		err = r.client.Get(context.TODO(), types.NamespacedName{
+			Name:      mon.GetPodName(),
+			Namespace: mon.GetNamespace(),
+		}, pod)
+		if errors.IsNotFound(err) || (err == nil && pod.Spec.NodeName == "") {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		node := &corev1.Node{}
+		err = r.client.Get(context.TODO(), types.NamespacedName{Name: pod.Spec.NodeName}, node)
+		if err != nil {
+			return nil, err
+		}
+
+		domain, ok := node.GetLabels()[topologyKey]
+		if !ok {
+			return nil, fmt.Errorf("node %s is missing topology label %s", node.GetName(), topologyKey)
+		}
+		domains[id] = domain
+	}
+
+	return domains, nil
+}
@@ -0,0 +1,176 @@
+package cephmoncluster
+
+import (
+	"context"
+	"sort"
+
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/controller/common"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// recover rebuilds quorum from the surviving monitor named in the recovery spec.  Once every monitor
+// is idle the survivor's monmap is edited to remove all other monitors, the other monitors are
+// deleted and the cluster is restarted with the survivor as the only initial member.  Recovery runs
+// once for each nonce in the recovery spec.
+func (r *ReconcileCephMonCluster) recover(instance *cephv1beta1.CephMonCluster, monMap cephv1beta1.MonMap) (reconcile.Result, error) {
+	if !instance.RecoveryRequested() {
+		log.Info("Recovery cancelled")
+		instance.SetMonClusterState(cephv1beta1.MonClusterLostQuorum)
+		return r.updateAndRequeue(instance)
+	}
+
+	survivorID := instance.Spec.Recovery.SurvivorID
+	survivorEntry, ok := monMap[survivorID]
+	if !ok {
+		log.Info("Unable to recover, surviving monitor not found", "MonitorID", survivorID)
+		return reconcile.Result{}, nil
+	}
+
+	restoreBackup := instance.Spec.Recovery.Backup
+	backup, ok := instance.GetBackup(restoreBackup)
+	if restoreBackup != "" && (!ok || instance.Spec.Backup == nil) {
+		log.Info("Unable to recover, backup not found", "Backup", restoreBackup)
+		return reconcile.Result{}, nil
+	}
+
+	if !monMap.AllInState(cephv1beta1.MonIdle) {
+		return reconcile.Result{}, nil
+	}
+
+	survivor := &cephv1beta1.CephMon{}
+	err := r.client.Get(context.TODO(), survivorEntry.NamespacedName, survivor)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	peers := make([]string, 0, len(monMap)-1)
+	for id := range monMap {
+		if id != survivorID {
+			peers = append(peers, id)
+		}
+	}
+	sort.Strings(peers)
+
+	// A restored store carries the monmap of the monitor it was taken from
+	removeMonIDs := peers
+	if restoreBackup != "" && backup.MonID != survivorID {
+		if _, ok := monMap[backup.MonID]; !ok {
+			removeMonIDs = append([]string{backup.MonID}, peers...)
+		}
+	}
+
+	job := &batchv1.Job{}
+	jobNamespacedName := types.NamespacedName{Namespace: instance.GetNamespace(), Name: survivor.GetRecoveryJobName()}
+	err = r.client.Get(context.TODO(), jobNamespacedName, job)
+	if errors.IsNotFound(err) {
+		job = survivor.GetRecoveryJob(instance, removeMonIDs, restoreBackup)
+		job.Namespace = instance.GetNamespace()
+		if err := r.applyPodOverrides(instance, job); err != nil {
+			return reconcile.Result{}, err
+		}
+		if err := controllerutil.SetControllerReference(instance, job, r.scheme); err != nil {
+			return reconcile.Result{}, err
+		}
+
+		log.Info("Rebuilding monmap from surviving monitor", "MonitorID", survivorID, "RemovedMonitors", removeMonIDs,
+			"Backup", restoreBackup)
+		return reconcile.Result{}, r.client.Create(context.TODO(), job)
+	}
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	switch {
+	case common.JobHasCondition(job, batchv1.JobFailed):
+		log.Info("Monmap rebuild failed, delete the recovery job to retry", "MonitorID", survivorID, "Job", job.GetName())
+		return reconcile.Result{}, nil
+
+	case common.JobHasCondition(job, batchv1.JobComplete):
+		return r.finishRecovery(instance, survivor, monMap, peers, job)
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// finishRecovery removes the monitors dropped from the monmap and restarts the cluster from the survivor.  The
+// volume claims of the dropped monitors are left to the reclaim policy.
+func (r *ReconcileCephMonCluster) finishRecovery(instance *cephv1beta1.CephMonCluster, survivor *cephv1beta1.CephMon,
+	monMap cephv1beta1.MonMap, peers []string, job *batchv1.Job) (reconcile.Result, error) {
+
+	pvSelectors := make([]*metav1.LabelSelector, 0, len(peers))
+	for _, id := range peers {
+		mon := &cephv1beta1.CephMon{}
+		err := r.client.Get(context.TODO(), monMap[id].NamespacedName, mon)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		pvSelectors = append(pvSelectors, mon.Spec.PvSelector)
+		log.Info("Removing monitor dropped by recovery", "MonitorID", id)
+		err = r.dropMon(mon)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	// Managed monitor counts are restored by scaling once the survivor has quorum
+	if instance.Spec.Count == 0 {
+		for _, pvSelector := range pvSelectors {
+			err := r.addMon(instance, pvSelector)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+	}
+
+	if !survivor.Status.InitialMember {
+		survivor.Status.InitialMember = true
+		_, err := r.updateAndRequeue(survivor)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	err := r.deleteJob(job)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	instance.Status.LastRecovery = &cephv1beta1.MonRecoveryStatus{
+		Nonce:          instance.Spec.Recovery.Nonce,
+		SurvivorID:     survivor.Spec.ID,
+		RemovedMonIDs:  peers,
+		CompletionTime: metav1.Now(),
+	}
+	instance.SetMonClusterState(cephv1beta1.MonClusterIdle)
+	return r.updateAndRequeue(instance)
+}
+
+// dropMon deletes a monitor and its pod, the monitor's volume claim is garbage collected with it or retained
+// according to the reclaim policy
+func (r *ReconcileCephMonCluster) dropMon(mon *cephv1beta1.CephMon) error {
+	pod := &corev1.Pod{}
+	pod.Name = mon.GetPodName()
+	pod.Namespace = mon.GetNamespace()
+
+	for _, o := range []runtime.Object{pod, mon} {
+		err := r.client.Delete(context.TODO(), o)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
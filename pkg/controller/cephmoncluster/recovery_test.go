@@ -0,0 +1,111 @@
+package cephmoncluster
+
+import (
+	"context"
+	"testing"
+
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/ceph"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newTestRecoveryMonCluster(state cephv1beta1.MonClusterState) *cephv1beta1.CephMonCluster {
+	monCluster := newTestMonCluster(3, state)
+	monCluster.Spec.Recovery = &cephv1beta1.MonRecoverySpec{Nonce: "1", SurvivorID: "a"}
+	return monCluster
+}
+
+func getTestMonCluster(t *testing.T, r *ReconcileCephMonCluster) *cephv1beta1.CephMonCluster {
+	monCluster := &cephv1beta1.CephMonCluster{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: testClusterName}, monCluster)
+	if err != nil {
+		t.Fatalf("unable to get mon cluster: %v", err)
+	}
+	return monCluster
+}
+
+func TestFinishRecovery(t *testing.T) {
+	survivor := newTestMon("a", cephv1beta1.MonIdle)
+	peer := newTestMon("b", cephv1beta1.MonIdle)
+
+	peerClaim := &corev1.PersistentVolumeClaim{}
+	peerClaim.Name = peer.GetName()
+	peerClaim.Namespace = testNamespace
+
+	job := &batchv1.Job{}
+	job.Name = survivor.GetRecoveryJobName()
+	job.Namespace = testNamespace
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+
+	r := newTestReconciler(&ceph.FakeRunner{},
+		newTestCluster(cephv1beta1.CephClusterStartMons),
+		newTestRecoveryMonCluster(cephv1beta1.MonClusterRecovering),
+		survivor, peer, peerClaim, job,
+	)
+
+	reconcileMonCluster(t, r)
+
+	monCluster := getTestMonCluster(t, r)
+	if monCluster.Spec.Recovery == nil || monCluster.Spec.Recovery.Nonce != "1" {
+		t.Errorf("expected the recovery spec to be left in place, got %v", monCluster.Spec.Recovery)
+	}
+	if monCluster.Status.LastRecovery == nil || monCluster.Status.LastRecovery.Nonce != "1" {
+		t.Errorf("expected the completed nonce to be recorded, got %v", monCluster.Status.LastRecovery)
+	}
+	if monCluster.GetMonClusterState() != cephv1beta1.MonClusterIdle {
+		t.Errorf("expected the mon cluster to be idle, got %s", monCluster.GetMonClusterState())
+	}
+
+	if ids := listMonIDs(t, r.client); len(ids) != 1 || !ids["a"] {
+		t.Errorf("expected only the survivor to be left, got %v", ids)
+	}
+
+	err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: peerClaim.Name}, &corev1.PersistentVolumeClaim{})
+	if err != nil {
+		t.Errorf("expected the dropped monitor's volume claim to be left to the reclaim policy: %v", err)
+	}
+
+	err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: job.Name}, &batchv1.Job{})
+	if !errors.IsNotFound(err) {
+		t.Errorf("expected the recovery job to be deleted: %v", err)
+	}
+}
+
+func TestRecoveryRunsOncePerNonce(t *testing.T) {
+	monCluster := newTestRecoveryMonCluster(cephv1beta1.MonClusterLostQuorum)
+	monCluster.Status.LastRecovery = &cephv1beta1.MonRecoveryStatus{Nonce: "1", SurvivorID: "a"}
+
+	r := newTestReconciler(&ceph.FakeRunner{},
+		newTestCluster(cephv1beta1.CephClusterStartMons),
+		monCluster,
+		newTestMon("a", cephv1beta1.MonWaitForPodReady),
+		newTestMon("b", cephv1beta1.MonIdle),
+	)
+
+	reconcileMonCluster(t, r)
+
+	if state := getTestMonCluster(t, r).GetMonClusterState(); state == cephv1beta1.MonClusterRecovering {
+		t.Errorf("expected a completed recovery not to run again on quorum loss")
+	}
+}
+
+func TestRecoveryStartsForNewNonce(t *testing.T) {
+	monCluster := newTestRecoveryMonCluster(cephv1beta1.MonClusterLostQuorum)
+	monCluster.Spec.Recovery.Nonce = "2"
+	monCluster.Status.LastRecovery = &cephv1beta1.MonRecoveryStatus{Nonce: "1", SurvivorID: "a"}
+
+	r := newTestReconciler(&ceph.FakeRunner{},
+		newTestCluster(cephv1beta1.CephClusterStartMons),
+		monCluster,
+		newTestMon("a", cephv1beta1.MonIdle),
+	)
+
+	reconcileMonCluster(t, r)
+
+	if state := getTestMonCluster(t, r).GetMonClusterState(); state != cephv1beta1.MonClusterRecovering {
+		t.Errorf("expected a new nonce to start recovery, got %s", state)
+	}
+}
@@ -0,0 +1,54 @@
+package cephmoncluster
+
+import (
+	"context"
+	"time"
+
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// replaceFailed replaces one monitor that has been out of quorum for longer than the failure timeout.
+// It returns true if a replacement was attempted.
+func (r *ReconcileCephMonCluster) replaceFailed(instance *cephv1beta1.CephMonCluster, monMap cephv1beta1.MonMap) (bool, reconcile.Result, error) {
+	now := time.Now()
+	timeout := instance.GetFailureTimeout()
+
+	failed := monMap.GetFailed(now, timeout)
+	if len(failed) == 0 {
+		if next, ok := monMap.NextFailureIn(now, timeout); ok {
+			return false, reconcile.Result{RequeueAfter: next}, nil
+		}
+		return false, reconcile.Result{}, nil
+	}
+
+	id := failed[0]
+
+	// The failed monitor is removed before its replacement is added.  A dead monitor doesn't
+	// count towards quorum, so growing the monmap first would raise the quorum size without
+	// adding a voter until the replacement joins.
+	if !monMap.CanRemove(id) {
+		log.Info("Unable to replace failed monitor, remaining monitors would not have quorum", "MonitorID", id)
+		return true, reconcile.Result{}, nil
+	}
+
+	mon := &cephv1beta1.CephMon{}
+	err := r.client.Get(context.TODO(), monMap[id].NamespacedName, mon)
+	if err != nil {
+		return true, reconcile.Result{}, err
+	}
+
+	log.Info("Replacing failed monitor", "MonitorID", id, "OutOfQuorumSince", monMap[id].OutOfQuorumSince)
+	err = r.removeMon(instance, monMap, id)
+	if err != nil {
+		return true, reconcile.Result{}, err
+	}
+
+	// Managed monitor counts are restored by scaling
+	if instance.Spec.Count > 0 {
+		return true, reconcile.Result{}, nil
+	}
+
+	return true, reconcile.Result{}, r.addMon(instance, mon.Spec.PvSelector)
+}
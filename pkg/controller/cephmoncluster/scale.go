@@ -0,0 +1,135 @@
+package cephmoncluster
+
+import (
+	"context"
+	"fmt"
+
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/ceph"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// scale adds or removes a single monitor to move towards the desired monitor count.  Changes are
+// only made once every monitor is in quorum.  While joining an external quorum the monmap ConfigMap is
+// refreshed from the existing monitors before a monitor is added.
+func (r *ReconcileCephMonCluster) scale(instance *cephv1beta1.CephMonCluster, cephCluster *cephv1beta1.CephCluster,
+	monMap cephv1beta1.MonMap) error {
+	count := instance.Spec.Count
+	if count == 0 || len(monMap) == count {
+		return nil
+	}
+
+	// Even counts are rejected by validation, this only guards objects written before it was in place
+	if count%2 == 0 {
+		log.Info("Ignoring monitor count, count must be odd", "Count", count)
+		return nil
+	}
+
+	if !monMap.AllInState(cephv1beta1.MonInQuorum) {
+		return nil
+	}
+
+	if len(monMap) < count {
+		if instance.Spec.ExternalQuorum {
+			synced, err := r.syncExternalMonMap(instance, cephCluster, monMap)
+			if err != nil {
+				return err
+			}
+			if !synced {
+				return fmt.Errorf("unable to fetch the monmap of the external quorum, the client pod isn't running")
+			}
+		}
+		return r.addMon(instance, instance.Spec.PvSelector)
+	}
+
+	return r.removeMon(instance, monMap, monMap.GetRemovalCandidate())
+}
+
+func (r *ReconcileCephMonCluster) addMon(instance *cephv1beta1.CephMonCluster, pvSelector *metav1.LabelSelector) error {
+	mon := cephv1beta1.NewCephMon(instance.GetCephClusterName(), pvSelector)
+	mon.Namespace = instance.GetNamespace()
+	mon.SetLabels(map[string]string{
+		cephv1beta1.ClusterNameLabel: instance.GetCephClusterName(),
+	})
+
+	if err := controllerutil.SetControllerReference(instance, mon, r.scheme); err != nil {
+		return err
+	}
+
+	log.Info("Adding monitor", "MonitorID", mon.Spec.ID)
+	return r.client.Create(context.TODO(), mon)
+}
+
+// removeMon removes the monitor from the monmap using another monitor, then deletes it
+func (r *ReconcileCephMonCluster) removeMon(instance *cephv1beta1.CephMonCluster, monMap cephv1beta1.MonMap, id string) error {
+	if !monMap.CanRemove(id) {
+		log.Info("Refusing to remove monitor, remaining monitors would not have quorum", "MonitorID", id)
+		return nil
+	}
+
+	admin, err := r.getAdmin(instance, monMap, id)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Removing monitor", "MonitorID", id)
+	err = admin.RemoveMon(id)
+	if err != nil {
+		return err
+	}
+
+	mon := &cephv1beta1.CephMon{}
+	err = r.client.Get(context.TODO(), monMap[id].NamespacedName, mon)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return r.deleteMon(mon)
+}
+
+// deleteMon deletes the monitor along with its pod and volume claim
+func (r *ReconcileCephMonCluster) deleteMon(mon *cephv1beta1.CephMon) error {
+	pod := &corev1.Pod{}
+	pod.Name = mon.GetPodName()
+	pod.Namespace = mon.GetNamespace()
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	pvc.Name = mon.GetName()
+	pvc.Namespace = mon.GetNamespace()
+
+	for _, o := range []runtime.Object{pod, pvc, mon} {
+		err := r.client.Delete(context.TODO(), o)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getAdmin returns an admin interface running in the pod of an in quorum monitor other than exclude
+func (r *ReconcileCephMonCluster) getAdmin(instance *cephv1beta1.CephMonCluster, monMap cephv1beta1.MonMap, exclude string) (*ceph.Admin, error) {
+	for id, entry := range monMap {
+		if id == exclude || entry.State != cephv1beta1.MonInQuorum {
+			continue
+		}
+
+		mon := &cephv1beta1.CephMon{}
+		err := r.client.Get(context.TODO(), entry.NamespacedName, mon)
+		if err != nil {
+			return nil, err
+		}
+
+		return ceph.NewAdmin(r.runner, mon.GetNamespace(), mon.GetPodName(), instance.GetCephClusterName()), nil
+	}
+
+	return nil, fmt.Errorf("no monitor in quorum to run admin commands")
+}
@@ -3,8 +3,9 @@ package cephosd
 import (
 	"context"
 
-	cephv1alpha1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1alpha1"
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
 	"github.com/PolarGeospatialCenter/ceph-operator/pkg/controller/common"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -46,7 +47,7 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	}
 
 	// Watch for changes to primary resource CephOsd
-	err = c.Watch(&source.Kind{Type: &cephv1alpha1.CephOsd{}}, &handler.EnqueueRequestForObject{})
+	err = c.Watch(&source.Kind{Type: &cephv1beta1.CephOsd{}}, &handler.EnqueueRequestForObject{}, common.WatchedNamespace)
 	if err != nil {
 		return err
 	}
@@ -55,16 +56,24 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	// Watch for changes to secondary resource Pods and requeue the owner CephOsd
 	err = c.Watch(&source.Kind{Type: &corev1.Pod{}}, &handler.EnqueueRequestForOwner{
 		IsController: true,
-		OwnerType:    &cephv1alpha1.CephOsd{},
-	})
+		OwnerType:    &cephv1beta1.CephOsd{},
+	}, common.WatchedNamespace)
 	if err != nil {
 		return err
 	}
 
-	err = c.Watch(&source.Kind{Type: &cephv1alpha1.CephCluster{}}, &handler.EnqueueRequestsFromMapFunc{
+	err = c.Watch(&source.Kind{Type: &batchv1.Job{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &cephv1beta1.CephOsd{},
+	}, common.WatchedNamespace)
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(&source.Kind{Type: &cephv1beta1.CephCluster{}}, &handler.EnqueueRequestsFromMapFunc{
 		ToRequests: &common.CephClusterEventMapper{Client: mgr.GetClient(), Scheme: mgr.GetScheme(),
-			ApiVersion: cephv1alpha1.SchemeGroupVersion.String(), Kind: "CephOsd"},
-	})
+			ApiVersion: cephv1beta1.SchemeGroupVersion.String(), Kind: "CephOsd"},
+	}, common.WatchedNamespace)
 	if err != nil {
 		return err
 	}
@@ -90,7 +99,7 @@ func (r *ReconcileCephOsd) Reconcile(request reconcile.Request) (reconcile.Resul
 	reqLogger.Info("Reconciling CephOsd")
 
 	// Fetch the CephOsd instance
-	instance := &cephv1alpha1.CephOsd{}
+	instance := &cephv1beta1.CephOsd{}
 	err := r.client.Get(context.TODO(), request.NamespacedName, instance)
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -114,7 +123,8 @@ func (r *ReconcileCephOsd) Reconcile(request reconcile.Request) (reconcile.Resul
 		return reconcile.Result{}, err
 	}
 
-	osdDisabled := !cluster.GetDaemonEnabled(cephv1alpha1.CephDaemonTypeOsd) || instance.GetDisabled()
+	osdDisabled := !cluster.GetDaemonEnabled(cephv1beta1.CephDaemonTypeOsd) || instance.GetDisabled() ||
+		cluster.InMaintenance(instance.Status.NodeName)
 
 	// Return if disabled
 	if osdDisabled {
@@ -141,16 +151,25 @@ func (r *ReconcileCephOsd) Reconcile(request reconcile.Request) (reconcile.Resul
 		return reconcile.Result{}, err
 	}
 
-	err = r.client.Create(context.TODO(), pvc)
-
-	if err != nil && !errors.IsAlreadyExists(err) {
+	err = common.EnsureVolumeClaim(r.client, pvc, instance, instance.Spec.ReclaimPolicy.Or(cluster.GetReclaimPolicy()), cluster)
+	if err != nil {
 		return reconcile.Result{}, err
 	}
 
+	// Prepare the osd volume before launching the daemon
+	if instance.GetState() != cephv1beta1.CephOsdStatePrepared {
+		return r.prepare(instance, cluster)
+	}
+
 	// Create Pod
-	pod := instance.GetPod(cluster.GetOsdImage(), cluster.GetCephConfigMapName(), "ceph-operator-osd")
+	pod := instance.GetPod(cluster.GetOsdImage(), cluster.GetCephConfigMapName(), cluster.GetServiceAccountName(cephv1beta1.CephDaemonTypeOsd), cluster.Spec.Network)
 	pod.Namespace = request.Namespace
 
+	err = cluster.ApplyPodOverrides(cephv1beta1.CephDaemonTypeOsd, &pod.Spec)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
 	if err = controllerutil.SetControllerReference(instance, pod, r.scheme); err != nil {
 		return reconcile.Result{}, err
 	}
@@ -162,12 +181,12 @@ func (r *ReconcileCephOsd) Reconcile(request reconcile.Request) (reconcile.Resul
 
 	// Delete pod?
 
-	return reconcile.Result{}, nil
+	return reconcile.Result{}, r.recordPlacement(instance, cluster)
 
 }
 
-func (r *ReconcileCephOsd) getCephCluster(d *cephv1alpha1.CephOsd) (*cephv1alpha1.CephCluster, error) {
-	cephCluster := &cephv1alpha1.CephCluster{}
+func (r *ReconcileCephOsd) getCephCluster(d *cephv1beta1.CephOsd) (*cephv1beta1.CephCluster, error) {
+	cephCluster := &cephv1beta1.CephCluster{}
 	cephClusterNamespacedName := types.NamespacedName{
 		Name:      d.Spec.ClusterName,
 		Namespace: d.GetNamespace(),
@@ -180,19 +199,19 @@ func (r *ReconcileCephOsd) updateObject(object runtime.Object) error {
 	return r.client.Update(context.TODO(), object)
 }
 
-func updateLabels(d *cephv1alpha1.CephOsd) bool {
+func updateLabels(d *cephv1beta1.CephOsd) bool {
 	var updated bool
 	labels := d.GetLabels()
 	if labels == nil {
 		labels = make(map[string]string)
 	}
-	if val, ok := labels[cephv1alpha1.ClusterNameLabel]; !ok || val != d.Spec.ClusterName {
-		labels[cephv1alpha1.ClusterNameLabel] = d.Spec.ClusterName
+	if val, ok := labels[cephv1beta1.ClusterNameLabel]; !ok || val != d.Spec.ClusterName {
+		labels[cephv1beta1.ClusterNameLabel] = d.Spec.ClusterName
 		d.SetLabels(labels)
 		updated = true
 	}
-	if val, ok := labels[cephv1alpha1.DaemonTypeLabel]; !ok || val != cephv1alpha1.CephDaemonTypeOsd.String() {
-		labels[cephv1alpha1.DaemonTypeLabel] = cephv1alpha1.CephDaemonTypeOsd.String()
+	if val, ok := labels[cephv1beta1.DaemonTypeLabel]; !ok || val != cephv1beta1.CephDaemonTypeOsd.String() {
+		labels[cephv1beta1.DaemonTypeLabel] = cephv1beta1.CephDaemonTypeOsd.String()
 		d.SetLabels(labels)
 		updated = true
 	}
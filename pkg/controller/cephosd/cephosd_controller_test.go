@@ -0,0 +1,52 @@
+package cephosd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/apis"
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func init() {
+	if err := apis.AddToScheme(scheme.Scheme); err != nil {
+		panic(err)
+	}
+}
+
+func TestReconcileStopsOsdsInMaintenance(t *testing.T) {
+	cluster := &cephv1beta1.CephCluster{}
+	cluster.Name = "test"
+	cluster.Namespace = "ceph"
+	cluster.Status.State = cephv1beta1.CephClusterRunning
+	cluster.Status.MaintenanceNodes = []string{"node-a"}
+
+	osd := &cephv1beta1.CephOsd{}
+	osd.Name = "test-osd-1"
+	osd.Namespace = "ceph"
+	osd.Spec.ClusterName = "test"
+	osd.Status.NodeName = "node-a"
+	updateLabels(osd)
+
+	pod := &corev1.Pod{}
+	pod.Name = osd.GetPodName()
+	pod.Namespace = "ceph"
+
+	r := &ReconcileCephOsd{client: fake.NewFakeClient(cluster, osd, pod), scheme: scheme.Scheme}
+
+	_, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ceph", Name: osd.Name}})
+	if err != nil {
+		t.Fatalf("unable to reconcile osd: %v", err)
+	}
+
+	err = r.client.Get(context.TODO(), types.NamespacedName{Namespace: "ceph", Name: pod.Name}, &corev1.Pod{})
+	if !errors.IsNotFound(err) {
+		t.Errorf("expected the osd pod on a node in maintenance to be deleted, got %v", err)
+	}
+}
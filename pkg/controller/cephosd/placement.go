@@ -0,0 +1,68 @@
+package cephosd
+
+import (
+	"context"
+	"fmt"
+
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// recordPlacement records the node running the osd's pod and its failure domain in the osd status, so pods
+// re-created before they're scheduled are labeled with it, and labels the running pod
+func (r *ReconcileCephOsd) recordPlacement(instance *cephv1beta1.CephOsd, cluster *cephv1beta1.CephCluster) error {
+	pod := &corev1.Pod{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: instance.GetNamespace(), Name: instance.GetPodName()}, pod)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if pod.Spec.NodeName == "" {
+		return nil
+	}
+
+	if instance.Status.FailureDomain == "" || instance.Status.NodeName != pod.Spec.NodeName {
+		node := &corev1.Node{}
+		err = r.client.Get(context.TODO(), types.NamespacedName{Name: pod.Spec.NodeName}, node)
+		if err != nil {
+			return err
+		}
+
+		domain, ok := node.GetLabels()[cluster.GetOsdFailureDomainKey()]
+		if !ok {
+			return fmt.Errorf("node %s is missing failure domain label %s", node.GetName(), cluster.GetOsdFailureDomainKey())
+		}
+
+		log.Info("Recording osd placement", "CephOsd", instance.GetName(), "Node", node.GetName(), "FailureDomain", domain)
+		instance.Status.FailureDomain = domain
+		instance.Status.NodeName = node.GetName()
+		err = r.updateObject(instance)
+		if err != nil {
+			return err
+		}
+	}
+
+	labels := map[string]string{}
+	for k, v := range pod.GetLabels() {
+		labels[k] = v
+	}
+
+	changed := false
+	for k, v := range instance.GetPodLabels() {
+		if labels[k] != v {
+			labels[k] = v
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	pod.SetLabels(labels)
+	return r.updateObject(pod)
+}
@@ -0,0 +1,150 @@
+package cephosd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/controller/common"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// errNoPrepareResult is returned when a completed prepare job has no successful pod left to read the result from
+var errNoPrepareResult = fmt.Errorf("no successful pod found for the osd prepare job")
+
+// osdPrepareResult is written by the prepare_osd command to the termination message of the prepare container
+type osdPrepareResult struct {
+	OsdFsid string `json:"osdFsid"`
+	OsdID   *int   `json:"osdId"`
+}
+
+// parsePrepareResult parses the prepare result and checks that the osd was prepared with the expected id
+func parsePrepareResult(message string, expectedID int) (*osdPrepareResult, error) {
+	result := &osdPrepareResult{}
+	err := json.Unmarshal([]byte(message), result)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse osd prepare result '%s': %v", message, err)
+	}
+
+	if result.OsdFsid == "" {
+		return nil, fmt.Errorf("osd prepare result is missing the osd fsid")
+	}
+
+	if result.OsdID == nil {
+		return nil, fmt.Errorf("osd prepare result is missing the osd id")
+	}
+
+	if *result.OsdID != expectedID {
+		return nil, fmt.Errorf("osd was prepared with id %d, expected %d", *result.OsdID, expectedID)
+	}
+
+	return result, nil
+}
+
+// prepare runs the prepare job for the osd and records its result in the osd status
+func (r *ReconcileCephOsd) prepare(instance *cephv1beta1.CephOsd, cluster *cephv1beta1.CephCluster) (reconcile.Result, error) {
+	job := &batchv1.Job{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{
+		Name:      instance.GetPrepareJobName(),
+		Namespace: instance.GetNamespace(),
+	}, job)
+	if errors.IsNotFound(err) {
+		job = instance.GetPrepareJob(cluster.GetOsdImage(), cluster.GetCephConfigMapName(), cluster.GetServiceAccountName(cephv1beta1.CephDaemonTypeOsd), cluster.Spec.Network)
+		job.Namespace = instance.GetNamespace()
+
+		err = cluster.ApplyPodOverrides(cephv1beta1.CephDaemonTypeOsd, &job.Spec.Template.Spec)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		if err = controllerutil.SetControllerReference(instance, job, r.scheme); err != nil {
+			return reconcile.Result{}, err
+		}
+
+		err = r.client.Create(context.TODO(), job)
+		if err != nil && !errors.IsAlreadyExists(err) {
+			return reconcile.Result{}, err
+		}
+
+		instance.SetState(cephv1beta1.CephOsdStatePreparing)
+		return reconcile.Result{}, r.updateObject(instance)
+	}
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	switch {
+	case common.JobHasCondition(job, batchv1.JobFailed):
+		if instance.GetState() == cephv1beta1.CephOsdStatePrepareFailed {
+			return reconcile.Result{}, nil
+		}
+		log.Info("Osd prepare failed, delete the prepare job to retry", "OsdID", instance.Spec.ID, "Job", job.GetName())
+		instance.SetState(cephv1beta1.CephOsdStatePrepareFailed)
+		return reconcile.Result{}, r.updateObject(instance)
+
+	case common.JobHasCondition(job, batchv1.JobComplete):
+		message, err := r.getPrepareResult(job)
+		if err == errNoPrepareResult {
+			// The job's pods have been garbage collected, re-run the job to get the result again
+			log.Info("Osd prepare job has no pods left, re-creating it", "OsdID", instance.Spec.ID, "Job", job.GetName())
+			err = r.client.Delete(context.TODO(), job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+			if err != nil && !errors.IsNotFound(err) {
+				return reconcile.Result{}, err
+			}
+			return reconcile.Result{Requeue: true}, nil
+		}
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		result, err := parsePrepareResult(message, instance.Spec.ID)
+		if err != nil {
+			if instance.GetState() == cephv1beta1.CephOsdStatePrepareFailed {
+				return reconcile.Result{}, nil
+			}
+			log.Info("Osd prepare returned an unusable result, delete the prepare job to retry", "OsdID", instance.Spec.ID, "Job", job.GetName(), "Error", err.Error())
+			instance.SetState(cephv1beta1.CephOsdStatePrepareFailed)
+			return reconcile.Result{}, r.updateObject(instance)
+		}
+
+		instance.Status.OsdFsid = result.OsdFsid
+		instance.Status.OsdID = *result.OsdID
+		instance.SetState(cephv1beta1.CephOsdStatePrepared)
+		return reconcile.Result{}, r.updateObject(instance)
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// getPrepareResult returns the termination message of the job's successful pod, which holds the prepare result
+func (r *ReconcileCephOsd) getPrepareResult(job *batchv1.Job) (string, error) {
+	pods := &corev1.PodList{}
+	listOptions := &client.ListOptions{Namespace: job.GetNamespace()}
+	listOptions.MatchingLabels(map[string]string{"job-name": job.GetName()})
+
+	err := r.client.List(context.TODO(), listOptions, pods)
+	if err != nil {
+		return "", err
+	}
+
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodSucceeded {
+			continue
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.State.Terminated != nil && status.State.Terminated.ExitCode == 0 {
+				return status.State.Terminated.Message, nil
+			}
+		}
+	}
+
+	return "", errNoPrepareResult
+}
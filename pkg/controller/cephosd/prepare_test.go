@@ -0,0 +1,87 @@
+package cephosd
+
+import (
+	"context"
+	"testing"
+
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestParsePrepareResult(t *testing.T) {
+	result, err := parsePrepareResult(`{"osdFsid": "5d2c0d3e-6f1a-4b8e-9a43-3f3c1a7d2b10", "osdId": 4}`, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.OsdFsid != "5d2c0d3e-6f1a-4b8e-9a43-3f3c1a7d2b10" {
+		t.Errorf("Got osd fsid '%s'", result.OsdFsid)
+	}
+
+	if *result.OsdID != 4 {
+		t.Errorf("Got osd id %d expected 4", *result.OsdID)
+	}
+
+	for _, message := range []string{
+		"",
+		"not json",
+		`{"osdId": 4}`,
+		`{"osdFsid": "5d2c0d3e-6f1a-4b8e-9a43-3f3c1a7d2b10"}`,
+		`{"osdFsid": "5d2c0d3e-6f1a-4b8e-9a43-3f3c1a7d2b10", "osdId": 5}`,
+	} {
+		if _, err := parsePrepareResult(message, 4); err == nil {
+			t.Errorf("Expected error parsing '%s'", message)
+		}
+	}
+}
+
+func TestPrepareRecreatesJobWithoutPods(t *testing.T) {
+	cluster := &cephv1beta1.CephCluster{}
+	cluster.Name = "test"
+	cluster.Namespace = "ceph"
+
+	osd := &cephv1beta1.CephOsd{}
+	osd.Name = "test-osd-1"
+	osd.Namespace = "ceph"
+	osd.Spec.ClusterName = "test"
+	osd.Spec.ID = 1
+	osd.SetState(cephv1beta1.CephOsdStatePreparing)
+
+	job := osd.GetPrepareJob("ceph/daemon:latest", "ceph-conf", "ceph-test-osd", cephv1beta1.NetworkSpec{})
+	job.Namespace = "ceph"
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+
+	r := &ReconcileCephOsd{client: fake.NewFakeClient(cluster, osd, job), scheme: scheme.Scheme}
+
+	result, err := r.prepare(osd, cluster)
+	if err != nil {
+		t.Fatalf("unable to prepare osd: %v", err)
+	}
+	if !result.Requeue {
+		t.Errorf("expected the osd to be requeued once the prepare job is deleted")
+	}
+
+	jobName := types.NamespacedName{Namespace: "ceph", Name: job.Name}
+	err = r.client.Get(context.TODO(), jobName, &batchv1.Job{})
+	if !errors.IsNotFound(err) {
+		t.Fatalf("expected the prepare job without pods to be deleted, got %v", err)
+	}
+
+	_, err = r.prepare(osd, cluster)
+	if err != nil {
+		t.Fatalf("unable to prepare osd: %v", err)
+	}
+	recreated := &batchv1.Job{}
+	err = r.client.Get(context.TODO(), jobName, recreated)
+	if err != nil {
+		t.Fatalf("expected the prepare job to be re-created: %v", err)
+	}
+	if osd.GetState() != cephv1beta1.CephOsdStatePreparing {
+		t.Errorf("expected the osd to be preparing, got %s", osd.GetState())
+	}
+}
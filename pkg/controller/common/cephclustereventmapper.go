@@ -3,7 +3,7 @@ package common
 import (
 	"context"
 
-	cephv1alpha1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1alpha1"
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -29,12 +29,12 @@ func (m *CephClusterEventMapper) Map(o handler.MapObject) []reconcile.Request {
 	req := make([]reconcile.Request, 0, 1)
 	switch obj := o.Object.(type) {
 
-	case *cephv1alpha1.CephCluster:
+	case *cephv1beta1.CephCluster:
 		objects := &unstructured.UnstructuredList{}
 		objects.SetKind(m.Kind)
 		objects.SetAPIVersion(m.ApiVersion)
-		listOptions := &client.ListOptions{}
-		listOptions.MatchingLabels(map[string]string{cephv1alpha1.ClusterNameLabel: obj.GetName()})
+		listOptions := &client.ListOptions{Namespace: obj.GetNamespace()}
+		listOptions.MatchingLabels(map[string]string{cephv1beta1.ClusterNameLabel: obj.GetName()})
 
 		err := m.Client.List(context.TODO(), listOptions, objects)
 		if err != nil {
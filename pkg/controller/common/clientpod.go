@@ -0,0 +1,59 @@
+package common
+
+import (
+	"context"
+
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/ceph"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// GetClientAdmin returns an admin interface running in the cluster's client pod as clientName, creating the
+// pod with the keyring from the named secret if it doesn't exist.  The pod is owned by the cluster.  Returns
+// nil until the pod is running.
+func GetClientAdmin(c client.Client, scheme *runtime.Scheme, runner ceph.CommandRunner, cluster *cephv1beta1.CephCluster,
+	keyringSecretName, clientName string) (*ceph.Admin, error) {
+
+	pod := &corev1.Pod{}
+	err := c.Get(context.TODO(), types.NamespacedName{Namespace: cluster.GetNamespace(), Name: cluster.GetClientPodName()}, pod)
+	if errors.IsNotFound(err) {
+		pod = cluster.GetClientPod(keyringSecretName)
+		err = controllerutil.SetControllerReference(cluster, pod, scheme)
+		if err != nil {
+			return nil, err
+		}
+
+		err = c.Create(context.TODO(), pod)
+		if err != nil && !errors.IsAlreadyExists(err) {
+			return nil, err
+		}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if pod.Status.Phase != corev1.PodRunning || pod.GetDeletionTimestamp() != nil {
+		return nil, nil
+	}
+
+	return ceph.NewClientAdmin(runner, pod.GetNamespace(), pod.GetName(), cluster.GetName(), clientName), nil
+}
+
+// DeleteClientPod deletes the cluster's client pod, if it exists
+func DeleteClientPod(c client.Client, cluster *cephv1beta1.CephCluster) error {
+	pod := &corev1.Pod{}
+	pod.Name = cluster.GetClientPodName()
+	pod.Namespace = cluster.GetNamespace()
+
+	err := c.Delete(context.TODO(), pod)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package common
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HasFinalizer returns true if the finalizer is set on the object
+func HasFinalizer(o metav1.Object, finalizer string) bool {
+	for _, f := range o.GetFinalizers() {
+		if f == finalizer {
+			return true
+		}
+	}
+	return false
+}
+
+// AddFinalizer adds the finalizer to the object, returning false if it was already set
+func AddFinalizer(o metav1.Object, finalizer string) bool {
+	if HasFinalizer(o, finalizer) {
+		return false
+	}
+	o.SetFinalizers(append(o.GetFinalizers(), finalizer))
+	return true
+}
+
+// RemoveFinalizer removes the finalizer from the object, returning false if it wasn't set
+func RemoveFinalizer(o metav1.Object, finalizer string) bool {
+	finalizers := make([]string, 0, len(o.GetFinalizers()))
+	for _, f := range o.GetFinalizers() {
+		if f != finalizer {
+			finalizers = append(finalizers, f)
+		}
+	}
+	if len(finalizers) == len(o.GetFinalizers()) {
+		return false
+	}
+	o.SetFinalizers(finalizers)
+	return true
+}
@@ -0,0 +1,54 @@
+package common
+
+import (
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// watchNamespaces are the namespaces events are accepted from, every namespace is watched if it's empty
+var watchNamespaces = map[string]bool{}
+
+// ParseWatchNamespaces splits a comma separated WATCH_NAMESPACE value.  No namespaces are returned when all
+// namespaces should be watched.
+func ParseWatchNamespaces(value string) []string {
+	namespaces := []string{}
+	for _, namespace := range strings.Split(value, ",") {
+		namespace = strings.TrimSpace(namespace)
+		if namespace != "" {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces
+}
+
+// SetWatchNamespaces limits the controllers to events from the namespaces, it must be called before the
+// controllers are added to the manager
+func SetWatchNamespaces(namespaces []string) {
+	watchNamespaces = map[string]bool{}
+	for _, namespace := range namespaces {
+		watchNamespaces[namespace] = true
+	}
+}
+
+// Watched returns true if the operator manages objects in the namespace
+func Watched(namespace string) bool {
+	return len(watchNamespaces) == 0 || watchNamespaces[namespace]
+}
+
+// WatchedNamespace filters out events for objects outside of the watched namespaces
+var WatchedNamespace = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return Watched(e.Meta.GetNamespace())
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		return Watched(e.Meta.GetNamespace())
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return Watched(e.MetaNew.GetNamespace())
+	},
+	GenericFunc: func(e event.GenericEvent) bool {
+		return Watched(e.Meta.GetNamespace())
+	},
+}
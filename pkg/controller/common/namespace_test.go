@@ -0,0 +1,41 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestParseWatchNamespaces(t *testing.T) {
+	cases := []struct {
+		Value    string
+		Expected []string
+	}{
+		{Value: "", Expected: []string{}},
+		{Value: "ceph", Expected: []string{"ceph"}},
+		{Value: "ceph, ceph-testing,", Expected: []string{"ceph", "ceph-testing"}},
+	}
+
+	for _, c := range cases {
+		if diff := deep.Equal(ParseWatchNamespaces(c.Value), c.Expected); diff != nil {
+			t.Errorf("%q: %v", c.Value, diff)
+		}
+	}
+}
+
+func TestWatched(t *testing.T) {
+	defer SetWatchNamespaces(nil)
+
+	SetWatchNamespaces(nil)
+	if !Watched("ceph") {
+		t.Errorf("expected every namespace to be watched")
+	}
+
+	SetWatchNamespaces([]string{"ceph", "ceph-testing"})
+	if !Watched("ceph-testing") {
+		t.Errorf("expected listed namespace to be watched")
+	}
+	if Watched("default") {
+		t.Errorf("expected unlisted namespace to be ignored")
+	}
+}
@@ -0,0 +1,86 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ApplyReclaimPolicy labels the object for adoption and sets its owner references for the reclaim policy.
+// The owner's reference is taken from desired, Retain removes it from the object and Delete adds it.  A
+// reference left by a deleted owner of the same kind and name is dropped.  Returns true if existing was
+// changed to match the policy.
+func ApplyReclaimPolicy(existing, desired, owner metav1.Object, policy cephv1beta1.ReclaimPolicy, cluster *cephv1beta1.CephCluster) bool {
+	labels := map[string]string{}
+	for k, v := range existing.GetLabels() {
+		labels[k] = v
+	}
+	for k, v := range cluster.GetRetainLabels() {
+		labels[k] = v
+	}
+
+	var ownerRef *metav1.OwnerReference
+	for _, ref := range desired.GetOwnerReferences() {
+		if ref.UID == owner.GetUID() {
+			ownerRef = ref.DeepCopy()
+		}
+	}
+
+	var refs []metav1.OwnerReference
+	for _, ref := range existing.GetOwnerReferences() {
+		if ref.UID == owner.GetUID() {
+			continue
+		}
+		if ownerRef != nil && ref.Kind == ownerRef.Kind && ref.Name == ownerRef.Name {
+			continue
+		}
+		refs = append(refs, ref)
+	}
+	if ownerRef != nil && policy != cephv1beta1.ReclaimPolicyRetain {
+		refs = append(refs, *ownerRef)
+	}
+
+	if reflect.DeepEqual(labels, existing.GetLabels()) && reflect.DeepEqual(refs, existing.GetOwnerReferences()) {
+		return false
+	}
+
+	existing.SetLabels(labels)
+	existing.SetOwnerReferences(refs)
+	return true
+}
+
+// EnsureVolumeClaim creates the PVC, or adopts an existing one, with the owner references and labels for
+// the reclaim policy.  A PVC retained from a different cluster is never adopted.
+func EnsureVolumeClaim(c client.Client, pvc *corev1.PersistentVolumeClaim, owner metav1.Object,
+	policy cephv1beta1.ReclaimPolicy, cluster *cephv1beta1.CephCluster) error {
+
+	existing := &corev1.PersistentVolumeClaim{}
+	err := c.Get(context.TODO(), types.NamespacedName{Namespace: pvc.GetNamespace(), Name: pvc.GetName()}, existing)
+	if errors.IsNotFound(err) {
+		ApplyReclaimPolicy(pvc, pvc, owner, policy, cluster)
+		err = c.Create(context.TODO(), pvc)
+		if errors.IsAlreadyExists(err) {
+			return nil
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if !cluster.CheckRetainLabels(existing) {
+		return fmt.Errorf("refusing to adopt volume claim %s, it was retained from another cluster", existing.GetName())
+	}
+
+	if !ApplyReclaimPolicy(existing, pvc, owner, policy, cluster) {
+		return nil
+	}
+	return c.Update(context.TODO(), existing)
+}
@@ -0,0 +1,105 @@
+package common
+
+import (
+	"testing"
+
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	"github.com/go-test/deep"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestApplyReclaimPolicy(t *testing.T) {
+	cluster := &cephv1beta1.CephCluster{}
+	cluster.Name = "test"
+	cluster.Spec.Fsid = "3f6d3ee2-9cbb-4e3b-9a3c-0b9cd0a8b5e1"
+
+	owner := &cephv1beta1.CephOsd{}
+	owner.Name = "test-osd-1"
+	owner.UID = "new"
+
+	ownerRef := metav1.OwnerReference{Kind: "CephOsd", Name: "test-osd-1", UID: "new"}
+	staleRef := metav1.OwnerReference{Kind: "CephOsd", Name: "test-osd-1", UID: "old"}
+	otherRef := metav1.OwnerReference{Kind: "ConfigMap", Name: "other", UID: "other"}
+
+	retainLabels := map[string]string{
+		cephv1beta1.ClusterNameLabel: "test",
+		cephv1beta1.FsidLabel:        "3f6d3ee2-9cbb-4e3b-9a3c-0b9cd0a8b5e1",
+	}
+
+	cases := []struct {
+		Name         string
+		Existing     []metav1.OwnerReference
+		Labels       map[string]string
+		Policy       cephv1beta1.ReclaimPolicy
+		ExpectedRefs []metav1.OwnerReference
+		Changed      bool
+	}{
+		{Name: "delete-new", Policy: cephv1beta1.ReclaimPolicyDelete, ExpectedRefs: []metav1.OwnerReference{ownerRef}, Changed: true},
+		{Name: "retain-new", Policy: cephv1beta1.ReclaimPolicyRetain, Changed: true},
+		{
+			Name:         "retain-owned",
+			Existing:     []metav1.OwnerReference{otherRef, ownerRef},
+			Labels:       retainLabels,
+			Policy:       cephv1beta1.ReclaimPolicyRetain,
+			ExpectedRefs: []metav1.OwnerReference{otherRef},
+			Changed:      true,
+		},
+		{
+			Name:         "adopt-stale",
+			Existing:     []metav1.OwnerReference{staleRef},
+			Labels:       retainLabels,
+			Policy:       cephv1beta1.ReclaimPolicyDelete,
+			ExpectedRefs: []metav1.OwnerReference{ownerRef},
+			Changed:      true,
+		},
+		{
+			Name:         "unchanged",
+			Existing:     []metav1.OwnerReference{ownerRef},
+			Labels:       retainLabels,
+			Policy:       cephv1beta1.ReclaimPolicyDelete,
+			ExpectedRefs: []metav1.OwnerReference{ownerRef},
+		},
+		{Name: "unchanged-retained", Labels: retainLabels, Policy: cephv1beta1.ReclaimPolicyRetain},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			existing := &corev1.PersistentVolumeClaim{}
+			existing.OwnerReferences = c.Existing
+			existing.Labels = c.Labels
+
+			desired := &corev1.PersistentVolumeClaim{}
+			desired.OwnerReferences = []metav1.OwnerReference{ownerRef}
+
+			changed := ApplyReclaimPolicy(existing, desired, owner, c.Policy, cluster)
+			if changed != c.Changed {
+				t.Errorf("expected changed to be %t", c.Changed)
+			}
+
+			if diff := deep.Equal(existing.OwnerReferences, c.ExpectedRefs); diff != nil {
+				t.Error(diff)
+			}
+
+			if diff := deep.Equal(existing.Labels, retainLabels); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestCheckRetainLabels(t *testing.T) {
+	cluster := &cephv1beta1.CephCluster{}
+	cluster.Name = "test"
+	cluster.Spec.Fsid = "3f6d3ee2-9cbb-4e3b-9a3c-0b9cd0a8b5e1"
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	if !cluster.CheckRetainLabels(pvc) {
+		t.Errorf("expected an unlabeled object to be adoptable")
+	}
+
+	pvc.Labels = map[string]string{cephv1beta1.FsidLabel: "8c2a6a4e-5b1f-4c39-9d7c-1f0f3e0c2b11"}
+	if cluster.CheckRetainLabels(pvc) {
+		t.Errorf("expected an object retained from another fsid to be refused")
+	}
+}
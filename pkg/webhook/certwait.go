@@ -0,0 +1,72 @@
+package webhook
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+const (
+	// serverCertName and serverKeyName are the keys the webhook server's certificate writer stores the server
+	// certificate under, the secret is mounted with each key as a file
+	serverCertName    = "cert.pem"
+	serverKeyName     = "key.pem"
+	certMountInterval = 5 * time.Second
+)
+
+// certWaitingManager adds the webhook server to the manager wrapped in a certWaitingServer
+type certWaitingManager struct {
+	manager.Manager
+}
+
+func (m certWaitingManager) Add(r manager.Runnable) error {
+	if server, ok := r.(*webhook.Server); ok {
+		r = &certWaitingServer{Server: server}
+	}
+	return m.Manager.Add(r)
+}
+
+// certWaitingServer provisions the webhook server's certificate and waits for the kubelet to mount it before
+// serving.  The certificate secret is empty the first time the operator starts, serving straight away would
+// fail to load the certificate and restart the operator until the kubelet synced the secret.
+type certWaitingServer struct {
+	*webhook.Server
+}
+
+func (s *certWaitingServer) Start(stop <-chan struct{}) error {
+	err := s.InstallWebhookManifests()
+	if err != nil {
+		return err
+	}
+
+	if !certMounted(s.CertDir) {
+		log.Info("Waiting for the webhook certificate to be mounted", "CertDir", s.CertDir)
+	}
+	err = wait.PollImmediateUntil(certMountInterval, func() (bool, error) {
+		return certMounted(s.CertDir), nil
+	}, stop)
+	if err == wait.ErrWaitTimeout {
+		// The manager is stopping
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.Server.Start(stop)
+}
+
+// certMounted returns true if the server certificate and key are in dir
+func certMounted(dir string) bool {
+	for _, name := range []string{serverCertName, serverKeyName} {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil || info.Size() == 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,31 @@
+package webhook
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCertMounted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "webhook-cert")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if certMounted(dir) {
+		t.Errorf("empty directory reported as mounted")
+	}
+
+	for _, name := range []string{serverCertName, serverKeyName} {
+		err = ioutil.WriteFile(filepath.Join(dir, name), []byte("pem"), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if !certMounted(dir) {
+		t.Errorf("certificate not reported as mounted")
+	}
+}
@@ -0,0 +1,175 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const conversionPath = "/convert"
+
+// conversionReview mirrors the apiextensions.k8s.io/v1beta1 ConversionReview, the apiextensions api isn't vendored
+type conversionReview struct {
+	metav1.TypeMeta `json:",inline"`
+	Request         *conversionRequest  `json:"request,omitempty"`
+	Response        *conversionResponse `json:"response,omitempty"`
+}
+
+type conversionRequest struct {
+	UID               types.UID              `json:"uid"`
+	DesiredAPIVersion string                 `json:"desiredAPIVersion"`
+	Objects           []runtime.RawExtension `json:"objects"`
+}
+
+type conversionResponse struct {
+	UID              types.UID              `json:"uid"`
+	ConvertedObjects []runtime.RawExtension `json:"convertedObjects"`
+	Result           metav1.Status          `json:"result"`
+}
+
+// hub is the api version every other version converts to and from
+type hub interface {
+	runtime.Object
+	Hub()
+}
+
+// convertible is an api version that converts through the hub
+type convertible interface {
+	runtime.Object
+	ConvertTo(hub runtime.Object) error
+	ConvertFrom(hub runtime.Object) error
+}
+
+// conversionHandler serves CustomResourceDefinition conversion requests
+type conversionHandler struct {
+	scheme *runtime.Scheme
+}
+
+func (h *conversionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	review := &conversionReview{}
+	err := json.NewDecoder(r.Body).Decode(review)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if review.Request == nil {
+		http.Error(w, "conversion request is missing", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = h.convertAll(review.Request)
+	review.Request = nil
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(review)
+	if err != nil {
+		log.Error(err, "Unable to write conversion response")
+	}
+}
+
+func (h *conversionHandler) convertAll(req *conversionRequest) *conversionResponse {
+	resp := &conversionResponse{UID: req.UID}
+
+	for _, raw := range req.Objects {
+		obj, err := h.convert(raw.Raw, req.DesiredAPIVersion)
+		if err != nil {
+			log.Error(err, "Conversion failed", "DesiredAPIVersion", req.DesiredAPIVersion)
+			resp.ConvertedObjects = nil
+			resp.Result = metav1.Status{Status: metav1.StatusFailure, Message: err.Error()}
+			return resp
+		}
+		resp.ConvertedObjects = append(resp.ConvertedObjects, runtime.RawExtension{Object: obj})
+	}
+
+	resp.Result = metav1.Status{Status: metav1.StatusSuccess}
+	return resp
+}
+
+// convert decodes a serialized object and converts it to the desired api version
+func (h *conversionHandler) convert(data []byte, desiredAPIVersion string) (runtime.Object, error) {
+	typeMeta := &metav1.TypeMeta{}
+	err := json.Unmarshal(data, typeMeta)
+	if err != nil {
+		return nil, err
+	}
+
+	srcGVK := typeMeta.GroupVersionKind()
+	src, err := h.scheme.New(srcGVK)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(data, src)
+	if err != nil {
+		return nil, err
+	}
+
+	dstGV, err := schema.ParseGroupVersion(desiredAPIVersion)
+	if err != nil {
+		return nil, err
+	}
+	dstGVK := dstGV.WithKind(srcGVK.Kind)
+	if dstGVK == srcGVK {
+		return src, nil
+	}
+
+	dst, err := h.scheme.New(dstGVK)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case isHub(src) && isConvertible(dst):
+		err = dst.(convertible).ConvertFrom(src)
+	case isConvertible(src) && isHub(dst):
+		err = src.(convertible).ConvertTo(dst)
+	case isConvertible(src) && isConvertible(dst):
+		err = h.convertThroughHub(src.(convertible), dst.(convertible))
+	default:
+		err = fmt.Errorf("no conversion from %s to %s", srcGVK, dstGVK)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dst.GetObjectKind().SetGroupVersionKind(dstGVK)
+	return dst, nil
+}
+
+// convertThroughHub converts between two versions that aren't the hub
+func (h *conversionHandler) convertThroughHub(src, dst convertible) error {
+	for gvk := range h.scheme.AllKnownTypes() {
+		if gvk.GroupKind() != src.GetObjectKind().GroupVersionKind().GroupKind() {
+			continue
+		}
+
+		obj, err := h.scheme.New(gvk)
+		if err != nil {
+			return err
+		}
+		if !isHub(obj) {
+			continue
+		}
+
+		err = src.ConvertTo(obj)
+		if err != nil {
+			return err
+		}
+		return dst.ConvertFrom(obj)
+	}
+	return fmt.Errorf("no conversion hub for %s", src.GetObjectKind().GroupVersionKind().GroupKind())
+}
+
+func isHub(obj runtime.Object) bool {
+	_, ok := obj.(hub)
+	return ok
+}
+
+func isConvertible(obj runtime.Object) bool {
+	_, ok := obj.(convertible)
+	return ok
+}
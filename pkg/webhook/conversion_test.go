@@ -0,0 +1,70 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/apis"
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestConversionHandler(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+
+	mon := []byte(`{
+		"apiVersion": "ceph.k8s.pgc.umn.edu/v1alpha1",
+		"kind": "CephMon",
+		"metadata": {"name": "test-mon-a", "namespace": "ceph"},
+		"spec": {"clusterName": "test", "id": "a", "pvSelectorString": "ceph.k8s.pgc.umn.edu/mon=a"},
+		"status": {"monState": "In Quorum", "podIP": "10.244.0.5", "initalMember": true}
+	}`)
+	review := conversionReview{
+		Request: &conversionRequest{
+			UID:               "1234",
+			DesiredAPIVersion: cephv1beta1.SchemeGroupVersion.String(),
+			Objects:           []runtime.RawExtension{{Raw: mon}},
+		},
+	}
+	body, _ := json.Marshal(review)
+
+	recorder := httptest.NewRecorder()
+	handler := &conversionHandler{scheme: scheme}
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, conversionPath, bytes.NewReader(body)))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("unexpected response code %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	response := conversionReview{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("unable to decode response: %v", err)
+	}
+	if response.Response == nil || response.Response.UID != "1234" || response.Response.Result.Status != metav1.StatusSuccess {
+		t.Fatalf("unexpected response: %v", response.Response)
+	}
+	if len(response.Response.ConvertedObjects) != 1 {
+		t.Fatalf("expected 1 converted object, got %d", len(response.Response.ConvertedObjects))
+	}
+
+	converted := &cephv1beta1.CephMon{}
+	if err := json.Unmarshal(response.Response.ConvertedObjects[0].Raw, converted); err != nil {
+		t.Fatalf("unable to decode converted object: %v", err)
+	}
+
+	if converted.APIVersion != cephv1beta1.SchemeGroupVersion.String() || converted.Kind != "CephMon" {
+		t.Errorf("unexpected type: %s %s", converted.APIVersion, converted.Kind)
+	}
+	if !converted.Status.InitialMember || converted.Status.PodIP != "10.244.0.5" {
+		t.Errorf("unexpected status: %v", converted.Status)
+	}
+	if converted.Spec.PvSelector == nil || converted.Spec.PvSelector.MatchLabels["ceph.k8s.pgc.umn.edu/mon"] != "a" {
+		t.Errorf("unexpected pv selector: %v", converted.Spec.PvSelector)
+	}
+}
@@ -0,0 +1,121 @@
+package webhook
+
+import (
+	"context"
+	"encoding/base64"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// caCertKey is the key the webhook server's certificate writer stores the CA under
+	caCertKey        = "ca-cert.pem"
+	caInjectInterval = time.Minute
+)
+
+var crdGVK = schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1beta1", Kind: "CustomResourceDefinition"}
+
+// conversionConfigInjector points the conversion webhooks of the CRDs at the webhook service, trusting the
+// CA the webhook server's certificate was issued by.  The CA isn't known until the server has provisioned
+// its certificate, so the CRDs are checked periodically.
+type conversionConfigInjector struct {
+	client   client.Client
+	crdNames []string
+	secret   types.NamespacedName
+	service  types.NamespacedName
+}
+
+func (i *conversionConfigInjector) Start(stop <-chan struct{}) error {
+	wait.Until(func() {
+		err := i.inject()
+		if err != nil {
+			log.Error(err, "Unable to configure the CRD conversion webhook")
+		}
+	}, caInjectInterval, stop)
+	return nil
+}
+
+func (i *conversionConfigInjector) inject() error {
+	secret := &corev1.Secret{}
+	err := i.client.Get(context.TODO(), i.secret, secret)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	ca, ok := secret.Data[caCertKey]
+	if !ok {
+		return nil
+	}
+
+	clientConfig := map[string]interface{}{
+		"caBundle": base64.StdEncoding.EncodeToString(ca),
+		"service": map[string]interface{}{
+			"namespace": i.service.Namespace,
+			"name":      i.service.Name,
+			"path":      conversionPath,
+		},
+	}
+
+	for _, name := range i.crdNames {
+		crd := &unstructured.Unstructured{}
+		crd.SetGroupVersionKind(crdGVK)
+		err := i.client.Get(context.TODO(), types.NamespacedName{Name: name}, crd)
+		if err != nil {
+			return err
+		}
+
+		// The CRDs for apiservers before 1.15 only serve the storage version, and those apiservers reject conversion
+		versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+		if len(versions) < 2 {
+			continue
+		}
+
+		current, _, _ := unstructured.NestedMap(crd.Object, "spec", "conversion", "webhookClientConfig")
+		strategy, _, _ := unstructured.NestedString(crd.Object, "spec", "conversion", "strategy")
+		if strategy == "Webhook" && equalClientConfig(current, clientConfig) {
+			continue
+		}
+
+		err = unstructured.SetNestedField(crd.Object, "Webhook", "spec", "conversion", "strategy")
+		if err != nil {
+			return err
+		}
+		err = unstructured.SetNestedField(crd.Object, clientConfig, "spec", "conversion", "webhookClientConfig")
+		if err != nil {
+			return err
+		}
+
+		log.Info("Configuring CRD conversion webhook", "CRD", name)
+		err = i.client.Update(context.TODO(), crd)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func equalClientConfig(current, desired map[string]interface{}) bool {
+	if current["caBundle"] != desired["caBundle"] {
+		return false
+	}
+
+	currentService, _ := current["service"].(map[string]interface{})
+	desiredService := desired["service"].(map[string]interface{})
+	for key, value := range desiredService {
+		if currentService[key] != value {
+			return false
+		}
+	}
+	return true
+}
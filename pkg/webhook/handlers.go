@@ -0,0 +1,201 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	atypes "sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+)
+
+// defaultingHandler fills in unset CephCluster fields
+type defaultingHandler struct {
+	scheme *runtime.Scheme
+}
+
+func (h *defaultingHandler) Handle(ctx context.Context, req atypes.Request) atypes.Response {
+	patch, err := h.defaultPatch(req.AdmissionRequest.Object.Raw)
+	if err != nil {
+		return admission.ErrorResponse(http.StatusBadRequest, err)
+	}
+	return patch
+}
+
+// defaultPatch defaults the cluster as v1beta1 and patches it back in the api version it was sent in
+func (h *defaultingHandler) defaultPatch(data []byte) (atypes.Response, error) {
+	cluster := &cephv1beta1.CephCluster{}
+	original, err := decodeVersioned(h.scheme, data, cluster)
+	if err != nil {
+		return atypes.Response{}, err
+	}
+
+	defaulted := cluster.DeepCopy()
+	defaulted.Default()
+
+	versioned, ok := original.(convertible)
+	if !ok {
+		return admission.PatchResponse(cluster, defaulted), nil
+	}
+
+	patched := versioned.DeepCopyObject().(convertible)
+	err = patched.ConvertFrom(defaulted)
+	if err != nil {
+		return atypes.Response{}, err
+	}
+	patched.GetObjectKind().SetGroupVersionKind(original.GetObjectKind().GroupVersionKind())
+
+	return admission.PatchResponse(original, patched), nil
+}
+
+// validatingHandler rejects invalid specs and changes to immutable fields
+type validatingHandler struct {
+	newObject func() cephv1beta1.Validator
+	scheme    *runtime.Scheme
+	client    client.Client
+}
+
+func (h *validatingHandler) InjectClient(c client.Client) error {
+	h.client = c
+	return nil
+}
+
+func (h *validatingHandler) Handle(ctx context.Context, req atypes.Request) atypes.Response {
+	obj, err := h.decode(req.AdmissionRequest.Object.Raw)
+	if err != nil {
+		return admission.ErrorResponse(http.StatusBadRequest, err)
+	}
+
+	err = h.validate(ctx, req.AdmissionRequest, obj)
+	if err != nil {
+		return admission.ValidationResponse(false, err.Error())
+	}
+
+	return admission.ValidationResponse(true, "")
+}
+
+func (h *validatingHandler) validate(ctx context.Context, req *admissionv1beta1.AdmissionRequest, obj cephv1beta1.Validator) error {
+	if req.Operation == admissionv1beta1.Update {
+		old, err := h.decode(req.OldObject.Raw)
+		if err != nil {
+			return err
+		}
+
+		// Status and metadata writes, like the operator's own, are allowed on objects created before a rule existed
+		if specUnchanged(old, obj) {
+			return nil
+		}
+
+		err = obj.ValidateUpdate(old)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := obj.Validate()
+	if err != nil {
+		return err
+	}
+
+	switch v := obj.(type) {
+	case *cephv1beta1.CephOsd:
+		return h.validateOsdID(ctx, v)
+	case *cephv1beta1.CephCluster:
+		return h.validateMonServiceName(ctx, v)
+	}
+
+	return nil
+}
+
+// decode decodes a serialized object of any served api version, converting it to v1beta1
+func (h *validatingHandler) decode(data []byte) (cephv1beta1.Validator, error) {
+	obj := h.newObject()
+	_, err := decodeVersioned(h.scheme, data, obj)
+	return obj, err
+}
+
+// decodeVersioned decodes a serialized object of any served api version into the v1beta1 obj, returning the
+// object as it was sent
+func decodeVersioned(scheme *runtime.Scheme, data []byte, obj runtime.Object) (runtime.Object, error) {
+	typeMeta := &metav1.TypeMeta{}
+	err := json.Unmarshal(data, typeMeta)
+	if err != nil {
+		return nil, err
+	}
+
+	gvk := typeMeta.GroupVersionKind()
+	if gvk.Version == "" || gvk.GroupVersion() == cephv1beta1.SchemeGroupVersion {
+		err = json.Unmarshal(data, obj)
+		return obj, err
+	}
+
+	src, err := scheme.New(gvk)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(data, src)
+	if err != nil {
+		return nil, err
+	}
+
+	versioned, ok := src.(convertible)
+	if !ok {
+		return nil, fmt.Errorf("no conversion from %s to %s", gvk, cephv1beta1.SchemeGroupVersion)
+	}
+	err = versioned.ConvertTo(obj)
+	return src, err
+}
+
+// specUnchanged returns true if both objects have the same spec
+func specUnchanged(old, obj runtime.Object) bool {
+	oldSpec := reflect.ValueOf(old).Elem().FieldByName("Spec")
+	newSpec := reflect.ValueOf(obj).Elem().FieldByName("Spec")
+	return oldSpec.IsValid() && newSpec.IsValid() && reflect.DeepEqual(oldSpec.Interface(), newSpec.Interface())
+}
+
+// validateMonServiceName rejects a cluster whose monitor service name is already used by another cluster in
+// the same namespace
+func (h *validatingHandler) validateMonServiceName(ctx context.Context, cluster *cephv1beta1.CephCluster) error {
+	if cluster.Spec.External {
+		return nil
+	}
+
+	clusters := &cephv1beta1.CephClusterList{}
+	err := h.client.List(ctx, &client.ListOptions{Namespace: cluster.GetNamespace()}, clusters)
+	if err != nil {
+		return err
+	}
+
+	for _, other := range clusters.Items {
+		if other.GetName() != cluster.GetName() && !other.Spec.External && other.Spec.MonServiceName == cluster.Spec.MonServiceName {
+			return fmt.Errorf("spec.monServiceName: %s is already used by %s", cluster.Spec.MonServiceName, other.GetName())
+		}
+	}
+
+	return nil
+}
+
+// validateOsdID rejects an osd whose id is already used by another osd in the same cluster
+func (h *validatingHandler) validateOsdID(ctx context.Context, osd *cephv1beta1.CephOsd) error {
+	osds := &cephv1beta1.CephOsdList{}
+	err := h.client.List(ctx, &client.ListOptions{Namespace: osd.GetNamespace()}, osds)
+	if err != nil {
+		return err
+	}
+
+	for _, other := range osds.Items {
+		if other.GetName() != osd.GetName() && other.Spec.ClusterName == osd.Spec.ClusterName && other.Spec.ID == osd.Spec.ID {
+			return fmt.Errorf("spec.id: osd %d is already used by %s", osd.Spec.ID, other.GetName())
+		}
+	}
+
+	return nil
+}
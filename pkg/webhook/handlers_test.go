@@ -0,0 +1,158 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/PolarGeospatialCenter/ceph-operator/pkg/apis"
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestValidateUpdate(t *testing.T) {
+	// An even monitor count, written before validation rejected it
+	old := &cephv1beta1.CephMonCluster{}
+	old.Name = "test"
+	old.Spec.ClusterName = "test"
+	old.Spec.Count = 2
+	old.Spec.Image = cephv1beta1.ImageSpec{Registry: "ceph/daemon", Tag: "latest-mimic"}
+
+	statusOnly := old.DeepCopy()
+	statusOnly.Status.State = cephv1beta1.MonClusterInQuorum
+	statusOnly.SetFinalizers([]string{"ceph.k8s.pgc.umn.edu/test"})
+
+	specChange := old.DeepCopy()
+	specChange.Spec.Count = 4
+
+	fixed := old.DeepCopy()
+	fixed.Spec.Count = 3
+
+	tests := map[string]struct {
+		Object      *cephv1beta1.CephMonCluster
+		ExpectError bool
+	}{
+		"status-and-metadata": {Object: statusOnly},
+		"invalid-spec-change": {Object: specChange, ExpectError: true},
+		"valid-spec-change":   {Object: fixed},
+	}
+
+	raw, err := json.Marshal(old)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := &validatingHandler{newObject: func() cephv1beta1.Validator { return &cephv1beta1.CephMonCluster{} }}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := &admissionv1beta1.AdmissionRequest{
+				Operation: admissionv1beta1.Update,
+				OldObject: runtime.RawExtension{Raw: raw},
+			}
+
+			err := h.validate(context.TODO(), req, test.Object)
+			if (err != nil) != test.ExpectError {
+				t.Errorf("expected error %t, got %v", test.ExpectError, err)
+			}
+		})
+	}
+}
+
+func TestValidateV1alpha1(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+
+	tests := map[string]struct {
+		Count       int
+		ExpectError bool
+	}{
+		"valid":   {Count: 3},
+		"invalid": {Count: 2, ExpectError: true},
+	}
+
+	h := &validatingHandler{
+		newObject: func() cephv1beta1.Validator { return &cephv1beta1.CephMonCluster{} },
+		scheme:    scheme,
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			raw := []byte(fmt.Sprintf(`{
+				"apiVersion": "ceph.k8s.pgc.umn.edu/v1alpha1",
+				"kind": "CephMonCluster",
+				"metadata": {"name": "test", "namespace": "ceph"},
+				"spec": {"clusterName": "test", "count": %d, "image": {"registry": "ceph/daemon", "tag": "latest-mimic"}}
+			}`, test.Count))
+
+			obj, err := h.decode(raw)
+			if err != nil {
+				t.Fatalf("unable to decode: %v", err)
+			}
+			if _, ok := obj.(*cephv1beta1.CephMonCluster); !ok {
+				t.Fatalf("decoded a %T, expected a v1beta1 CephMonCluster", obj)
+			}
+
+			req := &admissionv1beta1.AdmissionRequest{Operation: admissionv1beta1.Create}
+			err = h.validate(context.TODO(), req, obj)
+			if (err != nil) != test.ExpectError {
+				t.Errorf("expected error %t, got %v", test.ExpectError, err)
+			}
+		})
+	}
+}
+
+func TestDefaultV1alpha1(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := apis.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+
+	raw := []byte(`{
+		"apiVersion": "ceph.k8s.pgc.umn.edu/v1alpha1",
+		"kind": "CephCluster",
+		"metadata": {"name": "test", "namespace": "ceph"},
+		"spec": {
+			"monImage": {"registry": "ceph/daemon", "tag": "latest-mimic"},
+			"osdImage": {"registry": "ceph/daemon", "tag": "latest-mimic"},
+			"mgrImage": {"registry": "ceph/daemon", "tag": "latest-mimic"},
+			"mdsImage": {"registry": "ceph/daemon", "tag": "latest-mimic"}
+		}
+	}`)
+
+	h := &defaultingHandler{scheme: scheme}
+	resp, err := h.defaultPatch(raw)
+	if err != nil {
+		t.Fatalf("unable to default: %v", err)
+	}
+
+	patched := map[string]interface{}{}
+	for _, op := range resp.Patches {
+		if op.Path == "/apiVersion" || op.Path == "/kind" {
+			t.Errorf("patch changes the type: %v", op)
+		}
+		patched[op.Path] = op.Value
+	}
+
+	fsid, _ := patched["/spec/fsid"].(string)
+	if fsid == "" {
+		t.Fatalf("fsid was not defaulted: %v", resp.Patches)
+	}
+	if patched["/spec/monServiceName"] != "test-mon" {
+		t.Errorf("expected monServiceName test-mon, got %v", patched["/spec/monServiceName"])
+	}
+
+	// The defaulted object passes validation
+	cluster := &cephv1beta1.CephCluster{}
+	if _, err := decodeVersioned(scheme, raw, cluster); err != nil {
+		t.Fatalf("unable to decode: %v", err)
+	}
+	cluster.Spec.Fsid = fsid
+	cluster.Spec.MonServiceName = "test-mon"
+	cluster.Spec.ClusterDomain, _ = patched["/spec/clusterDomain"].(string)
+	if err := cluster.Validate(); err != nil {
+		t.Errorf("defaulted cluster is invalid: %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package webhook
+
+import (
+	cephv1alpha1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1alpha1"
+	cephv1beta1 "github.com/PolarGeospatialCenter/ceph-operator/pkg/apis/ceph/v1beta1"
+
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission/builder"
+)
+
+var log = logf.Log.WithName("webhook")
+
+const (
+	serverName     = "ceph-operator-admission-server"
+	serverPort     = 9443
+	certDir        = "/tmp/cert"
+	serviceName    = "ceph-operator-webhook"
+	certSecretName = "ceph-operator-webhook-cert"
+)
+
+// AddToManager adds a webhook server validating, defaulting and converting the ceph resources to the Manager.
+// namespace is the namespace the operator runs in, the webhook service and certificate are created there.
+func AddToManager(mgr manager.Manager, namespace string) error {
+	disableWebhookConfigInstaller := false
+	server, err := webhook.NewServer(serverName, certWaitingManager{mgr}, webhook.ServerOptions{
+		Port:                          serverPort,
+		CertDir:                       certDir,
+		DisableWebhookConfigInstaller: &disableWebhookConfigInstaller,
+		BootstrapOptions: &webhook.BootstrapOptions{
+			MutatingWebhookConfigName:   "ceph-operator-mutating",
+			ValidatingWebhookConfigName: "ceph-operator-validating",
+			Secret:                      &types.NamespacedName{Namespace: namespace, Name: certSecretName},
+			Service: &webhook.Service{
+				Name:      serviceName,
+				Namespace: namespace,
+				Selectors: map[string]string{"name": "ceph-operator"},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	webhooks := []webhook.Webhook{}
+
+	// The apiserver only calls webhooks for the api version their rules match, objects of every served version are
+	// defaulted and validated once converted to v1beta1
+	versions := []string{}
+	for _, gv := range []schema.GroupVersion{cephv1alpha1.SchemeGroupVersion, cephv1beta1.SchemeGroupVersion} {
+		versions = append(versions, gv.Version)
+	}
+
+	defaulting, err := builder.NewWebhookBuilder().
+		Name("default.cephclusters.ceph.k8s.pgc.umn.edu").
+		Path("/default-cephclusters").
+		Mutating().
+		Rules(admissionregistrationv1beta1.RuleWithOperations{
+			Operations: []admissionregistrationv1beta1.OperationType{admissionregistrationv1beta1.Create},
+			Rule: admissionregistrationv1beta1.Rule{
+				APIGroups:   []string{cephv1beta1.SchemeGroupVersion.Group},
+				APIVersions: versions,
+				Resources:   []string{"cephclusters"},
+			},
+		}).
+		Handlers(&defaultingHandler{scheme: mgr.GetScheme()}).
+		WithManager(mgr).
+		Build()
+	if err != nil {
+		return err
+	}
+	webhooks = append(webhooks, defaulting)
+
+	validators := map[string]func() cephv1beta1.Validator{
+		"cephclusters":       func() cephv1beta1.Validator { return &cephv1beta1.CephCluster{} },
+		"cephmonclusters":    func() cephv1beta1.Validator { return &cephv1beta1.CephMonCluster{} },
+		"cephmons":           func() cephv1beta1.Validator { return &cephv1beta1.CephMon{} },
+		"cephosds":           func() cephv1beta1.Validator { return &cephv1beta1.CephOsd{} },
+		"cephdaemonclusters": func() cephv1beta1.Validator { return &cephv1beta1.CephDaemonCluster{} },
+		"cephdaemons":        func() cephv1beta1.Validator { return &cephv1beta1.CephDaemon{} },
+	}
+
+	for resource, newObject := range validators {
+		validating, err := builder.NewWebhookBuilder().
+			Name(resource + ".ceph.k8s.pgc.umn.edu").
+			Path("/validate-" + resource).
+			Validating().
+			Rules(admissionregistrationv1beta1.RuleWithOperations{
+				Operations: []admissionregistrationv1beta1.OperationType{
+					admissionregistrationv1beta1.Create,
+					admissionregistrationv1beta1.Update,
+				},
+				Rule: admissionregistrationv1beta1.Rule{
+					APIGroups:   []string{cephv1beta1.SchemeGroupVersion.Group},
+					APIVersions: versions,
+					Resources:   []string{resource},
+				},
+			}).
+			Handlers(&validatingHandler{newObject: newObject, scheme: mgr.GetScheme()}).
+			WithManager(mgr).
+			Build()
+		if err != nil {
+			return err
+		}
+		webhooks = append(webhooks, validating)
+	}
+
+	log.Info("Registering admission webhooks", "Namespace", namespace)
+	err = server.Register(webhooks...)
+	if err != nil {
+		return err
+	}
+
+	crdNames := []string{}
+	for resource := range validators {
+		crdNames = append(crdNames, resource+"."+cephv1beta1.SchemeGroupVersion.Group)
+	}
+
+	log.Info("Registering conversion webhook", "Namespace", namespace)
+	server.Handle(conversionPath, &conversionHandler{scheme: mgr.GetScheme()})
+	return mgr.Add(&conversionConfigInjector{
+		client:   mgr.GetClient(),
+		crdNames: crdNames,
+		secret:   types.NamespacedName{Namespace: namespace, Name: certSecretName},
+		service:  types.NamespacedName{Namespace: namespace, Name: serviceName},
+	})
+}
+
+var _ admission.Handler = &defaultingHandler{}
+var _ admission.Handler = &validatingHandler{}